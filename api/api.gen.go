@@ -24,18 +24,62 @@ const (
 	TokenScopes = "Token.Scopes"
 )
 
+// AdminUser defines model for AdminUser.
+type AdminUser struct {
+	Email            string     `json:"email"`
+	EmailVerified    bool       `json:"emailVerified"`
+	Role             string     `json:"role"`
+	SuspendedAt      *time.Time `json:"suspendedAt,omitempty"`
+	SuspensionReason *string    `json:"suspensionReason,omitempty"`
+	TwoFactorEnabled bool       `json:"twoFactorEnabled"`
+	Username         string     `json:"username"`
+}
+
 // Article defines model for Article.
 type Article struct {
-	Author         Profile   `json:"author"`
-	Body           string    `json:"body"`
+	Author Profile `json:"author"`
+	Body   string  `json:"body"`
+
+	// BodyHtml The body rendered from Markdown to sanitized HTML. Headings have IDs to link to
+	BodyHtml       *string   `json:"bodyHtml,omitempty"`
 	CreatedAt      time.Time `json:"createdAt"`
 	Description    string    `json:"description"`
 	Favorited      bool      `json:"favorited"`
 	FavoritesCount int       `json:"favoritesCount"`
-	Slug           string    `json:"slug"`
-	TagList        []string  `json:"tagList"`
-	Title          string    `json:"title"`
-	UpdatedAt      time.Time `json:"updatedAt"`
+
+	// PublishAt When a scheduled article gets published
+	PublishAt *time.Time `json:"publishAt,omitempty"`
+	Slug      string     `json:"slug"`
+
+	// Status One of draft, scheduled, published or unlisted. Drafts and scheduled articles are only visible to their author, unlisted articles only to those who have the link
+	Status    string    `json:"status"`
+	TagList   []string  `json:"tagList"`
+	Title     string    `json:"title"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ArticleRevision defines model for ArticleRevision.
+type ArticleRevision struct {
+	Author      Profile   `json:"author"`
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Description string    `json:"description"`
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+}
+
+// ArticleRevisionSummary defines model for ArticleRevisionSummary.
+type ArticleRevisionSummary struct {
+	Author    Profile   `json:"author"`
+	CreatedAt time.Time `json:"createdAt"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+}
+
+// AuthProvider defines model for AuthProvider.
+type AuthProvider struct {
+	DisplayName string `json:"displayName"`
+	Name        string `json:"name"`
 }
 
 // Comment defines model for Comment.
@@ -47,6 +91,59 @@ type Comment struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// DataExport defines model for DataExport.
+type DataExport struct {
+	Articles   []Article         `json:"articles"`
+	Comments   []ExportedComment `json:"comments"`
+	ExportedAt time.Time         `json:"exportedAt"`
+
+	// Favorites Slugs of the articles the user favorited
+	Favorites []string `json:"favorites"`
+
+	// FollowedTags Tags the user follows
+	FollowedTags []string `json:"followedTags"`
+
+	// Following Usernames of the users the user follows
+	Following      []string        `json:"following"`
+	PersonalTokens []PersonalToken `json:"personalTokens"`
+	User           ExportedAccount `json:"user"`
+}
+
+// DiffLine defines model for DiffLine.
+type DiffLine struct {
+	// Op equal, insert or delete
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// EmailVerification defines model for EmailVerification.
+type EmailVerification struct {
+	Token string `json:"token"`
+}
+
+// ExportedAccount defines model for ExportedAccount.
+type ExportedAccount struct {
+	Bio           string `json:"bio"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"emailVerified"`
+
+	// Identities Names of the linked external identity providers
+	Identities       []string `json:"identities"`
+	Image            string   `json:"image"`
+	Role             string   `json:"role"`
+	TwoFactorEnabled bool     `json:"twoFactorEnabled"`
+	Username         string   `json:"username"`
+}
+
+// ExportedComment defines model for ExportedComment.
+type ExportedComment struct {
+	ArticleSlug string    `json:"articleSlug"`
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Id          int       `json:"id"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
 // GenericErrorModel defines model for GenericErrorModel.
 type GenericErrorModel struct {
 	Errors struct {
@@ -54,6 +151,21 @@ type GenericErrorModel struct {
 	} `json:"errors"`
 }
 
+// Lockout defines model for Lockout.
+type Lockout struct {
+	Email       string    `json:"email"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+// LoginAttempt defines model for LoginAttempt.
+type LoginAttempt struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Email     string    `json:"email"`
+	Ip        string    `json:"ip"`
+	Reason    string    `json:"reason"`
+}
+
 // LoginUser defines model for LoginUser.
 type LoginUser struct {
 	Email    string `json:"email"`
@@ -62,10 +174,18 @@ type LoginUser struct {
 
 // NewArticle defines model for NewArticle.
 type NewArticle struct {
-	Body        string    `json:"body"`
-	Description string    `json:"description"`
-	TagList     *[]string `json:"tagList,omitempty"`
-	Title       string    `json:"title"`
+	Body        string `json:"body"`
+	Description string `json:"description"`
+
+	// PublishAt When to publish the article, required for and implying the status scheduled
+	PublishAt *time.Time `json:"publishAt,omitempty"`
+
+	// Status One of draft, scheduled, published (default) or unlisted
+	Status *string `json:"status,omitempty"`
+
+	// TagList Up to 10 tags of up to 32 characters, lowercased with whitespace collapsed
+	TagList *[]string `json:"tagList,omitempty"`
+	Title   string    `json:"title"`
 }
 
 // NewComment defines model for NewComment.
@@ -73,6 +193,16 @@ type NewComment struct {
 	Body string `json:"body"`
 }
 
+// NewPersonalToken defines model for NewPersonalToken.
+type NewPersonalToken struct {
+	// ExpiresAt Optional expiry, tokens don't expire by default
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Name      string     `json:"name"`
+
+	// Scopes One or more of user:read, articles:write, comments:write, favorites:write and profiles:write
+	Scopes []string `json:"scopes"`
+}
+
 // NewUser defines model for NewUser.
 type NewUser struct {
 	Email    string `json:"email"`
@@ -80,19 +210,110 @@ type NewUser struct {
 	Username string `json:"username"`
 }
 
+// PasswordConfirmation defines model for PasswordConfirmation.
+type PasswordConfirmation struct {
+	Password string `json:"password"`
+}
+
+// PasswordReset defines model for PasswordReset.
+type PasswordReset struct {
+	Email string `json:"email"`
+}
+
+// PasswordResetConfirmation defines model for PasswordResetConfirmation.
+type PasswordResetConfirmation struct {
+	Password string `json:"password"`
+	Token    string `json:"token"`
+}
+
+// PersonalToken defines model for PersonalToken.
+type PersonalToken struct {
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	Id         string     `json:"id"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+}
+
 // Profile defines model for Profile.
 type Profile struct {
-	Bio       string `json:"bio"`
+	Bio string `json:"bio"`
+
+	// FollowedTags Tags the user follows. Only returned for single profiles
+	FollowedTags *[]string `json:"followedTags,omitempty"`
+	Following    bool      `json:"following"`
+	Image        string    `json:"image"`
+	Username     string    `json:"username"`
+}
+
+// RevisionDiff defines model for RevisionDiff.
+type RevisionDiff struct {
+	Body        []DiffLine `json:"body"`
+	Description []DiffLine `json:"description"`
+	From        int        `json:"from"`
+	Title       []DiffLine `json:"title"`
+	To          int        `json:"to"`
+}
+
+// RoleAssignment defines model for RoleAssignment.
+type RoleAssignment struct {
+	// Role One of user, moderator and admin
+	Role string `json:"role"`
+}
+
+// Suspension defines model for Suspension.
+type Suspension struct {
+	// Reason Optional note for other admins
+	Reason *string `json:"reason,omitempty"`
+}
+
+// TagCount defines model for TagCount.
+type TagCount struct {
+	// ArticlesCount Number of published articles with the tag
+	ArticlesCount int    `json:"articlesCount"`
+	Tag           string `json:"tag"`
+}
+
+// TagFollow defines model for TagFollow.
+type TagFollow struct {
 	Following bool   `json:"following"`
-	Image     string `json:"image"`
-	Username  string `json:"username"`
+	Tag       string `json:"tag"`
+}
+
+// TwoFactorChallenge defines model for TwoFactorChallenge.
+type TwoFactorChallenge struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	Token     string    `json:"token"`
+}
+
+// TwoFactorCode defines model for TwoFactorCode.
+type TwoFactorCode struct {
+	Code string `json:"code"`
+}
+
+// TwoFactorLogin defines model for TwoFactorLogin.
+type TwoFactorLogin struct {
+	Challenge string `json:"challenge"`
+
+	// Code A code from the authenticator app or a recovery code
+	Code string `json:"code"`
 }
 
 // UpdateArticle defines model for UpdateArticle.
 type UpdateArticle struct {
 	Body        *string `json:"body,omitempty"`
 	Description *string `json:"description,omitempty"`
-	Title       *string `json:"title,omitempty"`
+
+	// PublishAt When to publish the article, required for and implying the status scheduled
+	PublishAt *time.Time `json:"publishAt,omitempty"`
+
+	// Status One of draft, scheduled, published or unlisted
+	Status *string `json:"status,omitempty"`
+
+	// TagList Replaces the tags of the article, normalized like those of new articles
+	TagList *[]string `json:"tagList,omitempty"`
+	Title   *string   `json:"title,omitempty"`
 }
 
 // UpdateUser defines model for UpdateUser.
@@ -119,21 +340,50 @@ type LimitParam = int
 // OffsetParam defines model for offsetParam.
 type OffsetParam = int
 
+// AdminUsersResponse defines model for AdminUsersResponse.
+type AdminUsersResponse struct {
+	Users      []AdminUser `json:"users"`
+	UsersCount int         `json:"usersCount"`
+}
+
+// ArticleRevisionsResponse defines model for ArticleRevisionsResponse.
+type ArticleRevisionsResponse struct {
+	Revisions      []ArticleRevisionSummary `json:"revisions"`
+	RevisionsCount int                      `json:"revisionsCount"`
+}
+
+// AuthProvidersResponse defines model for AuthProvidersResponse.
+type AuthProvidersResponse struct {
+	Providers []AuthProvider `json:"providers"`
+}
+
 // GenericError defines model for GenericError.
 type GenericError = GenericErrorModel
 
+// LockoutsResponse defines model for LockoutsResponse.
+type LockoutsResponse struct {
+	Lockouts []Lockout `json:"lockouts"`
+}
+
+// LoginAttemptsResponse defines model for LoginAttemptsResponse.
+type LoginAttemptsResponse struct {
+	Attempts []LoginAttempt `json:"attempts"`
+}
+
 // MultipleArticlesResponse defines model for MultipleArticlesResponse.
 type MultipleArticlesResponse struct {
 	Articles []struct {
-		Author         Profile   `json:"author"`
-		CreatedAt      time.Time `json:"createdAt"`
-		Description    string    `json:"description"`
-		Favorited      bool      `json:"favorited"`
-		FavoritesCount int       `json:"favoritesCount"`
-		Slug           string    `json:"slug"`
-		TagList        []string  `json:"tagList"`
-		Title          string    `json:"title"`
-		UpdatedAt      time.Time `json:"updatedAt"`
+		Author         Profile    `json:"author"`
+		CreatedAt      time.Time  `json:"createdAt"`
+		Description    string     `json:"description"`
+		Favorited      bool       `json:"favorited"`
+		FavoritesCount int        `json:"favoritesCount"`
+		PublishAt      *time.Time `json:"publishAt,omitempty"`
+		Slug           string     `json:"slug"`
+		Status         string     `json:"status"`
+		TagList        []string   `json:"tagList"`
+		Title          string     `json:"title"`
+		UpdatedAt      time.Time  `json:"updatedAt"`
 	} `json:"articles"`
 	ArticlesCount int `json:"articlesCount"`
 }
@@ -143,24 +393,68 @@ type MultipleCommentsResponse struct {
 	Comments []Comment `json:"comments"`
 }
 
+// NewPersonalTokenResponse defines model for NewPersonalTokenResponse.
+type NewPersonalTokenResponse struct {
+	Secret string        `json:"secret"`
+	Token  PersonalToken `json:"token"`
+}
+
+// PersonalTokensResponse defines model for PersonalTokensResponse.
+type PersonalTokensResponse struct {
+	Tokens []PersonalToken `json:"tokens"`
+}
+
 // ProfileResponse defines model for ProfileResponse.
 type ProfileResponse struct {
 	Profile Profile `json:"profile"`
 }
 
+// RecoveryCodesResponse defines model for RecoveryCodesResponse.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// RevisionDiffResponse defines model for RevisionDiffResponse.
+type RevisionDiffResponse struct {
+	Diff RevisionDiff `json:"diff"`
+}
+
 // SingleArticleResponse defines model for SingleArticleResponse.
 type SingleArticleResponse struct {
 	Article Article `json:"article"`
 }
 
+// SingleArticleRevisionResponse defines model for SingleArticleRevisionResponse.
+type SingleArticleRevisionResponse struct {
+	Revision ArticleRevision `json:"revision"`
+}
+
 // SingleCommentResponse defines model for SingleCommentResponse.
 type SingleCommentResponse struct {
 	Comment Comment `json:"comment"`
 }
 
+// TagFollowResponse defines model for TagFollowResponse.
+type TagFollowResponse struct {
+	Tag TagFollow `json:"tag"`
+}
+
 // TagsResponse defines model for TagsResponse.
 type TagsResponse struct {
-	Tags []string `json:"tags"`
+	// TagCounts The tags with their article counts, in the same order. Only returned when requested with counts
+	TagCounts *[]TagCount `json:"tagCounts,omitempty"`
+	Tags      []string    `json:"tags"`
+}
+
+// TwoFactorChallengeResponse defines model for TwoFactorChallengeResponse.
+type TwoFactorChallengeResponse struct {
+	TwoFactor TwoFactorChallenge `json:"twoFactor"`
+}
+
+// TwoFactorEnrollmentResponse defines model for TwoFactorEnrollmentResponse.
+type TwoFactorEnrollmentResponse struct {
+	OtpauthUri string `json:"otpauthUri"`
+	Secret     string `json:"secret"`
 }
 
 // UserResponse defines model for UserResponse.
@@ -168,6 +462,11 @@ type UserResponse struct {
 	User User `json:"user"`
 }
 
+// EmailVerificationRequest defines model for EmailVerificationRequest.
+type EmailVerificationRequest struct {
+	User EmailVerification `json:"user"`
+}
+
 // LoginUserRequest defines model for LoginUserRequest.
 type LoginUserRequest struct {
 	User LoginUser `json:"user"`
@@ -183,11 +482,51 @@ type NewCommentRequest struct {
 	Comment NewComment `json:"comment"`
 }
 
+// NewPersonalTokenRequest defines model for NewPersonalTokenRequest.
+type NewPersonalTokenRequest struct {
+	Token NewPersonalToken `json:"token"`
+}
+
 // NewUserRequest defines model for NewUserRequest.
 type NewUserRequest struct {
 	User NewUser `json:"user"`
 }
 
+// PasswordConfirmationRequest defines model for PasswordConfirmationRequest.
+type PasswordConfirmationRequest struct {
+	User PasswordConfirmation `json:"user"`
+}
+
+// PasswordResetConfirmationRequest defines model for PasswordResetConfirmationRequest.
+type PasswordResetConfirmationRequest struct {
+	User PasswordResetConfirmation `json:"user"`
+}
+
+// PasswordResetRequest defines model for PasswordResetRequest.
+type PasswordResetRequest struct {
+	User PasswordReset `json:"user"`
+}
+
+// RoleAssignmentRequest defines model for RoleAssignmentRequest.
+type RoleAssignmentRequest struct {
+	User RoleAssignment `json:"user"`
+}
+
+// SuspensionRequest defines model for SuspensionRequest.
+type SuspensionRequest struct {
+	Suspension Suspension `json:"suspension"`
+}
+
+// TwoFactorCodeRequest defines model for TwoFactorCodeRequest.
+type TwoFactorCodeRequest struct {
+	TwoFactor TwoFactorCode `json:"twoFactor"`
+}
+
+// TwoFactorLoginRequest defines model for TwoFactorLoginRequest.
+type TwoFactorLoginRequest struct {
+	TwoFactor TwoFactorLogin `json:"twoFactor"`
+}
+
 // UpdateArticleRequest defines model for UpdateArticleRequest.
 type UpdateArticleRequest struct {
 	Article UpdateArticle `json:"article"`
@@ -198,6 +537,31 @@ type UpdateUserRequest struct {
 	User UpdateUser `json:"user"`
 }
 
+// ListUsersParams defines parameters for ListUsers.
+type ListUsersParams struct {
+	// Search Only users whose username or email contains the text
+	Search *string `form:"search,omitempty" json:"search,omitempty"`
+
+	// Suspended Only suspended or only active users
+	Suspended *bool `form:"suspended,omitempty" json:"suspended,omitempty"`
+
+	// Offset The number of items to skip before starting to collect the result set.
+	Offset *OffsetParam `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Limit The numbers of items to return.
+	Limit *LimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// SetUserRoleJSONBody defines parameters for SetUserRole.
+type SetUserRoleJSONBody struct {
+	User RoleAssignment `json:"user"`
+}
+
+// SuspendUserJSONBody defines parameters for SuspendUser.
+type SuspendUserJSONBody struct {
+	Suspension Suspension `json:"suspension"`
+}
+
 // GetArticlesParams defines parameters for GetArticles.
 type GetArticlesParams struct {
 	// Tag Filter by tag
@@ -240,11 +604,76 @@ type CreateArticleCommentJSONBody struct {
 	Comment NewComment `json:"comment"`
 }
 
+// GetArticleRevisionDiffParams defines parameters for GetArticleRevisionDiff.
+type GetArticleRevisionDiffParams struct {
+	// From Number of the revision to compare with, by default the previous one. 0 compares with an empty article
+	From *int `form:"from,omitempty" json:"from,omitempty"`
+}
+
+// OidcCallbackParams defines parameters for OidcCallback.
+type OidcCallbackParams struct {
+	// Code Authorization code issued by the provider
+	Code *string `form:"code,omitempty" json:"code,omitempty"`
+
+	// State State passed to the provider when the login started
+	State *string `form:"state,omitempty" json:"state,omitempty"`
+
+	// Error Error reported by the provider
+	Error *string `form:"error,omitempty" json:"error,omitempty"`
+}
+
+// GetTagsParams defines parameters for GetTags.
+type GetTagsParams struct {
+	// Order Either count (default), most used first, or recent, most recently active first
+	Order *string `form:"order,omitempty" json:"order,omitempty"`
+
+	// Limit The maximum number of tags to return, all by default
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Counts Also return the number of articles per tag
+	Counts *bool `form:"counts,omitempty" json:"counts,omitempty"`
+}
+
+// DeleteCurrentUserJSONBody defines parameters for DeleteCurrentUser.
+type DeleteCurrentUserJSONBody struct {
+	User PasswordConfirmation `json:"user"`
+}
+
 // UpdateCurrentUserJSONBody defines parameters for UpdateCurrentUser.
 type UpdateCurrentUserJSONBody struct {
 	User UpdateUser `json:"user"`
 }
 
+// GetUserDraftsParams defines parameters for GetUserDrafts.
+type GetUserDraftsParams struct {
+	// Offset The number of items to skip before starting to collect the result set.
+	Offset *OffsetParam `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Limit The numbers of items to return.
+	Limit *LimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetDataExportParams defines parameters for GetDataExport.
+type GetDataExportParams struct {
+	// Format Either json (default) or zip
+	Format *string `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// CreatePersonalTokenJSONBody defines parameters for CreatePersonalToken.
+type CreatePersonalTokenJSONBody struct {
+	Token NewPersonalToken `json:"token"`
+}
+
+// DisableTwoFactorJSONBody defines parameters for DisableTwoFactor.
+type DisableTwoFactorJSONBody struct {
+	User PasswordConfirmation `json:"user"`
+}
+
+// ConfirmTwoFactorJSONBody defines parameters for ConfirmTwoFactor.
+type ConfirmTwoFactorJSONBody struct {
+	TwoFactor TwoFactorCode `json:"twoFactor"`
+}
+
 // CreateUserJSONBody defines parameters for CreateUser.
 type CreateUserJSONBody struct {
 	User NewUser `json:"user"`
@@ -255,6 +684,32 @@ type LoginJSONBody struct {
 	User LoginUser `json:"user"`
 }
 
+// LoginTwoFactorJSONBody defines parameters for LoginTwoFactor.
+type LoginTwoFactorJSONBody struct {
+	TwoFactor TwoFactorLogin `json:"twoFactor"`
+}
+
+// RequestPasswordResetJSONBody defines parameters for RequestPasswordReset.
+type RequestPasswordResetJSONBody struct {
+	User PasswordReset `json:"user"`
+}
+
+// ConfirmPasswordResetJSONBody defines parameters for ConfirmPasswordReset.
+type ConfirmPasswordResetJSONBody struct {
+	User PasswordResetConfirmation `json:"user"`
+}
+
+// VerifyEmailJSONBody defines parameters for VerifyEmail.
+type VerifyEmailJSONBody struct {
+	User EmailVerification `json:"user"`
+}
+
+// SetUserRoleJSONRequestBody defines body for SetUserRole for application/json ContentType.
+type SetUserRoleJSONRequestBody SetUserRoleJSONBody
+
+// SuspendUserJSONRequestBody defines body for SuspendUser for application/json ContentType.
+type SuspendUserJSONRequestBody SuspendUserJSONBody
+
 // CreateArticleJSONRequestBody defines body for CreateArticle for application/json ContentType.
 type CreateArticleJSONRequestBody CreateArticleJSONBody
 
@@ -264,17 +719,68 @@ type UpdateArticleJSONRequestBody UpdateArticleJSONBody
 // CreateArticleCommentJSONRequestBody defines body for CreateArticleComment for application/json ContentType.
 type CreateArticleCommentJSONRequestBody CreateArticleCommentJSONBody
 
+// DeleteCurrentUserJSONRequestBody defines body for DeleteCurrentUser for application/json ContentType.
+type DeleteCurrentUserJSONRequestBody DeleteCurrentUserJSONBody
+
 // UpdateCurrentUserJSONRequestBody defines body for UpdateCurrentUser for application/json ContentType.
 type UpdateCurrentUserJSONRequestBody UpdateCurrentUserJSONBody
 
+// CreatePersonalTokenJSONRequestBody defines body for CreatePersonalToken for application/json ContentType.
+type CreatePersonalTokenJSONRequestBody CreatePersonalTokenJSONBody
+
+// DisableTwoFactorJSONRequestBody defines body for DisableTwoFactor for application/json ContentType.
+type DisableTwoFactorJSONRequestBody DisableTwoFactorJSONBody
+
+// ConfirmTwoFactorJSONRequestBody defines body for ConfirmTwoFactor for application/json ContentType.
+type ConfirmTwoFactorJSONRequestBody ConfirmTwoFactorJSONBody
+
 // CreateUserJSONRequestBody defines body for CreateUser for application/json ContentType.
 type CreateUserJSONRequestBody CreateUserJSONBody
 
 // LoginJSONRequestBody defines body for Login for application/json ContentType.
 type LoginJSONRequestBody LoginJSONBody
 
+// LoginTwoFactorJSONRequestBody defines body for LoginTwoFactor for application/json ContentType.
+type LoginTwoFactorJSONRequestBody LoginTwoFactorJSONBody
+
+// RequestPasswordResetJSONRequestBody defines body for RequestPasswordReset for application/json ContentType.
+type RequestPasswordResetJSONRequestBody RequestPasswordResetJSONBody
+
+// ConfirmPasswordResetJSONRequestBody defines body for ConfirmPasswordReset for application/json ContentType.
+type ConfirmPasswordResetJSONRequestBody ConfirmPasswordResetJSONBody
+
+// VerifyEmailJSONRequestBody defines body for VerifyEmail for application/json ContentType.
+type VerifyEmailJSONRequestBody VerifyEmailJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Get locked accounts
+	// (GET /admin/lockouts)
+	GetLockouts(w http.ResponseWriter, r *http.Request)
+	// Unlock an account
+	// (DELETE /admin/lockouts/{email})
+	DeleteLockout(w http.ResponseWriter, r *http.Request, email string)
+	// Get failed login attempts
+	// (GET /admin/login-attempts)
+	GetLoginAttempts(w http.ResponseWriter, r *http.Request)
+	// Get users
+	// (GET /admin/users)
+	ListUsers(w http.ResponseWriter, r *http.Request, params ListUsersParams)
+	// Delete a user
+	// (DELETE /admin/users/{username})
+	DeleteUser(w http.ResponseWriter, r *http.Request, username string)
+	// Force a password reset
+	// (POST /admin/users/{username}/password-reset)
+	ForcePasswordReset(w http.ResponseWriter, r *http.Request, username string)
+	// Change the role of a user
+	// (PUT /admin/users/{username}/role)
+	SetUserRole(w http.ResponseWriter, r *http.Request, username string)
+	// Lift the suspension of a user
+	// (DELETE /admin/users/{username}/suspension)
+	UnsuspendUser(w http.ResponseWriter, r *http.Request, username string)
+	// Suspend a user
+	// (POST /admin/users/{username}/suspension)
+	SuspendUser(w http.ResponseWriter, r *http.Request, username string)
 	// Get recent articles globally
 	// (GET /articles)
 	GetArticles(w http.ResponseWriter, r *http.Request, params GetArticlesParams)
@@ -308,6 +814,27 @@ type ServerInterface interface {
 	// Favorite an article
 	// (POST /articles/{slug}/favorite)
 	CreateArticleFavorite(w http.ResponseWriter, r *http.Request, slug string)
+	// List the revisions of an article
+	// (GET /articles/{slug}/revisions)
+	ListArticleRevisions(w http.ResponseWriter, r *http.Request, slug string)
+	// Get a revision of an article
+	// (GET /articles/{slug}/revisions/{number})
+	GetArticleRevision(w http.ResponseWriter, r *http.Request, slug string, number int)
+	// Compare two revisions of an article
+	// (GET /articles/{slug}/revisions/{number}/diff)
+	GetArticleRevisionDiff(w http.ResponseWriter, r *http.Request, slug string, number int, params GetArticleRevisionDiffParams)
+	// Restore a revision of an article
+	// (POST /articles/{slug}/revisions/{number}/restore)
+	RestoreArticleRevision(w http.ResponseWriter, r *http.Request, slug string, number int)
+	// List external identity providers
+	// (GET /auth/providers)
+	ListAuthProviders(w http.ResponseWriter, r *http.Request)
+	// Complete a sign in with an external identity provider
+	// (GET /auth/{provider}/callback)
+	OidcCallback(w http.ResponseWriter, r *http.Request, provider string, params OidcCallbackParams)
+	// Sign in with an external identity provider
+	// (GET /auth/{provider}/login)
+	OidcLogin(w http.ResponseWriter, r *http.Request, provider string)
 	// Get a profile
 	// (GET /profiles/{username})
 	GetProfileByUsername(w http.ResponseWriter, r *http.Request, username string)
@@ -319,25 +846,130 @@ type ServerInterface interface {
 	FollowUserByUsername(w http.ResponseWriter, r *http.Request, username string)
 	// Get tags
 	// (GET /tags)
-	GetTags(w http.ResponseWriter, r *http.Request)
+	GetTags(w http.ResponseWriter, r *http.Request, params GetTagsParams)
+	// Unfollow a tag
+	// (DELETE /tags/{tag}/follow)
+	UnfollowTag(w http.ResponseWriter, r *http.Request, tag string)
+	// Follow a tag
+	// (POST /tags/{tag}/follow)
+	FollowTag(w http.ResponseWriter, r *http.Request, tag string)
+	// Delete current user
+	// (DELETE /user)
+	DeleteCurrentUser(w http.ResponseWriter, r *http.Request)
 	// Get current user
 	// (GET /user)
 	GetCurrentUser(w http.ResponseWriter, r *http.Request)
 	// Update current user
 	// (PUT /user)
 	UpdateCurrentUser(w http.ResponseWriter, r *http.Request)
+	// Get the drafts of the current user
+	// (GET /user/drafts)
+	GetUserDrafts(w http.ResponseWriter, r *http.Request, params GetUserDraftsParams)
+	// Export current user's data
+	// (GET /user/export)
+	GetDataExport(w http.ResponseWriter, r *http.Request, params GetDataExportParams)
+	// List personal access tokens
+	// (GET /user/tokens)
+	ListPersonalTokens(w http.ResponseWriter, r *http.Request)
+	// Create a personal access token
+	// (POST /user/tokens)
+	CreatePersonalToken(w http.ResponseWriter, r *http.Request)
+	// Revoke a personal access token
+	// (DELETE /user/tokens/{id})
+	DeletePersonalToken(w http.ResponseWriter, r *http.Request, id string)
+	// Disable two-factor authentication
+	// (DELETE /user/two-factor)
+	DisableTwoFactor(w http.ResponseWriter, r *http.Request)
+	// Start two-factor enrollment
+	// (POST /user/two-factor)
+	EnrollTwoFactor(w http.ResponseWriter, r *http.Request)
+	// Confirm two-factor enrollment
+	// (POST /user/two-factor/verify)
+	ConfirmTwoFactor(w http.ResponseWriter, r *http.Request)
+	// Resend the verification email
+	// (POST /user/verify-email)
+	ResendVerificationEmail(w http.ResponseWriter, r *http.Request)
 
 	// (POST /users)
 	CreateUser(w http.ResponseWriter, r *http.Request)
 	// Existing user login
 	// (POST /users/login)
 	Login(w http.ResponseWriter, r *http.Request)
+	// Complete a two-factor login
+	// (POST /users/login/two-factor)
+	LoginTwoFactor(w http.ResponseWriter, r *http.Request)
+	// Log out
+	// (POST /users/logout)
+	Logout(w http.ResponseWriter, r *http.Request)
+	// Request a password reset
+	// (POST /users/password-reset)
+	RequestPasswordReset(w http.ResponseWriter, r *http.Request)
+	// Reset the password
+	// (POST /users/password-reset/confirm)
+	ConfirmPasswordReset(w http.ResponseWriter, r *http.Request)
+	// Verify an email address
+	// (POST /users/verify-email)
+	VerifyEmail(w http.ResponseWriter, r *http.Request)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// Get locked accounts
+// (GET /admin/lockouts)
+func (_ Unimplemented) GetLockouts(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Unlock an account
+// (DELETE /admin/lockouts/{email})
+func (_ Unimplemented) DeleteLockout(w http.ResponseWriter, r *http.Request, email string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get failed login attempts
+// (GET /admin/login-attempts)
+func (_ Unimplemented) GetLoginAttempts(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get users
+// (GET /admin/users)
+func (_ Unimplemented) ListUsers(w http.ResponseWriter, r *http.Request, params ListUsersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Delete a user
+// (DELETE /admin/users/{username})
+func (_ Unimplemented) DeleteUser(w http.ResponseWriter, r *http.Request, username string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Force a password reset
+// (POST /admin/users/{username}/password-reset)
+func (_ Unimplemented) ForcePasswordReset(w http.ResponseWriter, r *http.Request, username string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Change the role of a user
+// (PUT /admin/users/{username}/role)
+func (_ Unimplemented) SetUserRole(w http.ResponseWriter, r *http.Request, username string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Lift the suspension of a user
+// (DELETE /admin/users/{username}/suspension)
+func (_ Unimplemented) UnsuspendUser(w http.ResponseWriter, r *http.Request, username string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Suspend a user
+// (POST /admin/users/{username}/suspension)
+func (_ Unimplemented) SuspendUser(w http.ResponseWriter, r *http.Request, username string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Get recent articles globally
 // (GET /articles)
 func (_ Unimplemented) GetArticles(w http.ResponseWriter, r *http.Request, params GetArticlesParams) {
@@ -404,6 +1036,48 @@ func (_ Unimplemented) CreateArticleFavorite(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// List the revisions of an article
+// (GET /articles/{slug}/revisions)
+func (_ Unimplemented) ListArticleRevisions(w http.ResponseWriter, r *http.Request, slug string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get a revision of an article
+// (GET /articles/{slug}/revisions/{number})
+func (_ Unimplemented) GetArticleRevision(w http.ResponseWriter, r *http.Request, slug string, number int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Compare two revisions of an article
+// (GET /articles/{slug}/revisions/{number}/diff)
+func (_ Unimplemented) GetArticleRevisionDiff(w http.ResponseWriter, r *http.Request, slug string, number int, params GetArticleRevisionDiffParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Restore a revision of an article
+// (POST /articles/{slug}/revisions/{number}/restore)
+func (_ Unimplemented) RestoreArticleRevision(w http.ResponseWriter, r *http.Request, slug string, number int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List external identity providers
+// (GET /auth/providers)
+func (_ Unimplemented) ListAuthProviders(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Complete a sign in with an external identity provider
+// (GET /auth/{provider}/callback)
+func (_ Unimplemented) OidcCallback(w http.ResponseWriter, r *http.Request, provider string, params OidcCallbackParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Sign in with an external identity provider
+// (GET /auth/{provider}/login)
+func (_ Unimplemented) OidcLogin(w http.ResponseWriter, r *http.Request, provider string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Get a profile
 // (GET /profiles/{username})
 func (_ Unimplemented) GetProfileByUsername(w http.ResponseWriter, r *http.Request, username string) {
@@ -424,7 +1098,25 @@ func (_ Unimplemented) FollowUserByUsername(w http.ResponseWriter, r *http.Reque
 
 // Get tags
 // (GET /tags)
-func (_ Unimplemented) GetTags(w http.ResponseWriter, r *http.Request) {
+func (_ Unimplemented) GetTags(w http.ResponseWriter, r *http.Request, params GetTagsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Unfollow a tag
+// (DELETE /tags/{tag}/follow)
+func (_ Unimplemented) UnfollowTag(w http.ResponseWriter, r *http.Request, tag string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Follow a tag
+// (POST /tags/{tag}/follow)
+func (_ Unimplemented) FollowTag(w http.ResponseWriter, r *http.Request, tag string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Delete current user
+// (DELETE /user)
+func (_ Unimplemented) DeleteCurrentUser(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
@@ -440,55 +1132,208 @@ func (_ Unimplemented) UpdateCurrentUser(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /users)
-func (_ Unimplemented) CreateUser(w http.ResponseWriter, r *http.Request) {
+// Get the drafts of the current user
+// (GET /user/drafts)
+func (_ Unimplemented) GetUserDrafts(w http.ResponseWriter, r *http.Request, params GetUserDraftsParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Existing user login
-// (POST /users/login)
-func (_ Unimplemented) Login(w http.ResponseWriter, r *http.Request) {
+// Export current user's data
+// (GET /user/export)
+func (_ Unimplemented) GetDataExport(w http.ResponseWriter, r *http.Request, params GetDataExportParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+// List personal access tokens
+// (GET /user/tokens)
+func (_ Unimplemented) ListPersonalTokens(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
+// Create a personal access token
+// (POST /user/tokens)
+func (_ Unimplemented) CreatePersonalToken(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
 
-// GetArticles operation middleware
-func (siw *ServerInterfaceWrapper) GetArticles(w http.ResponseWriter, r *http.Request) {
+// Revoke a personal access token
+// (DELETE /user/tokens/{id})
+func (_ Unimplemented) DeletePersonalToken(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
 
-	var err error
+// Disable two-factor authentication
+// (DELETE /user/two-factor)
+func (_ Unimplemented) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetArticlesParams
+// Start two-factor enrollment
+// (POST /user/two-factor)
+func (_ Unimplemented) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
 
-	// ------------- Optional query parameter "tag" -------------
+// Confirm two-factor enrollment
+// (POST /user/two-factor/verify)
+func (_ Unimplemented) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
 
-	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+// Resend the verification email
+// (POST /user/verify-email)
+func (_ Unimplemented) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /users)
+func (_ Unimplemented) CreateUser(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Existing user login
+// (POST /users/login)
+func (_ Unimplemented) Login(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Complete a two-factor login
+// (POST /users/login/two-factor)
+func (_ Unimplemented) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Log out
+// (POST /users/logout)
+func (_ Unimplemented) Logout(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Request a password reset
+// (POST /users/password-reset)
+func (_ Unimplemented) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Reset the password
+// (POST /users/password-reset/confirm)
+func (_ Unimplemented) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Verify an email address
+// (POST /users/verify-email)
+func (_ Unimplemented) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetLockouts operation middleware
+func (siw *ServerInterfaceWrapper) GetLockouts(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetLockouts(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteLockout operation middleware
+func (siw *ServerInterfaceWrapper) DeleteLockout(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "email" -------------
+	var email string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "email", chi.URLParam(r, "email"), &email, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "email", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "author" -------------
+	ctx := r.Context()
 
-	err = runtime.BindQueryParameter("form", true, false, "author", r.URL.Query(), &params.Author)
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteLockout(w, r, email)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetLoginAttempts operation middleware
+func (siw *ServerInterfaceWrapper) GetLoginAttempts(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetLoginAttempts(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListUsers operation middleware
+func (siw *ServerInterfaceWrapper) ListUsers(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListUsersParams
+
+	// ------------- Optional query parameter "search" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "search", r.URL.Query(), &params.Search)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "search", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "favorited" -------------
+	// ------------- Optional query parameter "suspended" -------------
 
-	err = runtime.BindQueryParameter("form", true, false, "favorited", r.URL.Query(), &params.Favorited)
+	err = runtime.BindQueryParameter("form", true, false, "suspended", r.URL.Query(), &params.Suspended)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "favorited", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "suspended", Err: err})
 		return
 	}
 
@@ -509,7 +1354,7 @@ func (siw *ServerInterfaceWrapper) GetArticles(w http.ResponseWriter, r *http.Re
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetArticles(w, r, params)
+		siw.Handler.ListUsers(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -519,8 +1364,19 @@ func (siw *ServerInterfaceWrapper) GetArticles(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
-// CreateArticle operation middleware
-func (siw *ServerInterfaceWrapper) CreateArticle(w http.ResponseWriter, r *http.Request) {
+// DeleteUser operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
+		return
+	}
 
 	ctx := r.Context()
 
@@ -529,7 +1385,7 @@ func (siw *ServerInterfaceWrapper) CreateArticle(w http.ResponseWriter, r *http.
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateArticle(w, r)
+		siw.Handler.DeleteUser(w, r, username)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -539,38 +1395,90 @@ func (siw *ServerInterfaceWrapper) CreateArticle(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
-// GetArticlesFeed operation middleware
-func (siw *ServerInterfaceWrapper) GetArticlesFeed(w http.ResponseWriter, r *http.Request) {
+// ForcePasswordReset operation middleware
+func (siw *ServerInterfaceWrapper) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
+		return
+	}
+
 	ctx := r.Context()
 
 	ctx = context.WithValue(ctx, TokenScopes, []string{})
 
 	r = r.WithContext(ctx)
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetArticlesFeedParams
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ForcePasswordReset(w, r, username)
+	}))
 
-	// ------------- Optional query parameter "offset" -------------
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	handler.ServeHTTP(w, r)
+}
+
+// SetUserRole operation middleware
+func (siw *ServerInterfaceWrapper) SetUserRole(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "limit" -------------
+	ctx := r.Context()
 
-	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetUserRole(w, r, username)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UnsuspendUser operation middleware
+func (siw *ServerInterfaceWrapper) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetArticlesFeed(w, r, params)
+		siw.Handler.UnsuspendUser(w, r, username)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -580,17 +1488,17 @@ func (siw *ServerInterfaceWrapper) GetArticlesFeed(w http.ResponseWriter, r *htt
 	handler.ServeHTTP(w, r)
 }
 
-// DeleteArticle operation middleware
-func (siw *ServerInterfaceWrapper) DeleteArticle(w http.ResponseWriter, r *http.Request) {
+// SuspendUser operation middleware
+func (siw *ServerInterfaceWrapper) SuspendUser(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "slug" -------------
-	var slug string
+	// ------------- Path parameter "username" -------------
+	var username string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
 		return
 	}
 
@@ -601,7 +1509,7 @@ func (siw *ServerInterfaceWrapper) DeleteArticle(w http.ResponseWriter, r *http.
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteArticle(w, r, slug)
+		siw.Handler.SuspendUser(w, r, username)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -611,22 +1519,867 @@ func (siw *ServerInterfaceWrapper) DeleteArticle(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
-// GetArticle operation middleware
-func (siw *ServerInterfaceWrapper) GetArticle(w http.ResponseWriter, r *http.Request) {
+// GetArticles operation middleware
+func (siw *ServerInterfaceWrapper) GetArticles(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "slug" -------------
-	var slug string
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetArticlesParams
 
-	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "author" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author", r.URL.Query(), &params.Author)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "favorited" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "favorited", r.URL.Query(), &params.Favorited)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "favorited", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
 		return
 	}
 
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetArticles(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateArticle operation middleware
+func (siw *ServerInterfaceWrapper) CreateArticle(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateArticle(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetArticlesFeed operation middleware
+func (siw *ServerInterfaceWrapper) GetArticlesFeed(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetArticlesFeedParams
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetArticlesFeed(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteArticle operation middleware
+func (siw *ServerInterfaceWrapper) DeleteArticle(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteArticle(w, r, slug)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetArticle operation middleware
+func (siw *ServerInterfaceWrapper) GetArticle(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetArticle(w, r, slug)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateArticle operation middleware
+func (siw *ServerInterfaceWrapper) UpdateArticle(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateArticle(w, r, slug)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetArticleComments operation middleware
+func (siw *ServerInterfaceWrapper) GetArticleComments(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetArticleComments(w, r, slug)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateArticleComment operation middleware
+func (siw *ServerInterfaceWrapper) CreateArticleComment(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateArticleComment(w, r, slug)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteArticleComment operation middleware
+func (siw *ServerInterfaceWrapper) DeleteArticleComment(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteArticleComment(w, r, slug, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteArticleFavorite operation middleware
+func (siw *ServerInterfaceWrapper) DeleteArticleFavorite(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteArticleFavorite(w, r, slug)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateArticleFavorite operation middleware
+func (siw *ServerInterfaceWrapper) CreateArticleFavorite(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateArticleFavorite(w, r, slug)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListArticleRevisions operation middleware
+func (siw *ServerInterfaceWrapper) ListArticleRevisions(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListArticleRevisions(w, r, slug)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetArticleRevision operation middleware
+func (siw *ServerInterfaceWrapper) GetArticleRevision(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "number" -------------
+	var number int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "number", chi.URLParam(r, "number"), &number, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "number", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetArticleRevision(w, r, slug, number)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetArticleRevisionDiff operation middleware
+func (siw *ServerInterfaceWrapper) GetArticleRevisionDiff(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "number" -------------
+	var number int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "number", chi.URLParam(r, "number"), &number, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "number", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetArticleRevisionDiffParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetArticleRevisionDiff(w, r, slug, number, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RestoreArticleRevision operation middleware
+func (siw *ServerInterfaceWrapper) RestoreArticleRevision(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "number" -------------
+	var number int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "number", chi.URLParam(r, "number"), &number, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "number", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RestoreArticleRevision(w, r, slug, number)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListAuthProviders operation middleware
+func (siw *ServerInterfaceWrapper) ListAuthProviders(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAuthProviders(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// OidcCallback operation middleware
+func (siw *ServerInterfaceWrapper) OidcCallback(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "provider" -------------
+	var provider string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "provider", chi.URLParam(r, "provider"), &provider, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "provider", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params OidcCallbackParams
+
+	// ------------- Optional query parameter "code" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "code", r.URL.Query(), &params.Code)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "code", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "state" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "state", r.URL.Query(), &params.State)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "state", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "error" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "error", r.URL.Query(), &params.Error)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "error", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.OidcCallback(w, r, provider, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// OidcLogin operation middleware
+func (siw *ServerInterfaceWrapper) OidcLogin(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "provider" -------------
+	var provider string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "provider", chi.URLParam(r, "provider"), &provider, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "provider", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.OidcLogin(w, r, provider)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProfileByUsername operation middleware
+func (siw *ServerInterfaceWrapper) GetProfileByUsername(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetProfileByUsername(w, r, username)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UnfollowUserByUsername operation middleware
+func (siw *ServerInterfaceWrapper) UnfollowUserByUsername(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnfollowUserByUsername(w, r, username)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// FollowUserByUsername operation middleware
+func (siw *ServerInterfaceWrapper) FollowUserByUsername(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FollowUserByUsername(w, r, username)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTags operation middleware
+func (siw *ServerInterfaceWrapper) GetTags(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTagsParams
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "order", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "counts" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "counts", r.URL.Query(), &params.Counts)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "counts", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTags(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UnfollowTag operation middleware
+func (siw *ServerInterfaceWrapper) UnfollowTag(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "tag" -------------
+	var tag string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tag", chi.URLParam(r, "tag"), &tag, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnfollowTag(w, r, tag)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// FollowTag operation middleware
+func (siw *ServerInterfaceWrapper) FollowTag(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "tag" -------------
+	var tag string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tag", chi.URLParam(r, "tag"), &tag, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FollowTag(w, r, tag)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteCurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCurrentUser(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteCurrentUser(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCurrentUser(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateCurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetArticle(w, r, slug)
+		siw.Handler.UpdateCurrentUser(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -636,28 +2389,38 @@ func (siw *ServerInterfaceWrapper) GetArticle(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// UpdateArticle operation middleware
-func (siw *ServerInterfaceWrapper) UpdateArticle(w http.ResponseWriter, r *http.Request) {
+// GetUserDrafts operation middleware
+func (siw *ServerInterfaceWrapper) GetUserDrafts(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "slug" -------------
-	var slug string
+	ctx := r.Context()
 
-	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUserDraftsParams
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
 		return
 	}
 
-	ctx := r.Context()
-
-	ctx = context.WithValue(ctx, TokenScopes, []string{})
+	// ------------- Optional query parameter "limit" -------------
 
-	r = r.WithContext(ctx)
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UpdateArticle(w, r, slug)
+		siw.Handler.GetUserDrafts(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -667,22 +2430,30 @@ func (siw *ServerInterfaceWrapper) UpdateArticle(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
-// GetArticleComments operation middleware
-func (siw *ServerInterfaceWrapper) GetArticleComments(w http.ResponseWriter, r *http.Request) {
+// GetDataExport operation middleware
+func (siw *ServerInterfaceWrapper) GetDataExport(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "slug" -------------
-	var slug string
+	ctx := r.Context()
 
-	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDataExportParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetArticleComments(w, r, slug)
+		siw.Handler.GetDataExport(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -692,19 +2463,8 @@ func (siw *ServerInterfaceWrapper) GetArticleComments(w http.ResponseWriter, r *
 	handler.ServeHTTP(w, r)
 }
 
-// CreateArticleComment operation middleware
-func (siw *ServerInterfaceWrapper) CreateArticleComment(w http.ResponseWriter, r *http.Request) {
-
-	var err error
-
-	// ------------- Path parameter "slug" -------------
-	var slug string
-
-	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
-		return
-	}
+// ListPersonalTokens operation middleware
+func (siw *ServerInterfaceWrapper) ListPersonalTokens(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -713,7 +2473,7 @@ func (siw *ServerInterfaceWrapper) CreateArticleComment(w http.ResponseWriter, r
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateArticleComment(w, r, slug)
+		siw.Handler.ListPersonalTokens(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -723,28 +2483,8 @@ func (siw *ServerInterfaceWrapper) CreateArticleComment(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// DeleteArticleComment operation middleware
-func (siw *ServerInterfaceWrapper) DeleteArticleComment(w http.ResponseWriter, r *http.Request) {
-
-	var err error
-
-	// ------------- Path parameter "slug" -------------
-	var slug string
-
-	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
-		return
-	}
-
-	// ------------- Path parameter "id" -------------
-	var id int
-
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
-		return
-	}
+// CreatePersonalToken operation middleware
+func (siw *ServerInterfaceWrapper) CreatePersonalToken(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -753,7 +2493,7 @@ func (siw *ServerInterfaceWrapper) DeleteArticleComment(w http.ResponseWriter, r
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteArticleComment(w, r, slug, id)
+		siw.Handler.CreatePersonalToken(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -763,17 +2503,17 @@ func (siw *ServerInterfaceWrapper) DeleteArticleComment(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// DeleteArticleFavorite operation middleware
-func (siw *ServerInterfaceWrapper) DeleteArticleFavorite(w http.ResponseWriter, r *http.Request) {
+// DeletePersonalToken operation middleware
+func (siw *ServerInterfaceWrapper) DeletePersonalToken(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "slug" -------------
-	var slug string
+	// ------------- Path parameter "id" -------------
+	var id string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
@@ -784,7 +2524,7 @@ func (siw *ServerInterfaceWrapper) DeleteArticleFavorite(w http.ResponseWriter,
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteArticleFavorite(w, r, slug)
+		siw.Handler.DeletePersonalToken(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -794,19 +2534,8 @@ func (siw *ServerInterfaceWrapper) DeleteArticleFavorite(w http.ResponseWriter,
 	handler.ServeHTTP(w, r)
 }
 
-// CreateArticleFavorite operation middleware
-func (siw *ServerInterfaceWrapper) CreateArticleFavorite(w http.ResponseWriter, r *http.Request) {
-
-	var err error
-
-	// ------------- Path parameter "slug" -------------
-	var slug string
-
-	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
-		return
-	}
+// DisableTwoFactor operation middleware
+func (siw *ServerInterfaceWrapper) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -815,7 +2544,7 @@ func (siw *ServerInterfaceWrapper) CreateArticleFavorite(w http.ResponseWriter,
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateArticleFavorite(w, r, slug)
+		siw.Handler.DisableTwoFactor(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -825,22 +2554,17 @@ func (siw *ServerInterfaceWrapper) CreateArticleFavorite(w http.ResponseWriter,
 	handler.ServeHTTP(w, r)
 }
 
-// GetProfileByUsername operation middleware
-func (siw *ServerInterfaceWrapper) GetProfileByUsername(w http.ResponseWriter, r *http.Request) {
+// EnrollTwoFactor operation middleware
+func (siw *ServerInterfaceWrapper) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
 
-	var err error
+	ctx := r.Context()
 
-	// ------------- Path parameter "username" -------------
-	var username string
+	ctx = context.WithValue(ctx, TokenScopes, []string{})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
-		return
-	}
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetProfileByUsername(w, r, username)
+		siw.Handler.EnrollTwoFactor(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -850,19 +2574,8 @@ func (siw *ServerInterfaceWrapper) GetProfileByUsername(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// UnfollowUserByUsername operation middleware
-func (siw *ServerInterfaceWrapper) UnfollowUserByUsername(w http.ResponseWriter, r *http.Request) {
-
-	var err error
-
-	// ------------- Path parameter "username" -------------
-	var username string
-
-	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
-		return
-	}
+// ConfirmTwoFactor operation middleware
+func (siw *ServerInterfaceWrapper) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -871,7 +2584,7 @@ func (siw *ServerInterfaceWrapper) UnfollowUserByUsername(w http.ResponseWriter,
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UnfollowUserByUsername(w, r, username)
+		siw.Handler.ConfirmTwoFactor(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -881,19 +2594,8 @@ func (siw *ServerInterfaceWrapper) UnfollowUserByUsername(w http.ResponseWriter,
 	handler.ServeHTTP(w, r)
 }
 
-// FollowUserByUsername operation middleware
-func (siw *ServerInterfaceWrapper) FollowUserByUsername(w http.ResponseWriter, r *http.Request) {
-
-	var err error
-
-	// ------------- Path parameter "username" -------------
-	var username string
-
-	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
-		return
-	}
+// ResendVerificationEmail operation middleware
+func (siw *ServerInterfaceWrapper) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -902,7 +2604,7 @@ func (siw *ServerInterfaceWrapper) FollowUserByUsername(w http.ResponseWriter, r
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.FollowUserByUsername(w, r, username)
+		siw.Handler.ResendVerificationEmail(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -912,11 +2614,11 @@ func (siw *ServerInterfaceWrapper) FollowUserByUsername(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// GetTags operation middleware
-func (siw *ServerInterfaceWrapper) GetTags(w http.ResponseWriter, r *http.Request) {
+// CreateUser operation middleware
+func (siw *ServerInterfaceWrapper) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetTags(w, r)
+		siw.Handler.CreateUser(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -926,17 +2628,25 @@ func (siw *ServerInterfaceWrapper) GetTags(w http.ResponseWriter, r *http.Reques
 	handler.ServeHTTP(w, r)
 }
 
-// GetCurrentUser operation middleware
-func (siw *ServerInterfaceWrapper) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+// Login operation middleware
+func (siw *ServerInterfaceWrapper) Login(w http.ResponseWriter, r *http.Request) {
 
-	ctx := r.Context()
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Login(w, r)
+	}))
 
-	ctx = context.WithValue(ctx, TokenScopes, []string{})
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	r = r.WithContext(ctx)
+	handler.ServeHTTP(w, r)
+}
+
+// LoginTwoFactor operation middleware
+func (siw *ServerInterfaceWrapper) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetCurrentUser(w, r)
+		siw.Handler.LoginTwoFactor(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -946,17 +2656,25 @@ func (siw *ServerInterfaceWrapper) GetCurrentUser(w http.ResponseWriter, r *http
 	handler.ServeHTTP(w, r)
 }
 
-// UpdateCurrentUser operation middleware
-func (siw *ServerInterfaceWrapper) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+// Logout operation middleware
+func (siw *ServerInterfaceWrapper) Logout(w http.ResponseWriter, r *http.Request) {
 
-	ctx := r.Context()
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Logout(w, r)
+	}))
 
-	ctx = context.WithValue(ctx, TokenScopes, []string{})
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	r = r.WithContext(ctx)
+	handler.ServeHTTP(w, r)
+}
+
+// RequestPasswordReset operation middleware
+func (siw *ServerInterfaceWrapper) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UpdateCurrentUser(w, r)
+		siw.Handler.RequestPasswordReset(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -966,11 +2684,11 @@ func (siw *ServerInterfaceWrapper) UpdateCurrentUser(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r)
 }
 
-// CreateUser operation middleware
-func (siw *ServerInterfaceWrapper) CreateUser(w http.ResponseWriter, r *http.Request) {
+// ConfirmPasswordReset operation middleware
+func (siw *ServerInterfaceWrapper) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateUser(w, r)
+		siw.Handler.ConfirmPasswordReset(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -980,11 +2698,11 @@ func (siw *ServerInterfaceWrapper) CreateUser(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// Login operation middleware
-func (siw *ServerInterfaceWrapper) Login(w http.ResponseWriter, r *http.Request) {
+// VerifyEmail operation middleware
+func (siw *ServerInterfaceWrapper) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.Login(w, r)
+		siw.Handler.VerifyEmail(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -1107,6 +2825,33 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/lockouts", wrapper.GetLockouts)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/lockouts/{email}", wrapper.DeleteLockout)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/login-attempts", wrapper.GetLoginAttempts)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/users", wrapper.ListUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/users/{username}", wrapper.DeleteUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/users/{username}/password-reset", wrapper.ForcePasswordReset)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/admin/users/{username}/role", wrapper.SetUserRole)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/users/{username}/suspension", wrapper.UnsuspendUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/users/{username}/suspension", wrapper.SuspendUser)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/articles", wrapper.GetArticles)
 	})
@@ -1140,6 +2885,27 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/articles/{slug}/favorite", wrapper.CreateArticleFavorite)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/articles/{slug}/revisions", wrapper.ListArticleRevisions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/articles/{slug}/revisions/{number}", wrapper.GetArticleRevision)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/articles/{slug}/revisions/{number}/diff", wrapper.GetArticleRevisionDiff)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/articles/{slug}/revisions/{number}/restore", wrapper.RestoreArticleRevision)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/auth/providers", wrapper.ListAuthProviders)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/auth/{provider}/callback", wrapper.OidcCallback)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/auth/{provider}/login", wrapper.OidcLogin)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/profiles/{username}", wrapper.GetProfileByUsername)
 	})
@@ -1152,18 +2918,69 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/tags", wrapper.GetTags)
 	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/tags/{tag}/follow", wrapper.UnfollowTag)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/tags/{tag}/follow", wrapper.FollowTag)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/user", wrapper.DeleteCurrentUser)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/user", wrapper.GetCurrentUser)
 	})
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/user", wrapper.UpdateCurrentUser)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/user/drafts", wrapper.GetUserDrafts)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/user/export", wrapper.GetDataExport)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/user/tokens", wrapper.ListPersonalTokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/user/tokens", wrapper.CreatePersonalToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/user/tokens/{id}", wrapper.DeletePersonalToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/user/two-factor", wrapper.DisableTwoFactor)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/user/two-factor", wrapper.EnrollTwoFactor)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/user/two-factor/verify", wrapper.ConfirmTwoFactor)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/user/verify-email", wrapper.ResendVerificationEmail)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/users", wrapper.CreateUser)
 	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/users/login", wrapper.Login)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/login/two-factor", wrapper.LoginTwoFactor)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/logout", wrapper.Logout)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/password-reset", wrapper.RequestPasswordReset)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/password-reset/confirm", wrapper.ConfirmPasswordReset)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/verify-email", wrapper.VerifyEmail)
+	})
 
 	return r
 }
@@ -1171,48 +2988,130 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xbW3PbNvb/Khj8/zPdzciik+1DR2+uE3eym3QziT19SPIAkUcUGhBgAdCO6tF338GN",
-	"hHiRKJlJvN3kJRYJHJzL71xwAN7jVBSl4MC1wot7XBJJCtAg7S9GC6rfmEfmVwYqlbTUVHC8wNdrQLwq",
-	"liAVEitENRQKaYEk6EryOZ5haob9UYHc4BnmpAC8cBTxDKt0DQVxVFekYhovnp3PcEE5LaoCL57OsN6U",
-	"ZgblGnKQeLudYbFaKTjM0A4/6hMt0RJWQgJSmkhNeW6ep4IxSDXSa0ASVMU0UqCH+HYr7zBe83rew+t2",
-	"hiX8UYHSP4uMgtXmK5FTfqNAvnVvzLNUcA3c/knKktGUGGmS35UR6T5arZSiBKk9qUqBNP//v4QVXuD/",
-	"SxorJm6OSurlcOCGSsjw4r2b/bHmWix/h1Q7pndVeikhA64pYVaVlQIcU9Kygu0M/wp3F1LTlMHDBSOO",
-	"0CHZmiU7wgUKY+TzNCwcJBA9JN6lKArg+uHipY7QCPH8kh3xAoVR5nNj0UZU6I5wfVDOrwdOv9jp0HwO",
-	"mlBmQ4/xYA53Bp7SRaCcKg2yT8ibMiMavjZcd1adCrGVJTos5NczZrPe6fY0s1HmjVpLN0cXGjEgSqMn",
-	"TwSHJ0/QigLLEFUoLDPvqsAyoUrBlRPiRVHqzb8/vfXPurnjV5MPnH62M/wLcJA0fSGlkEepbp+SYqKv",
-	"RQasVwscPpeQasgQ2NW3M/y6YpqWLMBHxVI8DLb2b5soe4ZUei0OWv6NFCtqID3DLrBkF5aZlZAF0XiB",
-	"jRHPNC0MTj0GlJaU57gt+333/YrcCkm1MWv9dikEA8Lj1+pSVE4H7TQ8w4pVeS9tTfJX1HlGrYHuIPeA",
-	"SEk29jfVztk7Ix1cj5C+HQOcumM17uon1kZHdC9nI1VgNWas64VdAQMwBjXaH7oUbk8d4/IB2KimEqHd",
-	"564p0O5T5i7a96G6zr1tBfXnYnWctPWs7Qx7/5lAyNJ74liHbYkSpo+RJHL6d5TnrEmnUwWmQ1JMkUkd",
-	"6wF7jTB1rTcV7kaj7QGVnhcmbYB7TfIpfEeTXB0TI1sy2OljBDDsGmo33EVC+qeL+u30GL01o22J82Ah",
-	"R9U4D65u7GxPzqx20WD9gal3KbJNr22+5+RJcrLV79dOzZdN9HhE8KBZv02/pK5pdlBZ3eK6ozZbT/cU",
-	"ukE7J0Y4O70nBOyO8ov3sd60abosF4SyXpZKotSdkNmOtuuHh5Tt6EZU+viKWiyDOjs6ekzq4X2WaHum",
-	"mzsg36CLDcg3zvR1J+ULGnRmU4/rTd4fa+1och/3b5pKsqUVKvpTgmBM3JkfvSmBFiQfCNCjZTBLxwsF",
-	"qgdE2e27TAjjYUQO8NCPhyGNDuNkWJkxgo7UdJfnL86tFp+ATwKKAO8ACEd5LzBMDQJpJanevDPp0ol3",
-	"HVjaLd2uhEQkTUEpe3CwBlRKoV2P5uLNSyRBiUqmoGa2z1pUSqM1uQUkIQV6CxkiiKBbwmiG/vnbNbL8",
-	"IbLSIOtOpaEsJGIiz82flM/R9ZqqaLwlq9fA0RJQpSBDK8MXYxE3NSdouUEGDpaWRpSjW0os6z9c+PrZ",
-	"FsY/oDWQDOT8A//AL6LVqEK5yagm4xpiZqqRdblBQPW6xbkhnhh1q10hohcJM1nOrnO9BlQ7MnKxzom3",
-	"BDNpmE20MPMRQsiaCn22/+Yb92/+p/3nBnzg4RzHTW0OcnYoN4GVlPRfsHGlO+UrEXYTJLXpwU9+C4T9",
-	"JiSzMVQyQ17rUi2SRAJhd+bNWSZSNeegGV1t5qQsE9zTl+dZRbVVaSbSyiShwA+jKfjdjF/09ctr9Mo/",
-	"bS8rSuDO6HMh88RPVsnrl9dRnGr4RtHSeIZvQSrH0tP5+fzcTDEUSUnxAv9jfj5/avOGXlsHSeK2YQ66",
-	"6yu/gEaFUNpin+u6sYNyJpaEsc0c3ShA9mwNNUeNSAu0osx5hKqYVnNk7GRwKCxtYjzcxCKrppeZW+ui",
-	"6T7F55bvOy7saBsgk3zggM+9aXaIndAzTNTVruhvIeL8fWCJusY9aZV6X4HEChF32HFwyXhjsnfVvq1E",
-	"o9MkPngdMTw6N95+bPXin52fD+1d6nHJYM97O8M/nj89TKDdKfjx2bPDk3b6/jZLVEVB5MYjewjUbnNn",
-	"gIdrRH40GVmoHh+5tDsbRHgg1GC9Tm5trLs5oY6Jj5Y3w1JFp89J94h22zHLCK32t/u+sk185rZ+7nP2",
-	"+48GZo21OjruNdEMfz5LRQY58DOvrjNTEp4Ff426jHXsS1bgeh/HBcCVFAVyOdJUCS4BDkdD60AjgBEF",
-	"wSuw71uB8Ltfn4qhPo/vtWK/++9g5l6xKt86uDDQPQeQz+3z46KCm9NEhb058B2r8nBYTppDZM+Pzx4m",
-	"1TfJwzeuds9W9+WRkyDRPpt9fEjo2GYo4A+GhI5ZudBjnPpEo+b2ptI3s+hwlpggCR+2Qln1WMHtwo/z",
-	"r93uwWmmqC9pTGaNIzN/74WX7bRmfVzu2jH1hMnfBfIkPlEedHqDhDDQ7dZ7wDdiexNOwk9C4JrsXv/K",
-	"Qe8w9S3jxOBR/7cs8gcsFmGotsfhGj9QGzT/uIo/9KmnAEDaw9o3DE/dy5UPiE3to/vHuzEZAEYvyg5F",
-	"qjS6PzAYqZJ7mo0qQE+H7E45OiVksx7WJoJsp9/y8nlgJ+25PLuvWranlAdXbi5S/cWL5WMQ3ovc0Lra",
-	"B9obHkY9AKpXYaEpsFrVHD3OCvyRlWp99osgEkyzL9denYSAnfw6KQK+23+8/a9GW99ECH9HUSX3oe29",
-	"3VuAE+RnRB1zbz21URqKcWW4Pw//eXMTThQPwCSMC4sFLvbtzKuG9hfGRvvK6bcstmsLRVYP1w8GbZ74",
-	"ttv+vGDHBKsvNyhScGuX78caqz3cxq1cEBqE/8sWH5cHYnv1omEw/o+29NUXsvN3K4+M9gdtbDw+3Dge",
-	"7quQXI1so9pbxacoc+f29ETBTjtmgtSWNydyuH88JLJyG5JKSuCabewVC8jOKA+a7Ah+6cbeKP8V3LHy",
-	"71ysfpxnM14dbTDZ78gIzyxAgGt/B/xQZzhz4YNydyGFCm43Lq1F+vrDbVWf1J+Nv9Xb/gUN5nuyx9rs",
-	"UOvDXuCrXchd7u1NEm/9dSVE6i9FB7YEp9qx9fXsScfrHSOeZI+pterubg3r1l5gtt4Cn6myn9f36teO",
-	"O0W1ne/m/+s8pPaDF7GGEPMamcpi1hnlbahkmqtiiyRhIiVsLZRe/HT+03lCSmqP8P3S9WWzi+ZTvPrZ",
-	"ZfPBWv2s2ZtFD5tvDupH/rue+veQiNuP2/8EAAD//xbPXD+LQgAA",
+	"H4sIAAAAAAAC/+x9W3PktrHwX0Hx+6qcuCiN1vZDjt4U7a69yd5Kl+RUOX6ASMwMLA7AAKC04y3991No",
+	"XAiS4G1mtJLt9UOyGuLW6Au6G92Nz0nGNyVnhCmZnH5OSizwhigi4K+Cbqj6qH/Sf+VEZoKWinKWnCZX",
+	"a4JYtbkhQiK+RFSRjUSKI0FUJdhxkiZUN/tvRcQ2SROGNyQ5NSMmaSKzNdlgM+oSV4VKTr87SZMNZXRT",
+	"bZLTF2mitqXuQZkiKyKSh4c04culJOMLaqxH3tIS3ZAlFwRJhYWibKV/z3hRkEwhtSZIEFkVCkmi+tZt",
+	"Zm4s3K/1JLLWhzQR5L8VkervPKcEdvPVBtPiX0TQJc2wXvSFaaG/ZZwpwuCfuCwL22Dxq9SgfQ5mLQUv",
+	"iVB2yEoSof///wuyTE6T/7eosbkwfeSiM23iVkcFyZPTn80ov3go+M2vJFMGiOYWh6MgxW8JS8KRlKjI",
+	"Q5q85SvKriURXwY+P93ucJ0LkhOmKC6AZCpJYnC9J/dnQtGsIPsDhs1AY7DVU3aAcyNMgc+OAWQvCFZ9",
+	"4J3zzYYwtT94mRloAnh2yg54boRJ6DNt0ZZX6B4zNQrnRyIkZ7i40iS8P7SGE8ZhbUzbgdiMMgVeNw7C",
+	"WUakNJw4CvSX40g72e78+JIoTAs4V7R4ZuRe86Qwx8uKSkVEDMiPWMp7LvJzzpZUbL6gjI3NvIc4qoTQ",
+	"9FzaUd0+wAADcF8QSdTTAd+ZfvcdgKEsXWOWAwW43RjdgSeAendI4XRGOM+F5mSLaJxlvGIqBukFL8iZ",
+	"lHTFDiOpp4DanHN3WN+TeyR4Qcbo+bKSJWHyIAQs/VhjUNazdiAMBplGvVhy5qAMOkdgvbrnr3GmuDjn",
+	"+QH0CuWGG4O2MW/3LPLDTDt/c4KWgm8M8VZqTZjSK+YC4bIcBBu0t6eAGybeF/A1LgrCVgSElCQZZzla",
+	"mv4RoK/LHCvypXXIxqyHUiMrGLQfyC+nbNTz7S6YdG+UW6XDQ3eMzhQqCJYKffstZ+Tbb9GSkiJHVCI3",
+	"zXF3C2ARsuRMGiDO8o2xT+SF/XnPHYF/gH07tjd+6uTB7wIWAm/13zDUOZwzp5/d19Dabm+lTBqdpm6s",
+	"1JN5or+jWhIeYiuEG2v6djQXcVltNlhsY3vjx566P/ViOp2nnRi2iz40MEOOK/XOVWr9UfA7mh+Ggko3",
+	"1vRtC1bQ3azWRtTDT4H7DZjdaovqbg9ponXIgmaq6+PxXx7S5NWmVNsPt+GWtNQNjtwePaTJay5uaJ4b",
+	"Q63ZsP70kCY/EkYEzV4JYQ6Tyds8tIXhoO94ToooszDyqSSZIjkiMDv4UrJbXqlDIL6wQ03Gu517FOV+",
+	"4CkY14OS3Gm4MnH+ojOlyKY8CKDYDjUD0HoBo9D60adA+xrTguSo0BMg3/MhTd7xO5J/JGKD9VKKbZco",
+	"oQUqgyZpsibYce5bbnYjwiPWery+eOtUUEEkr0RGGv5Lu3ipBGUrvXi9rKpQtCyctnAQbNihGthoNanU",
+	"elxz+yj4khqZaPwc+RksZsm1uZmcJvrMPlJ0o8FsAdfCy+fu9yW+44IqkgdfbzgvCGbh5/4DIU3K6qag",
+	"cj1nVbKoVtHlSIVVJaOfFF69pVI19rPbqHWgKaqMpthpaXSdGXvZ5geDvBApzd0O97azkXYLPMA1eG7N",
+	"4Qq7LNeF1NHb1JPbtU/aXafwt+MX5EcJmMi6JQ/BRNYbOl2kebfqiDTzA8+C1veKulP3hlaSTBAVp+sp",
+	"rtZhP6sdPZ3hcD03pI1KO3DD8ZoaKx9G1aYBZ8UWyTW/Z4izDOTVe65e84rlMR1FoSV80l6scN2HIBtY",
+	"33Siae3bCOnYwXd2WAP1WKF+GL1WjzT5FOnqrfD7JHDqk+iCZPyOiK12gRzGqAnGmyPiO/ZIOM40E8T0",
+	"QBl0AdhyKkhMDfdfJqok/14TYW6fOFOUVeOKiDOIXtLl8gDbmtPlctTLGUzZ2U8YYNLVBV0u0Q1R94Qw",
+	"pO45qi1D7dmkbFXUHqBDKVcTDd+9nD9m6aFl2gLGgHlAw36mOd9rk88Bz/fx8Pk70UMd4pOP7j1uRC00",
+	"Wa0FXOHVa14U/P4QxwpejbpZ3XTdYwOvpplO0J3kSHcwAMjDrP3cWJ7RABKFVxLdU7XWhzoVjtyRsVZT",
+	"RJk57fGGIC5yIo7RB33em6gXkqP7NWHIhn6Q3AxlOifptFP4yq4wqsbj1T6nAnSfsvl6s2HTOX+H2dZ6",
+	"cmN7xjnaYLZ1IMvmkXCBFXmrQ36O4H8j+o8P2HEDIGzxfrOFrSaf1riCvRRYEdQNIIro9/W0F2SDKdN7",
+	"M2XqgixVik4sEv10JJ8zoSSDcJpbAokqpmgBENYTafVxWRVAT+1wo9isRInt0dlSETFlRsXRPabKBUMJ",
+	"3VvvzOAsD407KnffcQhGnH9T5Wbf89bG3dyCNloqkqfNqxvv3E9C2F8xwYviQEcBV6W2m68FjVv/fQZQ",
+	"C+5gGN9p0g4QluswuKsPVx+t5aKnvWbGmKe/kYip0viqW8MVz0FuNEbvePa+3YHedrjGpUx3QVpiFFG0",
+	"kDqSrs9PJHiPm8XcAufznFYyuAjHssdzpWoKxTdF38L0bplYxjGiMvC3obWgRaYLxo75Z85qFXVPr98N",
+	"z7fRHdAfflKbIn6k669I6L0XJDfX1O+wuM21ga44kphRpWka/XT17u0x+olgzRsSrfEdQW9egtwsKLtF",
+	"isdw9DvwRXaMMYYw0hucV9o77XScFVES2Y7GV3coH2ZzAR8YRIDkAuvT1q8jredGXKCKFVQf+8fopW4o",
+	"zRV7e9ESYUGMy0Ur7jfmdtrqbkBgqR+p7gPtoR2XBN2vuUG2Pow1pmNQPlevKzDFkzlf2ybYIzL5I/CZ",
+	"iRaPM1Af6nbGg53MjTxhN9399JPclxxyb8JNmbIN4X1zxJUjywJv38fPMhewP7a2cBTbJ7aU89pkf0Z0",
+	"TfM4Xh5TqNB8VBi8xAq/+lRyoSbeAk5yWXWl6uzLELMokvdeiqQJsU3moMFL0+4Rd1lUqzqa0x07LuYR",
+	"hTJ5+mmytB6RK7yKTKl/DWaAtnKH8aO28rXV8GQYurnndGXjtuNQlxTptJwYh24baDtwKeiprUEkIfZb",
+	"mAk3sgNl2me2pOA5fktZRFPmZRcj5L8VLrQ/ShKhtL6Uk4KoKJ0q8mmKLVkmtmlscd0kov4siOF5+m7d",
+	"0qSNlM4MN5T322W7WmzUhCDRGBu/D0lea4YkR+ST0rxQINqNXZpD/nSDV/ETrNeGPLSlp/cz7bX3go1x",
+	"q93ZCGxL4L7z4bLPlvhdHJ8BDLueod1Asc5WEf0tEkXjtmhH3zB07y6p1cpOHlu6Cxeb4VBZYlpUgsg4",
+	"PgoIFbvWftIdMeJI28/THDQORRAL1gFlB2rrh56W0Z9Fn6unBVxIWQ5QWiZ+gF7o5rq9fOJNCLP/cSoO",
+	"fIfYuoI8x166nm3ejflAFHceh1BNS737Fy25AK8D3ZTFFtKG15BDrCpZeyKm+0l2d4f8xSZJ/zV0jIx4",
+	"KVoKXKnBfXFiLrj4ElXww/ffoWyNBc4UETJFWokRGZbu4up+TRWRJc4IpEvjUs5UWSdailZWNg3mfgMx",
+	"SBudSi3ThF03mqk7AflUUkFkjKo+wD9wgaDNNrVxLijn7BtlfiT6WsticzLlsD5bV2a8JH0kJdCGCyAt",
+	"fTSfCoLz1Nsip/eCKpL6SC73t9dqzQ9A/jY0xv40A/+tTWfG2LaL7tn9RxZOuzjCg9EGlZxoEmgHlP2k",
+	"6aAUbaYkTt7EKNij4x8UynSq9RAO0WtIjHDwLod4yPQ7aJn1zwWW6lrOm30C/+/Ikh3tdJxHP9ZxdtOs",
+	"sx3cFu2gCn0QSxPS4oTR7p6NiAnYa4rNtaVCy9+ZTIMyoxF0Nq7SD/k0vP8gsgEtVWnv8fQt2ohveO85",
+	"FJ8QvR3VHGB19RWG4vGtb2Y1dzbfmeFRbU0jNUUbnhNh0lxZjrC+Ux6V4DBsbD2XjUzl1lq8TdCjcTCu",
+	"CLAJV2sizFJkdC2deX28Ua/T1n/uizCpNVXXxcdQQfhWGiMUvJpwhdBYQdoTPBZEt3WBGOH9ScsI2bp3",
+	"Cd0wlWHdcZrsn3gu1iMPHYzNpO/uwWh/HZ4KWg2OblKru8OHG9MB1E3eSjmGcOSB5HKt52IkwuDlURas",
+	"F5L2Q9NMm/5qlQ5e0s+yRS9IWeDM3oc4e7QBK9NLLyAoo6C3xN7T8yXUAQl88wcxRHswHzdE5rug+5WL",
+	"UEeeqXl01/zoq+0TRLs7nJ2CZEYeVJRMTFwlqNpeatXBgOf1+046r000cdxRCq5MYu3Zxzc+G1KmUDFq",
+	"U0ll4j8EyQjVWZcYYXSHC5qjf/z7ypWhWSoifPkhPTIXOrFzpf9J2TG6WlMZtIdhtbBCN6DcWsYtimA1",
+	"fiXaK6DJAcZSiDJ0RzEs/ZszG3QHhtY3yATXHv+H/YedBbNRiVaEEYFVHTmrYb3ZIkLVurVyPfjCXOE1",
+	"gAg+LCBnFebRQVT+BERGrBjwbogLiI4vE53q/gghBKhCn+C/46357/g3+M80+A8z0k/LMiLu9IIrJvXw",
+	"Gee3lICuZVKtPMS3pIS9wgz9pFQJNoPUaOe+F2VSEZyb4CGlBWG2xgzA9VG/gHvFEcnWEDqEzi8vXtcD",
+	"GPj+90j/egRwBFhw5QLND3W9wMZ21NIRl/SfZGuCFClbchc3iU2Wje18QXDxby4K8DiIQg+vVClPFwtB",
+	"cHGvvxzlPJPHjKiCLrfHuCwXSaQsC8srqoAOcp5VG8KUW09BM2LjNu2k795cobf21/a0vCTMUOoxF6uF",
+	"7SwX795cBcK1XjcKpk7S5I4Io9YmL45Pjk90Fz0iLmlymnx/fHL8Arwsag1cvQDldRGmq69iIdU/EhUW",
+	"S9LHCVYQDqbIpuQCC1psUWHzzS33liaRbxnkZctjBNGgEBWWwNIEbNOb3MzicvCTVmGP705O+uwb327R",
+	"SeB/SJMfTl6Md2yH2/5w8v14p6CSQSAyk9OfvbD8+ZeHX9JEutAi2MWilZPv8gx+NmGyyS96sBZaFp9B",
+	"jD8YvMDVcwdDb+nSoMh2csUtzDzAk4JIohBVsoESkzGhkysGMPMSZrX7m6SN0qU/f46W2WqW14JKL0yv",
+	"zXGxpsGah9051azvMpS59ssuFNKuY/GlCET3+GG8h89dnUpR17ClAaJH6GlF2VFYsmGY2aucKqSExeYy",
+	"Vl8h1WoikQotqZBqnLuD6hM7snisfsUz5PPobg1ix5cb6kUKtEASbve1tuGUuGN0LQmCKrqo5kzNcpJg",
+	"ka3NzQJeUWYqLfXjSBsR17b80CCPw/lv1nMPBoNbi1ZygJkh/xRTZk0PHewSL/dr1jiYpZpGp/dh/npO",
+	"DQ7CmaJ3Zi2ybzbXKTah91U8pHGqqPdkEdZHntA8KO+8m+iKlLZ6hmTvtn6EzBefHb0MHmrm2EEYho2k",
+	"Csr6Ui24TgNy995tWIFEGdb3gjfExm7lE467a1MycZAPrj3ZN8osRk44B/DXQ84fcrrD/4x3qAtRTSTE",
+	"BtnMIsaF8xMcCX+vx2N+lTcMbFasTCaD65Y6m8gQYbSGhak7ZkhaNzLSUq3JBmE/kFXWdBzcIKW+5iIj",
+	"zbvIrxT75GoZYKWDzXmU6K5EyipCfu/wrReLWLsbqgKLzkUJt5cT2l9CkL4NNX+HwnDDx4ThJQGV4MJE",
+	"Iz45bblC/9t+vAVvASziNXwfvsrVUK6myQ/ffTfeoVGubyovnGsfkJGSrh5xn2BOk09HGc/JirAji8Uj",
+	"7fs/skSj/50M8UyzAHGfVnGmNQOEA/3RFTzXZAJOrhXWXDPAFNfM9v6qJDwPkev9HzUNDJHaQ9pzsP/d",
+	"GNQ2REJfhWmisC5bfVyvaU5aWij87hTRrsbp6WxYzD4vepopZrtlxL+K2C8mYi3pHEKuBqljvW6IDZcK",
+	"7m+YqllgVfAbXBTbfl/EkhbGLyyrAtikUmuo4WbjKmIOo7P6/nGQI16bsW+2NgQiZvmbLzOcDPWghrjQ",
+	"Xxw7/bVnCp/Ut9MstQFbGwijU4bpbYOzPjN3Rm/1051Zfjf2angv+og6ZCn7beAIMRUMg9LSNa17Idym",
+	"ddPHDp7sIIC7DyZ1BfCEXY0XTfuCONGdJgjWdrGkydpoGzdR1I4JTBzUePMyc7EkJJ8vOEHHMJ5UfUNu",
+	"/FagURh/F16FH/rFK3DkBEoLpOprAt9bkvWroNj1HP6RDKJX47SFzrhgaVDVZ103YpKXdEDemF+4AK3U",
+	"ukCtEsvvfT+Z2mBGhNk2YJCYc7QWVYMHs07Qa4UcaWL1CbQRndXWyfhq/3hN89EItkM5fQddr0jrEB3j",
+	"KqC6j7rGI68kki5JXxCN5iB4Nai6joStdWrryqDMlle3JNEnyHYkQg3UE1Jg71H7/RQ67BS1/4KU1RB4",
+	"48QT9WCawL8xoQVBiBDyglb0zoYxOhRi+KqRlRrZCtFnAcF5eoIDkqquF6cRd7obFfnXeQ5GSDM1v+hL",
+	"Rw8Hpcg/n2TskOcB9URzoi/C0iqDcRiuoY1G7jLMBAva1cffich1rFf43ueKqMainlKK9j4A8JR2ZA/G",
+	"Ahry+Bg3I91oveifZlTaGQ9CAFlkaU8oAbuv6e4h/to1qP94tm8PQUWpc0zCZUEB7V4Jt/hM82lxHlNJ",
+	"fcSeqYNC/GWorNPGjHmTeX4YMG8OyTR5BMgDMU3HqfjmpVtOFnmvecj6ovmUmesUwa/G177G1xx2jLKZ",
+	"cwEPcdg1c63mHSENbnjtJjoEO1R+Rc/SCHtmrqUo/gIScagZUihe70QBDSXioBTwFf/T8f96MvZjEqLx",
+	"fGfU1tCBv/b5uujrmCniRR7GeXcO5FeQE2qs4WZf3U4qLrTXRyLsp0ib1ry0aqV989W0QS+iMcrtJ053",
+	"oMenpLreF1p3PwgfJ6ZimCgme69rAlx8NkVpHwat3ppGmhPOut5wu/t0tJH2Z/GHu5oiqfTEbIWwQi/i",
+	"k/tavo+tmw2/NPS8CHSIVvYhzoV7w6pHVjKCdAuHSHBQpihoBcYGFMOH+x/MEMGioETUiwUXNzxhR47R",
+	"FfmkgLmgjJRaY4ZenJycnOgAYFt3nXHwK5TYmz8z+QDKnvxpeWHi/ObhNNhluH9NgxpiTQcz4O3ENbZF",
+	"ODSitW3T3rx20IYpmnJo1o0+5PaFOPZRQ0gtQhpPvB2Q2wUB9aQ/2v/CNBhldr6MsXqqa/tl67YapC8v",
+	"XJMuQ6f24QRbh4ML8HTYlaLwCfQm39ulfj0DD3gG/kFCZB0V73BiVmq9aDxmP2xEfCgJe/NSZ6Yzkqm6",
+	"aLMNhNCU7EKttdiMq/jhW/w75YrGX/NveuthycNlpt2eXLuEnbO6Lg68eFhv0WfX72GR4aK4wdlt72ad",
+	"46Ko60e4jjZ53hdp09ukLSJ2jN5Sdgs1JIyZFFRyM8ndwVWn/rIUnCniwoiwez8Xt6s2QE0fdc+P7INc",
+	"YcGeJlY+0Dw7d2CNCJT3QXiygy3O1MHXPaRKowaEqWNEpay6G9xzIttaRjNmvFRYmcQvkrs991i8d/U1",
+	"TP4vyDZTzjUyt1RYzZwczk8kSOlzgSfASHSnZI4x+/3Jd1O0DkN5OwquxilvPaKhcIATtZdBd+bPwtXN",
+	"ijLnhQ9E0Y9aCX5vE0QaSFbcIBZEaJf+ljqODyD4+M/zV1FuMsW7npaVnhTnl4dHtCta2UoxHvA12B5B",
+	"1LXdbbmVimym3bPbUp1/317XKRezkjjcKoYCk75celD7rfCnvE33GApowK6vH+eLpa9ROHAnYoNtDdaD",
+	"igqRfC/TVmNtfxy37kFcKOifGePT7kBCfEWpoffuYzKmXz8Snr9ieWru9BiONcebX0cCp3QjUw7uZhvU",
+	"UnQegUjEakyy24eGhksembpvptSRf0EgNRH/sAK4N0kRFzZCPA2zAerSJdCsR3+DN7Dn6Yk673uDP9FN",
+	"tUGsNrz1vihuaz6nUCivUSo/Nnv3MegNZXrg5PRFOsHfdlZINyMghw1VtC2JGMjk8qWzBgq47MQdjXfP",
+	"D3R6KUM+joz1FAENLz4rvJp5WJmNiZ9PV3g1Rqv26fUZh5CZ8JElU/fR/Gd9Apk9aWF19PTR+644koRA",
+	"HTZP77by4ZJERNDrfRD7Fa1zjpwoUjWrunfuRgLYwpyFSnpvTmaeb/AVUn2xGMVXBA6O3UobQRVUn2Ze",
+	"sRzuJM5cjUZ3nX6zDXPYnanV46MzFToZ96u0Oo3LFGI5YsR4PCRRiDNiR2yXrjGBAj0BRedmk2yy++yA",
+	"z9jrH88k7/zxk8IfOUrUknJIxlNs72k55v2ZRDJkHqglulqR/Igyt4KOXtamobk4b7yp/zyTCeciYTjZ",
+	"xtY5ocyUFaacQchha5JYhsye7FoX+d6LSZ85wgyUj8Q47hBaQJH4cdMHmvU94+5Cg4OVts0R+3JiX7hX",
+	"jB81hOb5+D9bTvEMjva4iWKh/wYOcE/8g89R3L/k96zgOEdEh+KptT7w3YXzDa9UBOlYon9cfngPl0D6",
+	"Tvo3WiJdjZPe2XhNnOfUOD2LbbOip6cmLNE7LG5zrR6454I6xBG8Vz3NlP5VctZ8i+83Wjo9th1OAfIs",
+	"2UF3zeCODPYSl2VhmXLxq30Aph5v8GGdGjaNq3Cg38y7k/U4/qGIG8owwNBeaae0uB4eWdQ/P6lnAG+Q",
+	"1TcS5VjhyR57oG3lX6YevlruL6nYpm5T9c5+V2uykaS4C4Kp3ItX0dvnj+2npHdwtTWG+DIiBrYpvkVT",
+	"9YfhXLSCs9VRAQ83wLDmuTBoaYwT8ACZzZSpKUpR34/qHF6GzINnAXrgbgUq+q61EOEsIz1h6I093bFM",
+	"SmOMvYqldAd7tsqJR2CUOB5BTzFEN5r5dUHu+G3vunrOyJhF2aaMwTOmTo8y04AvVC9kn5yo51KP4nFC",
+	"h4bQNFPS+1iTQY8Klfq18zAyBTeGBMkzz+Gyt28k9HwEDo95DhADmH/A64/l/3i8bLkxejikkyJ+CP5o",
+	"H/2xsZtXH64+IkkyQVSUFo/RVS/tUomIec0fTjuEbciIHcaOSiW6I4IuaURJecUEL4omFc32zbreZrDD",
+	"pD0/SkXvS4izCXBP/Ip3FT4L2Nptf9Dvv+B7GzUlYbmWFgH2U4vLAVllIvT83VfjCT2rCcE/QTkdVYUM",
+	"1+8lQRqvE+4lOi4sLHog+ceKOodt3p3qZmpMhh6P/Htxcaq8NIU+tQCCDo7CoFu7bpIRQ69sPHqjfQHR",
+	"pFLxEt1zcWve2+yEkhOW/yvo9cq/0vM7cbBPj4wmtoBQd1NnCRg5lERgHoWz2IvqskZN39XPap9038um",
+	"6ThZv+gFxYF5StZhnnGcQAAmyHbyiUpIP4jixQVqzkYJdDy45/u7KTGa3Udyf281TQInU4AcE1OdPBax",
+	"tOyTON28+pTVZe19xHz9iPrNFtWkhdGyKgoXgF/Bw5N40lu7DFUMoofab+5GqPMw6gAM9bu8pTkIoQUx",
+	"6MGx/5gEx6uBV2ZesVwi7DI1XOKGfmdUP0Ht3yO2v+sVBc9pymN0XlANvqU53baZJ2FfDs2pzLDI7TW/",
+	"dcmZxzxjtGaeADyADtDMxOErZEaecdpOfq/Hqk2R53WczmQUKJzngkirkrula0MMNhpviE7tgAsKLsCL",
+	"HLyxCEeI9JaCHSqiVgFhtB/t2dkJAAMc3Pp/Iga0Pw+9nXMw7mvSzsI6jYZoSFndza+tZiwJKYNHlXQ+",
+	"RZP43aY4oLJjdGlZ1t5OuFQlsuSCNJTy4z7r77Dk86iOpD2DKGGBDT/eYxDDNOvL24RtgdF6bnWcLqJ2",
+	"RhPTxgHxKngZdRaCoWNovT03xDoHC2tu5QGxq6eDB67NJUD90PLpQj+ui4s1l+r0byd/O1ngkkIwgZ3a",
+	"P9VsntB4SOsf3MV88Jsv0BX8VtfkCX50QezBTxBlGPzdB/PDLw//NwAG6FkpQcwAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,75 @@
+"use client";
+
+import Link from "next/link";
+import { useState } from "react";
+import { api } from "@/lib/api";
+
+export default function ForgotPasswordPage() {
+  const [email, setEmail] = useState("");
+  const [isLoading, setIsLoading] = useState(false);
+  const [isSent, setIsSent] = useState(false);
+  const [error, setError] = useState("");
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setIsLoading(true);
+    setError("");
+
+    try {
+      await api.requestPasswordReset(email);
+      setIsSent(true);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Request failed");
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  return (
+    <div className="container mx-auto px-4 py-8 max-w-md">
+      <h1 className="text-2xl font-bold text-center mb-8">Reset Password</h1>
+
+      <div className="text-center mb-4">
+        <Link href="/login" className="text-primary hover:underline">
+          Back to sign in
+        </Link>
+      </div>
+
+      {error && (
+        <div className="bg-destructive/10 text-destructive p-3 rounded-md mb-4">
+          {error}
+        </div>
+      )}
+
+      {isSent ? (
+        <p className="text-center">
+          If an account exists for {email}, we have sent it a link to choose a new password.
+        </p>
+      ) : (
+        <form className="space-y-4" onSubmit={handleSubmit}>
+          <div>
+            <input
+              type="email"
+              placeholder="Email"
+              className="w-full p-2 border rounded-md"
+              required
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              disabled={isLoading}
+            />
+          </div>
+
+          <div>
+            <button
+              type="submit"
+              className="w-full bg-primary text-primary-foreground p-2 rounded-md hover:bg-primary/90 disabled:opacity-50"
+              disabled={isLoading}
+            >
+              {isLoading ? "Sending..." : "Send reset link"}
+            </button>
+          </div>
+        </form>
+      )}
+    </div>
+  );
+}
@@ -1,9 +1,9 @@
 "use client";
 
 import Link from "next/link";
-import { useState } from "react";
+import { useEffect, useState } from "react";
 import { useRouter } from "next/navigation";
-import { api } from "@/lib/api";
+import { api, authProviderLoginURL } from "@/lib/api";
 import { useAuth } from "@/contexts/auth-context";
 
 export default function LoginPage() {
@@ -11,8 +11,43 @@ export default function LoginPage() {
   const { login } = useAuth();
   const [email, setEmail] = useState("");
   const [password, setPassword] = useState("");
+  const [challenge, setChallenge] = useState("");
+  const [code, setCode] = useState("");
   const [isLoading, setIsLoading] = useState(false);
   const [error, setError] = useState("");
+  const [providers, setProviders] = useState<{ name: string; displayName: string }[]>([]);
+
+  useEffect(() => {
+    api.getAuthProviders()
+      .then((response) => setProviders(response.providers))
+      .catch(() => setProviders([]));
+  }, []);
+
+  // External identity providers send the user back here with the outcome in
+  // the URL fragment, which never reaches server logs
+  useEffect(() => {
+    const params = new URLSearchParams(window.location.hash.substring(1));
+    if (params.size === 0) {
+      return;
+    }
+    window.history.replaceState(null, "", window.location.pathname);
+
+    if (params.get("error")) {
+      setError(params.get("error") || "");
+    } else if (params.get("challenge")) {
+      setChallenge(params.get("challenge") || "");
+    } else if (params.get("token") || params.get("session")) {
+      const token = params.get("token");
+      setIsLoading(true);
+      api.getCurrentUser(token)
+        .then((response) => {
+          login({ ...response.user, token: token || response.user.token });
+          router.push("/");
+        })
+        .catch((err) => setError(err instanceof Error ? err.message : "Login failed"))
+        .finally(() => setIsLoading(false));
+    }
+  }, [login, router]);
 
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
@@ -20,10 +55,21 @@ export default function LoginPage() {
     setError("");
 
     try {
-      const response = await api.login(email, password);
+      // Accounts with two-factor authentication answer the password with a
+      // challenge that has to be completed with a code
+      if (challenge) {
+        const response = await api.loginTwoFactor(challenge, code);
+        login(response.user);
+      } else {
+        const response = await api.login(email, password);
+        if ("twoFactor" in response) {
+          setChallenge(response.twoFactor.token);
+          return;
+        }
 
-      // Update auth context with user data
-      login(response.user);
+        // Update auth context with user data
+        login(response.user);
+      }
 
       // Redirect to home page
       router.push("/");
@@ -42,6 +88,10 @@ export default function LoginPage() {
         <Link href="/register" className="text-primary hover:underline">
           Need an account?
         </Link>
+        <span className="mx-2 text-muted-foreground">·</span>
+        <Link href="/forgot-password" className="text-primary hover:underline">
+          Forgot password?
+        </Link>
       </div>
 
       {error && (
@@ -59,7 +109,7 @@ export default function LoginPage() {
             required
             value={email}
             onChange={(e) => setEmail(e.target.value)}
-            disabled={isLoading}
+            disabled={isLoading || !!challenge}
           />
         </div>
 
@@ -71,20 +121,51 @@ export default function LoginPage() {
             required
             value={password}
             onChange={(e) => setPassword(e.target.value)}
-            disabled={isLoading}
+            disabled={isLoading || !!challenge}
           />
         </div>
 
+        {challenge && (
+          <div>
+            <input
+              type="text"
+              inputMode="numeric"
+              autoComplete="one-time-code"
+              placeholder="Authentication or recovery code"
+              className="w-full p-2 border rounded-md"
+              required
+              autoFocus
+              value={code}
+              onChange={(e) => setCode(e.target.value)}
+              disabled={isLoading}
+            />
+          </div>
+        )}
+
         <div>
           <button
             type="submit"
             className="w-full bg-primary text-primary-foreground p-2 rounded-md hover:bg-primary/90 disabled:opacity-50"
             disabled={isLoading}
           >
-            {isLoading ? "Signing in..." : "Sign in"}
+            {isLoading ? "Signing in..." : challenge ? "Verify" : "Sign in"}
           </button>
         </div>
       </form>
+
+      {providers.length > 0 && !challenge && (
+        <div className="mt-6 space-y-2">
+          {providers.map((provider) => (
+            <a
+              key={provider.name}
+              href={authProviderLoginURL(provider.name)}
+              className="block w-full text-center border p-2 rounded-md hover:bg-muted"
+            >
+              Sign in with {provider.displayName}
+            </a>
+          ))}
+        </div>
+      )}
     </div>
   );
 }
@@ -0,0 +1,74 @@
+"use client";
+
+import Link from "next/link";
+import { useEffect, useState } from "react";
+import { useRouter } from "next/navigation";
+import { api } from "@/lib/api";
+
+export default function ResetPasswordPage() {
+  const router = useRouter();
+  const [token, setToken] = useState("");
+  const [password, setPassword] = useState("");
+  const [isLoading, setIsLoading] = useState(false);
+  const [error, setError] = useState("");
+
+  // The token arrives in the query string of the emailed link
+  useEffect(() => {
+    setToken(new URLSearchParams(window.location.search).get("token") || "");
+  }, []);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setIsLoading(true);
+    setError("");
+
+    try {
+      await api.confirmPasswordReset(token, password);
+      router.push("/login");
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Password reset failed");
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  return (
+    <div className="container mx-auto px-4 py-8 max-w-md">
+      <h1 className="text-2xl font-bold text-center mb-8">Choose a New Password</h1>
+
+      {error && (
+        <div className="bg-destructive/10 text-destructive p-3 rounded-md mb-4">
+          {error}{" "}
+          <Link href="/forgot-password" className="underline">
+            Request a new link
+          </Link>
+        </div>
+      )}
+
+      <form className="space-y-4" onSubmit={handleSubmit}>
+        <div>
+          <input
+            type="password"
+            placeholder="New password"
+            className="w-full p-2 border rounded-md"
+            required
+            autoComplete="new-password"
+            value={password}
+            onChange={(e) => setPassword(e.target.value)}
+            disabled={isLoading}
+          />
+        </div>
+
+        <div>
+          <button
+            type="submit"
+            className="w-full bg-primary text-primary-foreground p-2 rounded-md hover:bg-primary/90 disabled:opacity-50"
+            disabled={isLoading || !token}
+          >
+            {isLoading ? "Saving..." : "Set password"}
+          </button>
+        </div>
+      </form>
+    </div>
+  );
+}
@@ -0,0 +1,38 @@
+"use client";
+
+import Link from "next/link";
+import { useEffect, useState } from "react";
+import { api } from "@/lib/api";
+
+export default function VerifyEmailPage() {
+  const [status, setStatus] = useState<"verifying" | "verified" | "failed">("verifying");
+
+  // Verify as soon as the emailed link is opened
+  useEffect(() => {
+    const token = new URLSearchParams(window.location.search).get("token") || "";
+    api
+      .verifyEmail(token)
+      .then(() => setStatus("verified"))
+      .catch(() => setStatus("failed"));
+  }, []);
+
+  return (
+    <div className="container mx-auto px-4 py-8 max-w-md text-center">
+      <h1 className="text-2xl font-bold mb-8">Verify Email</h1>
+
+      {status === "verifying" && <p>Verifying your email address...</p>}
+      {status === "verified" && <p>Your email address has been verified.</p>}
+      {status === "failed" && (
+        <p className="text-destructive">
+          This link is invalid or has expired. You can request a new one in your settings.
+        </p>
+      )}
+
+      <div className="mt-4">
+        <Link href="/" className="text-primary hover:underline">
+          Go to home page
+        </Link>
+      </div>
+    </div>
+  );
+}
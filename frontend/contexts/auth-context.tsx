@@ -2,6 +2,7 @@
 
 import React, { createContext, useContext, useState, useEffect, ReactNode } from "react";
 import { getCurrentUser, storeUserData, logout as authLogout } from "@/lib/auth";
+import { api } from "@/lib/api";
 
 // Define the User interface
 interface User {
@@ -57,6 +58,8 @@ export function AuthProvider({ children }: { children: ReactNode }) {
   const logout = () => {
     authLogout();
     setUser(null);
+    // Expire the session cookies if the server uses them
+    api.logout().catch(() => {});
   };
 
   return (
@@ -3,12 +3,16 @@
 // Base URL for the API
 const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api';
 
+// Name of the cookie holding the CSRF token when the server runs in cookie mode
+const CSRF_COOKIE_NAME = 'conduit_csrf';
+
 // Define interfaces for API responses based on the OpenAPI spec
 interface Profile {
   username: string;
   bio: string;
   image: string;
   following: boolean;
+  followedTags?: string[];
 }
 
 interface Article {
@@ -16,12 +20,15 @@ interface Article {
   title: string;
   description: string;
   body: string;
+  bodyHtml?: string;
   tagList: string[];
   createdAt: string;
   updatedAt: string;
   favorited: boolean;
   favoritesCount: number;
   author: Profile;
+  status: 'draft' | 'scheduled' | 'published' | 'unlisted';
+  publishAt?: string;
 }
 
 interface MultipleArticlesResponse {
@@ -49,6 +56,24 @@ interface UserResponse {
   user: User;
 }
 
+// Returned by login instead of a user when the account has two-factor authentication
+interface TwoFactorChallengeResponse {
+  twoFactor: {
+    token: string;
+    expiresAt: string;
+  };
+}
+
+// External identity provider users can sign in with
+interface AuthProvider {
+  name: string;
+  displayName: string;
+}
+
+interface AuthProvidersResponse {
+  providers: AuthProvider[];
+}
+
 // Type for URL search params to avoid 'as any' cast
 type SearchParamsObject = Record<string, string | number | undefined>;
 
@@ -64,6 +89,23 @@ function convertToURLSearchParams(params: SearchParamsObject): Record<string, st
   return result;
 }
 
+// Read a cookie set by the server, if present
+function getCookie(name: string): string | undefined {
+  if (typeof document === 'undefined') {
+    return undefined;
+  }
+
+  const prefix = `${name}=`;
+  const cookie = document.cookie.split('; ').find((c) => c.startsWith(prefix));
+  return cookie ? decodeURIComponent(cookie.substring(prefix.length)) : undefined;
+}
+
+// Build the Authorization header for a token. In cookie mode the server
+// doesn't hand out tokens and the session cookie is used instead.
+function authHeader(token?: string | null): Record<string, string> {
+  return token ? { Authorization: `Token ${token}` } : {};
+}
+
 // Generic fetch function with error handling
 async function fetchAPI<T>(
   endpoint: string,
@@ -71,14 +113,22 @@ async function fetchAPI<T>(
 ): Promise<T> {
   const url = `${API_BASE_URL}${endpoint}`;
 
-  const headers = {
+  const headers: Record<string, string> = {
     'Content-Type': 'application/json',
-    ...options.headers,
+    ...(options.headers as Record<string, string>),
   };
 
+  // State-changing requests echo the CSRF cookie when the server uses cookie sessions
+  const method = (options.method || 'GET').toUpperCase();
+  const csrfToken = getCookie(CSRF_COOKIE_NAME);
+  if (csrfToken && !['GET', 'HEAD', 'OPTIONS'].includes(method)) {
+    headers['X-CSRF-Token'] = csrfToken;
+  }
+
   const response = await fetch(url, {
     ...options,
     headers,
+    credentials: 'include',
   });
 
   if (!response.ok) {
@@ -86,7 +136,14 @@ async function fetchAPI<T>(
     throw new Error(error.message || 'An error occurred while fetching the data.');
   }
 
-  return response.json();
+  // Some endpoints answer without a body
+  const text = await response.text();
+  return text ? JSON.parse(text) : (undefined as T);
+}
+
+// URL that starts the sign in with an external identity provider
+export function authProviderLoginURL(name: string): string {
+  return `${API_BASE_URL}/auth/${encodeURIComponent(name)}/login`;
 }
 
 // API functions for different endpoints
@@ -99,20 +156,18 @@ export const api = {
     fetchAPI<SingleArticleResponse>(`/articles/${slug}`),
 
   // Tags
-  getTags: () => 
-    fetchAPI<TagsResponse>('/tags'),
+  getTags: (params?: { order?: 'count' | 'recent'; limit?: number }) => 
+    fetchAPI<TagsResponse>('/tags' + (params ? `?${new URLSearchParams(convertToURLSearchParams(params)).toString()}` : '')),
 
   // User
-  getCurrentUser: (token: string) => 
+  getCurrentUser: (token?: string | null) => 
     fetchAPI<UserResponse>('/user', {
-      headers: {
-        Authorization: `Token ${token}`,
-      },
+      headers: authHeader(token),
     }),
 
   // Authentication
   login: (email: string, password: string) => 
-    fetchAPI<UserResponse>('/users/login', {
+    fetchAPI<UserResponse | TwoFactorChallengeResponse>('/users/login', {
       method: 'POST',
       body: JSON.stringify({
         user: {
@@ -122,6 +177,56 @@ export const api = {
       }),
     }),
 
+  getAuthProviders: () => 
+    fetchAPI<AuthProvidersResponse>('/auth/providers'),
+
+  loginTwoFactor: (challenge: string, code: string) => 
+    fetchAPI<UserResponse>('/users/login/two-factor', {
+      method: 'POST',
+      body: JSON.stringify({
+        twoFactor: {
+          challenge,
+          code,
+        },
+      }),
+    }),
+
+  requestPasswordReset: (email: string) => 
+    fetchAPI<void>('/users/password-reset', {
+      method: 'POST',
+      body: JSON.stringify({
+        user: {
+          email,
+        },
+      }),
+    }),
+
+  confirmPasswordReset: (token: string, password: string) => 
+    fetchAPI<void>('/users/password-reset/confirm', {
+      method: 'POST',
+      body: JSON.stringify({
+        user: {
+          token,
+          password,
+        },
+      }),
+    }),
+
+  verifyEmail: (token: string) => 
+    fetchAPI<void>('/users/verify-email', {
+      method: 'POST',
+      body: JSON.stringify({
+        user: {
+          token,
+        },
+      }),
+    }),
+
+  logout: () => 
+    fetchAPI<void>('/users/logout', {
+      method: 'POST',
+    }),
+
   register: (username: string, email: string, password: string) => 
     fetchAPI<UserResponse>('/users', {
       method: 'POST',
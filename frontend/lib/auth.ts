@@ -44,10 +44,11 @@ export function getToken() {
 }
 
 /**
- * Check if a user is logged in
+ * Check if a user is logged in. In cookie mode there is no token in
+ * localStorage, the stored user marks an active session instead.
  */
 export function isLoggedIn() {
-  return !!getToken();
+  return !!getToken() || !!getCurrentUser();
 }
 
 /**
@@ -63,13 +64,18 @@ export function logout() {
 }
 
 /**
- * Store user data and token in localStorage
+ * Store user data and token in localStorage. The token is empty when the
+ * server keeps it in an HttpOnly session cookie.
  */
 export function storeUserData(user: User) {
   if (typeof window === 'undefined') {
     return;
   }
 
-  localStorage.setItem("token", user.token);
+  if (user.token) {
+    localStorage.setItem("token", user.token);
+  } else {
+    localStorage.removeItem("token");
+  }
   localStorage.setItem("user", JSON.stringify(user));
 }
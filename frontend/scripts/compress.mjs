@@ -0,0 +1,46 @@
+// Writes Brotli (.br) and gzip (.gz) variants of the text files in the static
+// export, which the Go server serves to clients accepting them.
+import { readdir, readFile, writeFile } from "node:fs/promises";
+import { join, extname } from "node:path";
+import { brotliCompressSync, gzipSync, constants } from "node:zlib";
+
+const distDir = new URL("../dist/", import.meta.url).pathname;
+const extensions = new Set([".html", ".js", ".css", ".json", ".svg", ".txt", ".xml", ".map"]);
+
+// Small files don't get smaller enough to be worth a variant
+const minSize = 1024;
+
+async function* files(dir) {
+  for (const entry of await readdir(dir, { withFileTypes: true })) {
+    const path = join(dir, entry.name);
+    if (entry.isDirectory()) {
+      yield* files(path);
+    } else if (extensions.has(extname(entry.name))) {
+      yield path;
+    }
+  }
+}
+
+let count = 0;
+for await (const path of files(distDir)) {
+  const content = await readFile(path);
+  if (content.length < minSize) {
+    continue;
+  }
+
+  const variants = {
+    ".br": brotliCompressSync(content, {
+      params: { [constants.BROTLI_PARAM_QUALITY]: constants.BROTLI_MAX_QUALITY },
+    }),
+    ".gz": gzipSync(content, { level: 9 }),
+  };
+  for (const [suffix, compressed] of Object.entries(variants)) {
+    // Only keep variants that save bytes
+    if (compressed.length < content.length) {
+      await writeFile(path + suffix, compressed);
+    }
+  }
+  count++;
+}
+
+console.log(`Precompressed ${count} files in ${distDir}`);
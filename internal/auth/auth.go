@@ -26,6 +26,19 @@ type Config struct {
 	Secret string
 	// TokenExpiry is the duration for which a token is valid
 	TokenExpiry time.Duration
+	// Throttle configures login attempt limiting and account lockout
+	Throttle ThrottleConfig
+	// AdminEmails lists bootstrap admins, accounts that get the admin role
+	// once they verified their email address
+	AdminEmails []string
+	// Mode selects whether clients authenticate with a header token or a session cookie
+	Mode Mode
+	// Cookie configures the session and CSRF cookies used in cookie mode
+	Cookie CookieConfig
+	// ChallengeExpiry is how long a two-factor login challenge stays valid
+	ChallengeExpiry time.Duration
+	// TOTPIssuer is the issuer name shown in authenticator apps
+	TOTPIssuer string
 }
 
 // DefaultConfig returns a default configuration
@@ -33,12 +46,20 @@ func DefaultConfig() Config {
 	return Config{
 		Secret:      "your-secret-key", // In production, this should be set via environment variables
 		TokenExpiry: 24 * time.Hour,    // 24 hours
+		Throttle:    DefaultThrottleConfig(),
+		Mode:        ModeToken,
+		Cookie:      DefaultCookieConfig(),
+
+		ChallengeExpiry: defaultChallengeExpiry,
+		TOTPIssuer:      "Conduit",
 	}
 }
 
 // Claims represents the JWT claims
 type Claims struct {
 	Email string `json:"email"`
+	// Purpose restricts what the token can be used for, empty for session tokens
+	Purpose string `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -64,6 +85,33 @@ func GenerateToken(email string, config Config) (string, error) {
 
 // ValidateToken validates a JWT token and returns the email if valid
 func ValidateToken(tokenString string, config Config) (string, error) {
+	claims, err := ValidateTokenClaims(tokenString, config)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Email, nil
+}
+
+// ValidateTokenClaims validates a JWT token like ValidateToken, but returns
+// all of its claims
+func ValidateTokenClaims(tokenString string, config Config) (*Claims, error) {
+	claims, err := parseClaims(tokenString, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens issued for a specific purpose, like two-factor challenges,
+	// don't grant access to the API
+	if claims.Purpose != "" {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// parseClaims verifies the signature and expiry of a JWT token and returns its claims
+func parseClaims(tokenString string, config Config) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
@@ -75,16 +123,16 @@ func ValidateToken(tokenString string, config Config) (string, error) {
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return "", ErrExpiredToken
+			return nil, ErrExpiredToken
 		}
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	if !token.Valid {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
-	return claims.Email, nil
+	return claims, nil
 }
 
 // ExtractTokenFromRequest extracts the JWT token from the Authorization header
@@ -32,6 +32,15 @@ func TestGenerateAndValidateToken(t *testing.T) {
 	if validatedEmail != email {
 		t.Errorf("Validated email does not match original. Got %s, expected %s", validatedEmail, email)
 	}
+
+	// The claims include when the token was issued
+	claims, err := ValidateTokenClaims(token, config)
+	if err != nil {
+		t.Fatalf("Failed to validate token: %v", err)
+	}
+	if claims.Email != email || claims.IssuedAt == nil {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
 }
 
 func TestValidateTokenWithInvalidToken(t *testing.T) {
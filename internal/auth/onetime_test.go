@@ -0,0 +1,26 @@
+package auth
+
+import "testing"
+
+func TestOneTimeToken(t *testing.T) {
+	token, err := GenerateOneTimeToken()
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if len(token) != 43 {
+		t.Errorf("Expected 43 characters, got %d", len(token))
+	}
+
+	other, _ := GenerateOneTimeToken()
+	if token == other {
+		t.Error("Expected tokens to be random")
+	}
+
+	// Hashes are stable and differ from the token
+	if HashOneTimeToken(token) != HashOneTimeToken(token) {
+		t.Error("Expected hash to be deterministic")
+	}
+	if HashOneTimeToken(token) == token || HashOneTimeToken(token) == HashOneTimeToken(other) {
+		t.Error("Expected distinct hashes")
+	}
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"slices"
+	"strings"
+)
+
+// PersonalTokenPrefix marks personal access tokens, so that they can be told
+// apart from JWTs and recognized by secret scanners
+const PersonalTokenPrefix = "cdt_"
+
+// Scopes of personal access tokens
+const (
+	// ScopeUserRead allows reading the account of the token owner
+	ScopeUserRead = "user:read"
+	// ScopeArticlesWrite allows creating, updating and deleting articles
+	ScopeArticlesWrite = "articles:write"
+	// ScopeCommentsWrite allows adding and deleting comments
+	ScopeCommentsWrite = "comments:write"
+	// ScopeFavoritesWrite allows favoriting and unfavoriting articles
+	ScopeFavoritesWrite = "favorites:write"
+	// ScopeProfilesWrite allows following and unfollowing users and tags
+	ScopeProfilesWrite = "profiles:write"
+)
+
+// Scopes lists all scopes a personal access token can be granted
+var Scopes = []string{
+	ScopeUserRead,
+	ScopeArticlesWrite,
+	ScopeCommentsWrite,
+	ScopeFavoritesWrite,
+	ScopeProfilesWrite,
+}
+
+// ValidScope reports whether scope is a known scope
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// GeneratePersonalToken generates a random personal access token. Like
+// one-time tokens, only its hash (see HashOneTimeToken) is meant to be stored.
+func GeneratePersonalToken() (string, error) {
+	token, err := GenerateOneTimeToken()
+	if err != nil {
+		return "", err
+	}
+	return PersonalTokenPrefix + token, nil
+}
+
+// IsPersonalToken reports whether a token from a request is a personal access
+// token rather than a JWT
+func IsPersonalToken(token string) bool {
+	return strings.HasPrefix(token, PersonalTokenPrefix)
+}
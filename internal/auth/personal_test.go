@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePersonalToken(t *testing.T) {
+	token, err := GeneratePersonalToken()
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if !strings.HasPrefix(token, PersonalTokenPrefix) || !IsPersonalToken(token) {
+		t.Errorf("Expected token with prefix %s, got %s", PersonalTokenPrefix, token)
+	}
+
+	other, _ := GeneratePersonalToken()
+	if token == other {
+		t.Error("Expected tokens to be random")
+	}
+
+	// JWTs are not personal tokens
+	jwt, _ := GenerateToken("test@example.com", DefaultConfig())
+	if IsPersonalToken(jwt) {
+		t.Error("Expected JWT not to be a personal token")
+	}
+}
+
+func TestValidScope(t *testing.T) {
+	for _, scope := range Scopes {
+		if !ValidScope(scope) {
+			t.Errorf("Expected %s to be valid", scope)
+		}
+	}
+	for _, scope := range []string{"", "admin", "articles:read", "ARTICLES:WRITE"} {
+		if ValidScope(scope) {
+			t.Errorf("Expected %q to be invalid", scope)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"time"
+)
+
+// Mode selects how clients present their credentials
+type Mode string
+
+const (
+	// ModeToken expects the JWT in the Authorization header
+	ModeToken Mode = "token"
+	// ModeCookie keeps the JWT in an HttpOnly session cookie protected by a CSRF token
+	ModeCookie Mode = "cookie"
+)
+
+// CSRFHeader is the header that has to echo the CSRF cookie on unsafe requests
+const CSRFHeader = "X-CSRF-Token"
+
+// CookieConfig holds the configuration for session and CSRF cookies
+type CookieConfig struct {
+	// SessionName is the name of the HttpOnly cookie holding the JWT
+	SessionName string
+	// CSRFName is the name of the cookie holding the CSRF token
+	CSRFName string
+	// Domain is the cookie domain, empty for the host of the request
+	Domain string
+	// Path is the cookie path
+	Path string
+	// Secure restricts the cookies to HTTPS
+	Secure bool
+	// SameSite is the SameSite attribute of the cookies
+	SameSite http.SameSite
+}
+
+// DefaultCookieConfig returns a default cookie configuration
+func DefaultCookieConfig() CookieConfig {
+	return CookieConfig{
+		SessionName: "conduit_session",
+		CSRFName:    "conduit_csrf",
+		Path:        "/",
+		Secure:      true,
+		SameSite:    http.SameSiteLaxMode,
+	}
+}
+
+// GenerateCSRFToken generates a random CSRF token
+func GenerateCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// SetSessionCookies stores the token in an HttpOnly session cookie and sets a
+// fresh CSRF cookie that scripts can read and echo in the CSRF header
+func SetSessionCookies(w http.ResponseWriter, token string, config Config) error {
+	csrfToken, err := GenerateCSRFToken()
+	if err != nil {
+		return err
+	}
+
+	expires := time.Now().Add(config.TokenExpiry)
+	http.SetCookie(w, &http.Cookie{
+		Name:     config.Cookie.SessionName,
+		Value:    token,
+		Domain:   config.Cookie.Domain,
+		Path:     config.Cookie.Path,
+		Expires:  expires,
+		MaxAge:   int(config.TokenExpiry.Seconds()),
+		Secure:   config.Cookie.Secure,
+		HttpOnly: true,
+		SameSite: config.Cookie.SameSite,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     config.Cookie.CSRFName,
+		Value:    csrfToken,
+		Domain:   config.Cookie.Domain,
+		Path:     config.Cookie.Path,
+		Expires:  expires,
+		MaxAge:   int(config.TokenExpiry.Seconds()),
+		Secure:   config.Cookie.Secure,
+		HttpOnly: false,
+		SameSite: config.Cookie.SameSite,
+	})
+
+	return nil
+}
+
+// ClearSessionCookies expires the session and CSRF cookies
+func ClearSessionCookies(w http.ResponseWriter, config Config) {
+	for _, name := range []string{config.Cookie.SessionName, config.Cookie.CSRFName} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Domain:   config.Cookie.Domain,
+			Path:     config.Cookie.Path,
+			MaxAge:   -1,
+			Secure:   config.Cookie.Secure,
+			HttpOnly: name == config.Cookie.SessionName,
+			SameSite: config.Cookie.SameSite,
+		})
+	}
+}
+
+// ExtractTokenFromCookie extracts the JWT token from the session cookie
+func ExtractTokenFromCookie(r *http.Request, config Config) (string, error) {
+	cookie, err := r.Cookie(config.Cookie.SessionName)
+	if err != nil || cookie.Value == "" {
+		return "", ErrInvalidToken
+	}
+	return cookie.Value, nil
+}
+
+// VerifyCSRF checks the double-submit CSRF token of a cookie-authenticated
+// request. Safe methods don't change state and always pass.
+func VerifyCSRF(r *http.Request, config Config) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	cookie, err := r.Cookie(config.Cookie.CSRFName)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	header := r.Header.Get(CSRFHeader)
+
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) == 1
+}
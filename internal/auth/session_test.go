@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// cookieTestConfig returns a config with cookie mode enabled
+func cookieTestConfig() Config {
+	return Config{
+		Secret:      "test-secret-key",
+		TokenExpiry: 1 * time.Hour,
+		Mode:        ModeCookie,
+		Cookie:      DefaultCookieConfig(),
+	}
+}
+
+func TestSetSessionCookies(t *testing.T) {
+	config := cookieTestConfig()
+
+	rr := httptest.NewRecorder()
+	if err := SetSessionCookies(rr, "jwt-token", config); err != nil {
+		t.Fatalf("Failed to set session cookies: %v", err)
+	}
+
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range rr.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+
+	session := cookies[config.Cookie.SessionName]
+	if session == nil {
+		t.Fatal("Expected session cookie to be set")
+	}
+	if session.Value != "jwt-token" {
+		t.Errorf("Expected session cookie to hold the token, got %q", session.Value)
+	}
+	if !session.HttpOnly || !session.Secure || session.SameSite != http.SameSiteLaxMode {
+		t.Errorf("Expected HttpOnly, Secure, SameSite=Lax session cookie, got %+v", session)
+	}
+
+	csrf := cookies[config.Cookie.CSRFName]
+	if csrf == nil {
+		t.Fatal("Expected CSRF cookie to be set")
+	}
+	if csrf.Value == "" {
+		t.Error("Expected non-empty CSRF token")
+	}
+	if csrf.HttpOnly {
+		t.Error("Expected CSRF cookie to be readable by scripts")
+	}
+}
+
+func TestClearSessionCookies(t *testing.T) {
+	config := cookieTestConfig()
+
+	rr := httptest.NewRecorder()
+	ClearSessionCookies(rr, config)
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("Expected 2 cookies, got %d", len(cookies))
+	}
+	for _, cookie := range cookies {
+		if cookie.MaxAge >= 0 || cookie.Value != "" {
+			t.Errorf("Expected cookie %s to be expired, got %+v", cookie.Name, cookie)
+		}
+	}
+}
+
+func TestExtractTokenFromCookie(t *testing.T) {
+	config := cookieTestConfig()
+
+	req, _ := http.NewRequest("GET", "/api/user", nil)
+	if _, err := ExtractTokenFromCookie(req, config); err != ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken without cookie, got %v", err)
+	}
+
+	req.AddCookie(&http.Cookie{Name: config.Cookie.SessionName, Value: "jwt-token"})
+	token, err := ExtractTokenFromCookie(req, config)
+	if err != nil {
+		t.Fatalf("Failed to extract token: %v", err)
+	}
+	if token != "jwt-token" {
+		t.Errorf("Expected token jwt-token, got %q", token)
+	}
+}
+
+func TestVerifyCSRF(t *testing.T) {
+	config := cookieTestConfig()
+
+	tests := []struct {
+		name     string
+		method   string
+		cookie   string
+		header   string
+		expected bool
+	}{
+		{name: "Safe method", method: "GET", expected: true},
+		{name: "Matching token", method: "POST", cookie: "abc", header: "abc", expected: true},
+		{name: "Missing header", method: "PUT", cookie: "abc", expected: false},
+		{name: "Missing cookie", method: "DELETE", header: "abc", expected: false},
+		{name: "Mismatching token", method: "POST", cookie: "abc", header: "xyz", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "/api/articles", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: config.Cookie.CSRFName, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(CSRFHeader, tt.header)
+			}
+
+			if result := VerifyCSRF(req, config); result != tt.expected {
+				t.Errorf("VerifyCSRF() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
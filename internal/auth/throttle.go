@@ -0,0 +1,324 @@
+package auth
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTooManyAttempts is returned when a login must wait for its backoff delay to pass
+	ErrTooManyAttempts = errors.New("too many login attempts")
+	// ErrAccountLocked is returned when an account is temporarily locked
+	ErrAccountLocked = errors.New("account locked")
+)
+
+// ThrottleConfig holds the configuration for login attempt limiting
+type ThrottleConfig struct {
+	// AccountFreeAttempts is the number of failures per account before backoff starts
+	AccountFreeAttempts int
+	// IPFreeAttempts is the number of failures per client IP before backoff starts
+	IPFreeAttempts int
+	// BaseDelay is the first backoff delay, doubled with every further failure
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay
+	MaxDelay time.Duration
+	// LockoutThreshold is the number of account failures that locks the account
+	LockoutThreshold int
+	// LockoutDuration is how long a locked account stays locked
+	LockoutDuration time.Duration
+	// ResetAfter is the quiet period after which failure counters are forgotten
+	ResetAfter time.Duration
+	// AuditSize is the number of failed attempts kept in the audit trail
+	AuditSize int
+}
+
+// DefaultThrottleConfig returns a default login throttle configuration
+func DefaultThrottleConfig() ThrottleConfig {
+	return ThrottleConfig{
+		AccountFreeAttempts: 3,
+		IPFreeAttempts:      10,
+		BaseDelay:           time.Second,
+		MaxDelay:            15 * time.Minute,
+		LockoutThreshold:    10,
+		LockoutDuration:     30 * time.Minute,
+		ResetAfter:          time.Hour,
+		AuditSize:           1000,
+	}
+}
+
+// FailedAttempt is an entry of the failed login audit trail
+type FailedAttempt struct {
+	Email  string
+	IP     string
+	Reason string
+	Time   time.Time
+}
+
+// Lockout describes a temporarily locked account
+type Lockout struct {
+	Email       string
+	Failures    int
+	LockedUntil time.Time
+}
+
+// attemptCounter tracks consecutive failures for a single account or IP
+type attemptCounter struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// LoginThrottle limits login attempts per account and per client IP using
+// exponential backoff, and locks accounts after repeated failures. Counters
+// that have been quiet for ResetAfter are swept at most once per ResetAfter.
+type LoginThrottle struct {
+	config    ThrottleConfig
+	accounts  map[string]*attemptCounter // key: normalized email
+	ips       map[string]*attemptCounter // key: client IP
+	audit     []FailedAttempt
+	lastSweep time.Time
+	now       func() time.Time
+	mutex     sync.Mutex
+}
+
+// NewLoginThrottle creates a new login throttle, using defaults for unset fields
+func NewLoginThrottle(config ThrottleConfig) *LoginThrottle {
+	defaults := DefaultThrottleConfig()
+	if config.AccountFreeAttempts <= 0 {
+		config.AccountFreeAttempts = defaults.AccountFreeAttempts
+	}
+	if config.IPFreeAttempts <= 0 {
+		config.IPFreeAttempts = defaults.IPFreeAttempts
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = defaults.BaseDelay
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = defaults.MaxDelay
+	}
+	if config.LockoutThreshold <= 0 {
+		config.LockoutThreshold = defaults.LockoutThreshold
+	}
+	if config.LockoutDuration <= 0 {
+		config.LockoutDuration = defaults.LockoutDuration
+	}
+	if config.ResetAfter <= 0 {
+		config.ResetAfter = defaults.ResetAfter
+	}
+	if config.AuditSize <= 0 {
+		config.AuditSize = defaults.AuditSize
+	}
+
+	return &LoginThrottle{
+		config:   config,
+		accounts: make(map[string]*attemptCounter),
+		ips:      make(map[string]*attemptCounter),
+		now:      time.Now,
+	}
+}
+
+// normalizeEmail makes account keys case-insensitive
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Check reports whether a login attempt for the given account and IP may proceed.
+// If not, it returns ErrAccountLocked or ErrTooManyAttempts and the time to wait.
+func (t *LoginThrottle) Check(email, ip string) (time.Duration, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+
+	// Locked accounts are rejected until the lockout expires
+	if counter := t.counter(t.accounts, normalizeEmail(email), now, false); counter != nil {
+		if now.Before(counter.lockedUntil) {
+			return counter.lockedUntil.Sub(now), ErrAccountLocked
+		}
+		if wait := t.wait(counter, t.config.AccountFreeAttempts, now); wait > 0 {
+			return wait, ErrTooManyAttempts
+		}
+	}
+
+	if counter := t.counter(t.ips, ip, now, false); counter != nil {
+		if wait := t.wait(counter, t.config.IPFreeAttempts, now); wait > 0 {
+			return wait, ErrTooManyAttempts
+		}
+	}
+
+	return 0, nil
+}
+
+// Failure records a failed login attempt for an existing account and adds it
+// to the audit trail
+func (t *LoginThrottle) Failure(email, ip, reason string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	counter := t.counter(t.accounts, normalizeEmail(email), now, true)
+	counter.failures++
+	counter.lastFailure = now
+	if counter.failures >= t.config.LockoutThreshold {
+		counter.lockedUntil = now.Add(t.config.LockoutDuration)
+	}
+
+	t.record(email, ip, reason, now)
+}
+
+// UnknownAccountFailure records a failed login attempt for an email without
+// an account. Only the client IP is counted, so that made up emails don't
+// fill the throttle with account counters.
+func (t *LoginThrottle) UnknownAccountFailure(email, ip string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.record(email, ip, "unknown account", t.now())
+}
+
+// record counts a failure of the client IP and adds it to the audit trail.
+// The caller must hold the lock.
+func (t *LoginThrottle) record(email, ip, reason string, now time.Time) {
+	if ip != "" {
+		ipCounter := t.counter(t.ips, ip, now, true)
+		ipCounter.failures++
+		ipCounter.lastFailure = now
+	}
+
+	// Keep the audit trail bounded
+	t.audit = append(t.audit, FailedAttempt{
+		Email:  normalizeEmail(email),
+		IP:     ip,
+		Reason: reason,
+		Time:   now,
+	})
+	if len(t.audit) > t.config.AuditSize {
+		t.audit = t.audit[len(t.audit)-t.config.AuditSize:]
+	}
+
+	// Forget counters that are never looked up again
+	if now.Sub(t.lastSweep) > t.config.ResetAfter {
+		t.sweep(t.accounts, now)
+		t.sweep(t.ips, now)
+		t.lastSweep = now
+	}
+}
+
+// Success resets the failure counter of an account after a successful login
+func (t *LoginThrottle) Success(email string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.accounts, normalizeEmail(email))
+}
+
+// Unlock lifts the lockout of an account and resets its failure counter.
+// It reports whether the account had any recorded failures.
+func (t *LoginThrottle) Unlock(email string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	account := normalizeEmail(email)
+	if _, exists := t.accounts[account]; !exists {
+		return false
+	}
+	delete(t.accounts, account)
+
+	return true
+}
+
+// Lockouts returns all currently locked accounts
+func (t *LoginThrottle) Lockouts() []Lockout {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	lockouts := []Lockout{}
+	for email, counter := range t.accounts {
+		if now.Before(counter.lockedUntil) {
+			lockouts = append(lockouts, Lockout{
+				Email:       email,
+				Failures:    counter.failures,
+				LockedUntil: counter.lockedUntil,
+			})
+		}
+	}
+
+	sort.Slice(lockouts, func(i, j int) bool {
+		return lockouts[i].Email < lockouts[j].Email
+	})
+
+	return lockouts
+}
+
+// FailedAttempts returns the audit trail of failed attempts, newest first
+func (t *LoginThrottle) FailedAttempts() []FailedAttempt {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	attempts := make([]FailedAttempt, len(t.audit))
+	for i, attempt := range t.audit {
+		attempts[len(t.audit)-1-i] = attempt
+	}
+
+	return attempts
+}
+
+// expired reports whether a counter has been quiet for longer than
+// ResetAfter and isn't locked
+func (t *LoginThrottle) expired(counter *attemptCounter, now time.Time) bool {
+	return now.Sub(counter.lastFailure) > t.config.ResetAfter && !now.Before(counter.lockedUntil)
+}
+
+// sweep deletes the expired counters
+func (t *LoginThrottle) sweep(counters map[string]*attemptCounter, now time.Time) {
+	for key, counter := range counters {
+		if t.expired(counter, now) {
+			delete(counters, key)
+		}
+	}
+}
+
+// counter returns the counter for key, forgetting it once it has been quiet
+// for longer than ResetAfter. If create is set, a missing counter is created.
+func (t *LoginThrottle) counter(counters map[string]*attemptCounter, key string, now time.Time, create bool) *attemptCounter {
+	counter, exists := counters[key]
+	if exists && t.expired(counter, now) {
+		delete(counters, key)
+		exists = false
+	}
+	if !exists {
+		if !create {
+			return nil
+		}
+		counter = &attemptCounter{}
+		counters[key] = counter
+	}
+
+	return counter
+}
+
+// wait returns how long the next attempt has to wait given the failures so far
+func (t *LoginThrottle) wait(counter *attemptCounter, free int, now time.Time) time.Duration {
+	if counter.failures < free {
+		return 0
+	}
+
+	delay := t.config.BaseDelay
+	for i := free; i < counter.failures && delay < t.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > t.config.MaxDelay {
+		delay = t.config.MaxDelay
+	}
+
+	retryAt := counter.lastFailure.Add(delay)
+	if !now.Before(retryAt) {
+		return 0
+	}
+
+	return retryAt.Sub(now)
+}
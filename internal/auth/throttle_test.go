@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestThrottle creates a throttle with a controllable clock
+func newTestThrottle(config ThrottleConfig) (*LoginThrottle, *time.Time) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	throttle := NewLoginThrottle(config)
+	throttle.now = func() time.Time { return now }
+	return throttle, &now
+}
+
+func TestLoginThrottleBackoff(t *testing.T) {
+	throttle, now := newTestThrottle(ThrottleConfig{
+		AccountFreeAttempts: 2,
+		IPFreeAttempts:      100,
+		BaseDelay:           time.Second,
+		MaxDelay:            time.Minute,
+		LockoutThreshold:    100,
+	})
+
+	// The free attempts are not delayed
+	for i := 0; i < 2; i++ {
+		if _, err := throttle.Check("test@example.com", "1.2.3.4"); err != nil {
+			t.Fatalf("Expected attempt %d to be allowed, got %v", i+1, err)
+		}
+		throttle.Failure("test@example.com", "1.2.3.4", "invalid password")
+	}
+
+	// The third attempt has to wait for the base delay
+	wait, err := throttle.Check("test@example.com", "1.2.3.4")
+	if err != ErrTooManyAttempts {
+		t.Fatalf("Expected ErrTooManyAttempts, got %v", err)
+	}
+	if wait != time.Second {
+		t.Errorf("Expected wait of 1s, got %v", wait)
+	}
+
+	// After waiting, one more failure doubles the delay
+	*now = now.Add(time.Second)
+	if _, err := throttle.Check("test@example.com", "1.2.3.4"); err != nil {
+		t.Fatalf("Expected attempt after delay to be allowed, got %v", err)
+	}
+	throttle.Failure("test@example.com", "1.2.3.4", "invalid password")
+	wait, _ = throttle.Check("test@example.com", "1.2.3.4")
+	if wait != 2*time.Second {
+		t.Errorf("Expected wait of 2s, got %v", wait)
+	}
+
+	// Email addresses are compared case-insensitively
+	if _, err := throttle.Check("TEST@example.com", "5.6.7.8"); err != ErrTooManyAttempts {
+		t.Errorf("Expected ErrTooManyAttempts for differently cased email, got %v", err)
+	}
+
+	// A successful login resets the account
+	throttle.Success("test@example.com")
+	if _, err := throttle.Check("test@example.com", "1.2.3.4"); err != nil {
+		t.Errorf("Expected attempt after success to be allowed, got %v", err)
+	}
+}
+
+func TestLoginThrottleMaxDelay(t *testing.T) {
+	throttle, _ := newTestThrottle(ThrottleConfig{
+		AccountFreeAttempts: 1,
+		BaseDelay:           time.Second,
+		MaxDelay:            5 * time.Second,
+		LockoutThreshold:    100,
+	})
+
+	for i := 0; i < 20; i++ {
+		throttle.Failure("test@example.com", "1.2.3.4", "invalid password")
+	}
+
+	wait, err := throttle.Check("test@example.com", "")
+	if err != ErrTooManyAttempts {
+		t.Fatalf("Expected ErrTooManyAttempts, got %v", err)
+	}
+	if wait != 5*time.Second {
+		t.Errorf("Expected wait to be capped at 5s, got %v", wait)
+	}
+}
+
+func TestLoginThrottlePerIP(t *testing.T) {
+	throttle, _ := newTestThrottle(ThrottleConfig{
+		AccountFreeAttempts: 100,
+		IPFreeAttempts:      3,
+		BaseDelay:           time.Second,
+		LockoutThreshold:    100,
+	})
+
+	// Spray failures over different accounts from the same IP
+	throttle.UnknownAccountFailure("a@example.com", "1.2.3.4")
+	throttle.UnknownAccountFailure("b@example.com", "1.2.3.4")
+	throttle.UnknownAccountFailure("c@example.com", "1.2.3.4")
+
+	if _, err := throttle.Check("d@example.com", "1.2.3.4"); err != ErrTooManyAttempts {
+		t.Errorf("Expected ErrTooManyAttempts for IP, got %v", err)
+	}
+	if _, err := throttle.Check("d@example.com", "5.6.7.8"); err != nil {
+		t.Errorf("Expected other IP to be allowed, got %v", err)
+	}
+
+	// Unknown accounts get no counters of their own
+	if len(throttle.accounts) != 0 {
+		t.Errorf("Expected no account counters, got %d", len(throttle.accounts))
+	}
+}
+
+func TestLoginThrottleSweep(t *testing.T) {
+	throttle, now := newTestThrottle(ThrottleConfig{
+		LockoutThreshold: 2,
+		LockoutDuration:  3 * time.Hour,
+		ResetAfter:       time.Hour,
+	})
+
+	throttle.Failure("quiet@example.com", "1.1.1.1", "invalid password")
+	throttle.Failure("locked@example.com", "2.2.2.2", "invalid password")
+	throttle.Failure("locked@example.com", "2.2.2.2", "invalid password")
+
+	// Counters that are never looked up again are swept by later failures
+	*now = now.Add(2 * time.Hour)
+	throttle.UnknownAccountFailure("other@example.com", "3.3.3.3")
+	if _, exists := throttle.accounts["quiet@example.com"]; exists {
+		t.Error("Expected the quiet account counter to be swept")
+	}
+	if _, exists := throttle.ips["1.1.1.1"]; exists {
+		t.Error("Expected the quiet IP counter to be swept")
+	}
+
+	// Locked accounts are kept until the lockout ends
+	if _, exists := throttle.accounts["locked@example.com"]; !exists {
+		t.Error("Expected the locked account counter to be kept")
+	}
+	if len(throttle.ips) != 1 {
+		t.Errorf("Expected only the new IP counter, got %d", len(throttle.ips))
+	}
+}
+
+func TestLoginThrottleLockout(t *testing.T) {
+	throttle, now := newTestThrottle(ThrottleConfig{
+		AccountFreeAttempts: 100,
+		IPFreeAttempts:      100,
+		LockoutThreshold:    3,
+		LockoutDuration:     10 * time.Minute,
+		ResetAfter:          time.Minute,
+	})
+
+	for i := 0; i < 3; i++ {
+		throttle.Failure("test@example.com", "1.2.3.4", "invalid password")
+	}
+
+	wait, err := throttle.Check("test@example.com", "1.2.3.4")
+	if err != ErrAccountLocked {
+		t.Fatalf("Expected ErrAccountLocked, got %v", err)
+	}
+	if wait != 10*time.Minute {
+		t.Errorf("Expected wait of 10m, got %v", wait)
+	}
+
+	// The lockout outlives the reset period
+	*now = now.Add(5 * time.Minute)
+	if _, err := throttle.Check("test@example.com", "1.2.3.4"); err != ErrAccountLocked {
+		t.Errorf("Expected account to still be locked, got %v", err)
+	}
+
+	lockouts := throttle.Lockouts()
+	if len(lockouts) != 1 || lockouts[0].Email != "test@example.com" || lockouts[0].Failures != 3 {
+		t.Errorf("Unexpected lockouts: %+v", lockouts)
+	}
+
+	// Expired lockouts are lifted
+	*now = now.Add(5 * time.Minute)
+	if _, err := throttle.Check("test@example.com", "1.2.3.4"); err != nil {
+		t.Errorf("Expected lockout to have expired, got %v", err)
+	}
+}
+
+func TestLoginThrottleUnlock(t *testing.T) {
+	throttle, _ := newTestThrottle(ThrottleConfig{
+		LockoutThreshold: 1,
+	})
+
+	throttle.Failure("test@example.com", "1.2.3.4", "invalid password")
+	if _, err := throttle.Check("test@example.com", "1.2.3.4"); err != ErrAccountLocked {
+		t.Fatalf("Expected ErrAccountLocked, got %v", err)
+	}
+
+	if !throttle.Unlock("Test@Example.com") {
+		t.Error("Expected Unlock to report a locked account")
+	}
+	if _, err := throttle.Check("test@example.com", "5.6.7.8"); err != nil {
+		t.Errorf("Expected unlocked account to be allowed, got %v", err)
+	}
+	if throttle.Unlock("test@example.com") {
+		t.Error("Expected second Unlock to report nothing to unlock")
+	}
+}
+
+func TestLoginThrottleAuditTrail(t *testing.T) {
+	throttle, _ := newTestThrottle(ThrottleConfig{
+		AuditSize: 2,
+	})
+
+	throttle.UnknownAccountFailure("a@example.com", "1.1.1.1")
+	throttle.Failure("b@example.com", "2.2.2.2", "invalid password")
+	throttle.Failure("c@example.com", "3.3.3.3", "invalid password")
+
+	attempts := throttle.FailedAttempts()
+	if len(attempts) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", len(attempts))
+	}
+
+	// Newest entries come first and the oldest one was dropped
+	if attempts[0].Email != "c@example.com" || attempts[1].Email != "b@example.com" {
+		t.Errorf("Unexpected audit trail order: %+v", attempts)
+	}
+	if attempts[0].IP != "3.3.3.3" || attempts[0].Reason != "invalid password" {
+		t.Errorf("Unexpected audit entry: %+v", attempts[0])
+	}
+}
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the base32 encoding of the RFC 6238 SHA1 test key "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// Test vectors from RFC 6238, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := TOTPCode(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Failed to compute code: %v", err)
+		}
+		if code != tt.code {
+			t.Errorf("Expected code %s at %d, got %s", tt.code, tt.unix, code)
+		}
+	}
+
+	// Lower case secrets are accepted
+	code, err := TOTPCode(strings.ToLower(rfcSecret), time.Unix(59, 0))
+	if err != nil || code != "287082" {
+		t.Errorf("Expected lower case secret to work, got %s, %v", code, err)
+	}
+
+	// Invalid secrets are rejected
+	if _, err := TOTPCode("not base32!", time.Unix(59, 0)); err != ErrInvalidSecret {
+		t.Errorf("Expected ErrInvalidSecret, got %v", err)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	// The current code is accepted and reports its step
+	step, ok := ValidateTOTP(rfcSecret, "081804", now)
+	if !ok {
+		t.Fatal("Expected current code to be valid")
+	}
+	if step != TOTPStep(now) {
+		t.Errorf("Expected step %d, got %d", TOTPStep(now), step)
+	}
+
+	// Codes of the neighbouring steps are accepted to tolerate clock drift
+	previous, _ := TOTPCode(rfcSecret, now.Add(-TOTPPeriod*time.Second))
+	if step, ok := ValidateTOTP(rfcSecret, previous, now); !ok || step != TOTPStep(now)-1 {
+		t.Errorf("Expected previous code to be valid for step %d, got %d, %v", TOTPStep(now)-1, step, ok)
+	}
+
+	// Codes further away are rejected
+	old, _ := TOTPCode(rfcSecret, now.Add(-2*TOTPPeriod*time.Second))
+	if _, ok := ValidateTOTP(rfcSecret, old, now); ok {
+		t.Error("Expected code from two steps ago to be invalid")
+	}
+
+	// Wrong codes are rejected
+	if _, ok := ValidateTOTP(rfcSecret, "000000", now); ok {
+		t.Error("Expected wrong code to be invalid")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("Conduit", "test@example.com", rfcSecret)
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("Failed to parse URI: %v", err)
+	}
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" {
+		t.Errorf("Expected otpauth://totp URI, got %s", uri)
+	}
+	if parsed.Path != "/Conduit:test@example.com" {
+		t.Errorf("Expected label Conduit:test@example.com, got %s", parsed.Path)
+	}
+	if parsed.Query().Get("secret") != rfcSecret {
+		t.Errorf("Expected secret %s, got %s", rfcSecret, parsed.Query().Get("secret"))
+	}
+	if parsed.Query().Get("issuer") != "Conduit" {
+		t.Errorf("Expected issuer Conduit, got %s", parsed.Query().Get("issuer"))
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("Failed to generate secret: %v", err)
+	}
+
+	// The secret must be usable to compute codes
+	if _, err := TOTPCode(secret, time.Now()); err != nil {
+		t.Errorf("Expected generated secret to be valid, got %v", err)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("Failed to generate recovery codes: %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("Expected 10 codes, got %d", len(codes))
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("Unexpected recovery code format: %s", code)
+		}
+		if seen[code] {
+			t.Errorf("Duplicate recovery code: %s", code)
+		}
+		seen[code] = true
+	}
+
+	// Hashing ignores case and formatting
+	if HashRecoveryCode("abcde-fghij") != HashRecoveryCode("ABCDE FGHIJ") {
+		t.Error("Expected hashes to ignore formatting")
+	}
+	if HashRecoveryCode("abcde-fghij") == HashRecoveryCode("abcde-fghik") {
+		t.Error("Expected different codes to have different hashes")
+	}
+}
+
+func TestChallengeToken(t *testing.T) {
+	config := Config{
+		Secret:          "test-secret",
+		TokenExpiry:     time.Hour,
+		ChallengeExpiry: time.Minute,
+	}
+
+	token, expiresAt, err := GenerateChallengeToken("test@example.com", config)
+	if err != nil {
+		t.Fatalf("Failed to generate challenge token: %v", err)
+	}
+	if time.Until(expiresAt) > time.Minute {
+		t.Errorf("Expected challenge to expire within a minute, got %v", expiresAt)
+	}
+
+	email, err := ValidateChallengeToken(token, config)
+	if err != nil {
+		t.Fatalf("Failed to validate challenge token: %v", err)
+	}
+	if email != "test@example.com" {
+		t.Errorf("Expected email test@example.com, got %s", email)
+	}
+
+	// A challenge token does not grant API access
+	if _, err := ValidateToken(token, config); err != ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken when using challenge as session token, got %v", err)
+	}
+
+	// A session token is not a challenge token
+	sessionToken, _ := GenerateToken("test@example.com", config)
+	if _, err := ValidateChallengeToken(sessionToken, config); err != ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken when using session token as challenge, got %v", err)
+	}
+}
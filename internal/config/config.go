@@ -0,0 +1,372 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/mail"
+	"github.com/denga/go-real-world-example/internal/middleware"
+	"github.com/denga/go-real-world-example/internal/oidc"
+	"github.com/denga/go-real-world-example/internal/ratelimit"
+)
+
+// Config holds the configuration of the server
+type Config struct {
+	// Port is the port the server listens on
+	Port string
+	// TLS configures serving HTTPS, which is off without a certificate
+	TLS TLSConfig
+	// AppURL is the public base URL of the application, used for links in emails
+	AppURL string
+	// Auth is the authentication configuration
+	Auth auth.Config
+	// Mail is the configuration for sending email
+	Mail mail.Config
+	// CORS configures which other origins may call the API from a browser
+	CORS middleware.CORSConfig
+	// Security configures the security headers, like the Content-Security-Policy
+	Security middleware.SecurityHeadersConfig
+	// OIDC lists the external identity providers users can sign in with
+	OIDC []oidc.Config
+	// RateLimits are the rate limiting rules, none disables rate limiting
+	RateLimits []ratelimit.Rule
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For headers are
+	// believed when determining client IPs
+	TrustedProxies []netip.Prefix
+}
+
+// TLSConfig holds the configuration for serving HTTPS directly
+type TLSConfig struct {
+	// CertFile and KeyFile are the PEM files of the certificate, with
+	// intermediates, and its private key. They are reloaded when they change.
+	CertFile string
+	KeyFile  string
+	// MinVersion is the oldest TLS version accepted, like tls.VersionTLS12
+	MinVersion uint16
+	// RedirectPort is the port of a plain HTTP listener that redirects to
+	// HTTPS, empty disables it
+	RedirectPort string
+}
+
+// Enabled reports whether the server should serve HTTPS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// Default returns the default configuration
+func Default() Config {
+	return Config{
+		Port:   "8080",
+		TLS:    TLSConfig{MinVersion: tls.VersionTLS12},
+		AppURL: "http://localhost:8080",
+		Auth:   auth.DefaultConfig(),
+		Mail:   mail.DefaultConfig(),
+		CORS:   middleware.DefaultCORSConfig(),
+
+		Security:   middleware.DefaultSecurityHeadersConfig(),
+		RateLimits: ratelimit.DefaultRules(),
+	}
+}
+
+// Load builds the configuration from environment variables, keeping the
+// defaults for unset variables. getenv is usually os.Getenv.
+func Load(getenv func(string) string) (Config, error) {
+	config := Default()
+
+	if port := getenv("PORT"); port != "" {
+		config.Port = port
+	}
+
+	if err := loadTLS(getenv, &config.TLS); err != nil {
+		return Config{}, err
+	}
+
+	if secret := getenv("JWT_SECRET"); secret != "" {
+		config.Auth.Secret = secret
+	}
+
+	if admins := getenv("ADMIN_EMAILS"); admins != "" {
+		config.Auth.AdminEmails = splitList(admins)
+	}
+
+	if mode := getenv("AUTH_MODE"); mode != "" {
+		switch auth.Mode(mode) {
+		case auth.ModeToken, auth.ModeCookie:
+			config.Auth.Mode = auth.Mode(mode)
+		default:
+			return Config{}, fmt.Errorf("invalid AUTH_MODE %q: must be %q or %q", mode, auth.ModeToken, auth.ModeCookie)
+		}
+	}
+
+	if domain := getenv("COOKIE_DOMAIN"); domain != "" {
+		config.Auth.Cookie.Domain = domain
+	}
+
+	if secure := getenv("COOKIE_SECURE"); secure != "" {
+		value, err := strconv.ParseBool(secure)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid COOKIE_SECURE %q: %w", secure, err)
+		}
+		config.Auth.Cookie.Secure = value
+	}
+
+	if sameSite := getenv("COOKIE_SAMESITE"); sameSite != "" {
+		switch strings.ToLower(sameSite) {
+		case "lax":
+			config.Auth.Cookie.SameSite = http.SameSiteLaxMode
+		case "strict":
+			config.Auth.Cookie.SameSite = http.SameSiteStrictMode
+		case "none":
+			config.Auth.Cookie.SameSite = http.SameSiteNoneMode
+		default:
+			return Config{}, fmt.Errorf("invalid COOKIE_SAMESITE %q: must be lax, strict or none", sameSite)
+		}
+	}
+
+	if appURL := getenv("APP_URL"); appURL != "" {
+		config.AppURL = appURL
+	}
+
+	if from := getenv("MAIL_FROM"); from != "" {
+		config.Mail.From = from
+	}
+
+	config.Mail.SMTPAddr = getenv("SMTP_ADDR")
+	config.Mail.SMTPUsername = getenv("SMTP_USERNAME")
+	config.Mail.SMTPPassword = getenv("SMTP_PASSWORD")
+	config.Mail.OutboxDir = getenv("MAIL_OUTBOX_DIR")
+
+	for _, name := range splitList(getenv("OIDC_PROVIDERS")) {
+		provider, err := loadOIDCProvider(getenv, name, config.AppURL)
+		if err != nil {
+			return Config{}, err
+		}
+		config.OIDC = append(config.OIDC, provider)
+	}
+
+	if err := loadCORS(getenv, &config.CORS); err != nil {
+		return Config{}, err
+	}
+
+	// The server's own HTTPS listener makes HSTS safe to send, unless
+	// HSTS_MAX_AGE says otherwise
+	if config.TLS.Enabled() {
+		config.Security.HSTSMaxAge = middleware.DefaultHSTSMaxAge
+	}
+	if err := loadSecurityHeaders(getenv, &config.Security); err != nil {
+		return Config{}, err
+	}
+
+	if limits := getenv("RATE_LIMITS"); limits != "" {
+		if strings.EqualFold(limits, "off") {
+			config.RateLimits = nil
+		} else {
+			rules, err := ratelimit.ParseRules(limits)
+			if err != nil {
+				return Config{}, fmt.Errorf("invalid RATE_LIMITS: %w", err)
+			}
+			config.RateLimits = rules
+		}
+	}
+
+	for _, proxy := range splitList(getenv("TRUSTED_PROXIES")) {
+		prefix, err := parsePrefix(proxy)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: %w", proxy, err)
+		}
+		config.TrustedProxies = append(config.TrustedProxies, prefix)
+	}
+
+	return config, nil
+}
+
+// loadTLS reads the TLS_* variables and HTTP_REDIRECT_PORT
+func loadTLS(getenv func(string) string, config *TLSConfig) error {
+	config.CertFile = getenv("TLS_CERT_FILE")
+	config.KeyFile = getenv("TLS_KEY_FILE")
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	if version := getenv("TLS_MIN_VERSION"); version != "" {
+		switch version {
+		case "1.2":
+			config.MinVersion = tls.VersionTLS12
+		case "1.3":
+			config.MinVersion = tls.VersionTLS13
+		default:
+			return fmt.Errorf("invalid TLS_MIN_VERSION %q: must be 1.2 or 1.3", version)
+		}
+	}
+
+	if port := getenv("HTTP_REDIRECT_PORT"); port != "" {
+		if !config.Enabled() {
+			return fmt.Errorf("HTTP_REDIRECT_PORT requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("invalid HTTP_REDIRECT_PORT %q: %w", port, err)
+		}
+		config.RedirectPort = port
+	}
+
+	return nil
+}
+
+// loadCORS reads the CORS_* variables
+func loadCORS(getenv func(string) string, cors *middleware.CORSConfig) error {
+	if origins := getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		cors.AllowedOrigins = splitList(origins)
+	}
+	if methods := getenv("CORS_ALLOWED_METHODS"); methods != "" {
+		cors.AllowedMethods = splitList(strings.ToUpper(methods))
+	}
+	if headers := getenv("CORS_ALLOWED_HEADERS"); headers != "" {
+		cors.AllowedHeaders = splitList(headers)
+	}
+
+	if credentials := getenv("CORS_ALLOW_CREDENTIALS"); credentials != "" {
+		value, err := strconv.ParseBool(credentials)
+		if err != nil {
+			return fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS %q: %w", credentials, err)
+		}
+		cors.AllowCredentials = value
+	}
+
+	if maxAge := getenv("CORS_MAX_AGE"); maxAge != "" {
+		value, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return fmt.Errorf("invalid CORS_MAX_AGE %q: %w", maxAge, err)
+		}
+		cors.MaxAge = value
+	}
+
+	if err := cors.Validate(); err != nil {
+		return fmt.Errorf("invalid CORS configuration: %w", err)
+	}
+	return nil
+}
+
+// loadSecurityHeaders reads the variables of the security headers. "off"
+// disables the Content-Security-Policy, the frame options and the referrer
+// policy.
+func loadSecurityHeaders(getenv func(string) string, security *middleware.SecurityHeadersConfig) error {
+	if policy := getenv("CONTENT_SECURITY_POLICY"); policy != "" {
+		security.ContentSecurityPolicy = offToEmpty(policy)
+	}
+
+	if reportOnly := getenv("CSP_REPORT_ONLY"); reportOnly != "" {
+		value, err := strconv.ParseBool(reportOnly)
+		if err != nil {
+			return fmt.Errorf("invalid CSP_REPORT_ONLY %q: %w", reportOnly, err)
+		}
+		security.ReportOnly = value
+	}
+
+	if frameOptions := getenv("FRAME_OPTIONS"); frameOptions != "" {
+		security.FrameOptions = strings.ToUpper(offToEmpty(frameOptions))
+	}
+	if referrerPolicy := getenv("REFERRER_POLICY"); referrerPolicy != "" {
+		security.ReferrerPolicy = offToEmpty(referrerPolicy)
+	}
+
+	if maxAge := getenv("HSTS_MAX_AGE"); maxAge != "" {
+		value, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return fmt.Errorf("invalid HSTS_MAX_AGE %q: %w", maxAge, err)
+		}
+		security.HSTSMaxAge = value
+	}
+
+	if subdomains := getenv("HSTS_INCLUDE_SUBDOMAINS"); subdomains != "" {
+		value, err := strconv.ParseBool(subdomains)
+		if err != nil {
+			return fmt.Errorf("invalid HSTS_INCLUDE_SUBDOMAINS %q: %w", subdomains, err)
+		}
+		security.HSTSIncludeSubdomains = value
+	}
+
+	if err := security.Validate(); err != nil {
+		return fmt.Errorf("invalid security headers configuration: %w", err)
+	}
+	return nil
+}
+
+// offToEmpty turns "off" into an empty string
+func offToEmpty(s string) string {
+	if strings.EqualFold(s, "off") {
+		return ""
+	}
+	return s
+}
+
+// parsePrefix parses a CIDR range or a single IP address
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// loadOIDCProvider reads the OIDC_<NAME>_* variables of a provider. The
+// callback URL is derived from the app URL and the name.
+func loadOIDCProvider(getenv func(string) string, name, appURL string) (oidc.Config, error) {
+	if !validProviderName(name) {
+		return oidc.Config{}, fmt.Errorf("invalid OIDC provider name %q: use lowercase letters, digits and dashes", name)
+	}
+
+	prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+	provider := oidc.Config{
+		Name:         name,
+		DisplayName:  getenv(prefix + "DISPLAY_NAME"),
+		Issuer:       getenv(prefix + "ISSUER"),
+		ClientID:     getenv(prefix + "CLIENT_ID"),
+		ClientSecret: getenv(prefix + "CLIENT_SECRET"),
+		RedirectURL:  strings.TrimRight(appURL, "/") + "/api/auth/" + name + "/callback",
+		Scopes:       []string{"email", "profile"},
+	}
+
+	if provider.Issuer == "" || provider.ClientID == "" {
+		return oidc.Config{}, fmt.Errorf("OIDC provider %q needs %sISSUER and %sCLIENT_ID", name, prefix, prefix)
+	}
+	if provider.DisplayName == "" {
+		provider.DisplayName = name
+	}
+	if scopes := getenv(prefix + "SCOPES"); scopes != "" {
+		provider.Scopes = strings.Fields(strings.ReplaceAll(scopes, ",", " "))
+	}
+
+	return provider, nil
+}
+
+// validProviderName reports whether a provider name is safe to use in URLs
+// and environment variable names
+func validProviderName(name string) bool {
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/middleware"
+)
+
+// envMap returns a getenv function backed by a map
+func envMap(env map[string]string) func(string) string {
+	return func(key string) string {
+		return env[key]
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	config, err := Load(envMap(nil))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if config.Port != "8080" {
+		t.Errorf("Expected default port 8080, got %s", config.Port)
+	}
+	if config.Auth.Mode != auth.ModeToken {
+		t.Errorf("Expected default auth mode %q, got %q", auth.ModeToken, config.Auth.Mode)
+	}
+	if !config.Auth.Cookie.Secure {
+		t.Error("Expected cookies to be secure by default")
+	}
+	if config.Mail.SMTPAddr != "" || config.Mail.OutboxDir != "" {
+		t.Errorf("Expected no mail delivery by default, got %+v", config.Mail)
+	}
+	if len(config.CORS.AllowedOrigins) != 0 {
+		t.Errorf("Expected same-origin only by default, got %v", config.CORS.AllowedOrigins)
+	}
+	if len(config.RateLimits) == 0 {
+		t.Error("Expected default rate limits")
+	}
+	if len(config.TrustedProxies) != 0 {
+		t.Errorf("Expected no trusted proxies by default, got %v", config.TrustedProxies)
+	}
+	if config.TLS.Enabled() || config.TLS.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Expected plain HTTP with TLS 1.2 as minimum, got %+v", config.TLS)
+	}
+	if config.Security.ContentSecurityPolicy == "" || config.Security.HSTSMaxAge != 0 {
+		t.Errorf("Expected a CSP without HSTS by default, got %+v", config.Security)
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	config, err := Load(envMap(map[string]string{
+		"PORT":            "9090",
+		"JWT_SECRET":      "s3cret",
+		"ADMIN_EMAILS":    "a@example.com, b@example.com,",
+		"AUTH_MODE":       "cookie",
+		"COOKIE_DOMAIN":   "example.com",
+		"COOKIE_SECURE":   "false",
+		"COOKIE_SAMESITE": "Strict",
+		"APP_URL":         "https://conduit.example.com",
+		"MAIL_FROM":       "Conduit <mail@example.com>",
+		"SMTP_ADDR":       "smtp.example.com:587",
+		"SMTP_USERNAME":   "mailer",
+		"SMTP_PASSWORD":   "hunter2",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if config.Port != "9090" {
+		t.Errorf("Expected port 9090, got %s", config.Port)
+	}
+	if config.Auth.Secret != "s3cret" {
+		t.Errorf("Expected secret from env, got %s", config.Auth.Secret)
+	}
+	if len(config.Auth.AdminEmails) != 2 || config.Auth.AdminEmails[1] != "b@example.com" {
+		t.Errorf("Unexpected admin emails: %v", config.Auth.AdminEmails)
+	}
+	if config.Auth.Mode != auth.ModeCookie {
+		t.Errorf("Expected auth mode %q, got %q", auth.ModeCookie, config.Auth.Mode)
+	}
+	if config.Auth.Cookie.Domain != "example.com" {
+		t.Errorf("Expected cookie domain example.com, got %s", config.Auth.Cookie.Domain)
+	}
+	if config.Auth.Cookie.Secure {
+		t.Error("Expected insecure cookies")
+	}
+	if config.Auth.Cookie.SameSite != http.SameSiteStrictMode {
+		t.Errorf("Expected SameSite strict, got %v", config.Auth.Cookie.SameSite)
+	}
+	if config.AppURL != "https://conduit.example.com" {
+		t.Errorf("Expected app URL from env, got %s", config.AppURL)
+	}
+	if config.Mail.From != "Conduit <mail@example.com>" {
+		t.Errorf("Expected mail sender from env, got %s", config.Mail.From)
+	}
+	if config.Mail.SMTPAddr != "smtp.example.com:587" || config.Mail.SMTPUsername != "mailer" || config.Mail.SMTPPassword != "hunter2" {
+		t.Errorf("Unexpected SMTP settings: %+v", config.Mail)
+	}
+}
+
+func TestLoadInvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{name: "Invalid auth mode", env: map[string]string{"AUTH_MODE": "basic"}},
+		{name: "Invalid cookie secure flag", env: map[string]string{"COOKIE_SECURE": "maybe"}},
+		{name: "Invalid SameSite", env: map[string]string{"COOKIE_SAMESITE": "sometimes"}},
+		{name: "Invalid OIDC provider name", env: map[string]string{"OIDC_PROVIDERS": "Company SSO"}},
+		{name: "OIDC provider without issuer", env: map[string]string{"OIDC_PROVIDERS": "company", "OIDC_COMPANY_CLIENT_ID": "conduit"}},
+		{name: "Any origin with credentials", env: map[string]string{"CORS_ALLOWED_ORIGINS": "*"}},
+		{name: "Invalid CORS max age", env: map[string]string{"CORS_MAX_AGE": "1h"}},
+		{name: "TLS certificate without key", env: map[string]string{"TLS_CERT_FILE": "cert.pem"}},
+		{name: "Invalid TLS version", env: map[string]string{"TLS_CERT_FILE": "cert.pem", "TLS_KEY_FILE": "key.pem", "TLS_MIN_VERSION": "1.0"}},
+		{name: "Redirect without TLS", env: map[string]string{"HTTP_REDIRECT_PORT": "80"}},
+		{name: "Invalid redirect port", env: map[string]string{"TLS_CERT_FILE": "cert.pem", "TLS_KEY_FILE": "key.pem", "HTTP_REDIRECT_PORT": "http"}},
+		{name: "Invalid frame options", env: map[string]string{"FRAME_OPTIONS": "ALLOWALL"}},
+		{name: "Invalid HSTS max age", env: map[string]string{"HSTS_MAX_AGE": "1y"}},
+		{name: "Invalid rate limit", env: map[string]string{"RATE_LIMITS": "POST /api/articles lots user"}},
+		{name: "Invalid trusted proxy", env: map[string]string{"TRUSTED_PROXIES": "proxy.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(envMap(tt.env)); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadOIDCProviders(t *testing.T) {
+	config, err := Load(envMap(map[string]string{
+		"APP_URL":                    "https://conduit.example.com/",
+		"OIDC_PROVIDERS":             "company, my-idp",
+		"OIDC_COMPANY_ISSUER":        "https://sso.example.com",
+		"OIDC_COMPANY_CLIENT_ID":     "conduit",
+		"OIDC_COMPANY_CLIENT_SECRET": "secret",
+		"OIDC_COMPANY_DISPLAY_NAME":  "Company SSO",
+		"OIDC_MY_IDP_ISSUER":         "https://idp.example.org",
+		"OIDC_MY_IDP_CLIENT_ID":      "public-client",
+		"OIDC_MY_IDP_SCOPES":         "email,groups",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if len(config.OIDC) != 2 {
+		t.Fatalf("Expected 2 providers, got %d", len(config.OIDC))
+	}
+
+	company := config.OIDC[0]
+	if company.Name != "company" || company.DisplayName != "Company SSO" || company.ClientSecret != "secret" {
+		t.Errorf("Unexpected provider: %+v", company)
+	}
+	if company.RedirectURL != "https://conduit.example.com/api/auth/company/callback" {
+		t.Errorf("Unexpected redirect URL: %s", company.RedirectURL)
+	}
+	if strings.Join(company.Scopes, " ") != "email profile" {
+		t.Errorf("Expected default scopes, got %v", company.Scopes)
+	}
+
+	idp := config.OIDC[1]
+	if idp.DisplayName != "my-idp" {
+		t.Errorf("Expected name as display name, got %s", idp.DisplayName)
+	}
+	if idp.ClientSecret != "" {
+		t.Errorf("Expected public client, got secret %s", idp.ClientSecret)
+	}
+	if strings.Join(idp.Scopes, " ") != "email groups" {
+		t.Errorf("Expected scopes from env, got %v", idp.Scopes)
+	}
+}
+
+func TestLoadCORS(t *testing.T) {
+	config, err := Load(envMap(map[string]string{
+		"CORS_ALLOWED_ORIGINS":   "http://localhost:3000, https://app.example.com",
+		"CORS_ALLOWED_METHODS":   "get,post",
+		"CORS_ALLOWED_HEADERS":   "Authorization,Content-Type",
+		"CORS_ALLOW_CREDENTIALS": "false",
+		"CORS_MAX_AGE":           "600",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if strings.Join(config.CORS.AllowedOrigins, " ") != "http://localhost:3000 https://app.example.com" {
+		t.Errorf("Unexpected origins: %v", config.CORS.AllowedOrigins)
+	}
+	if strings.Join(config.CORS.AllowedMethods, " ") != "GET POST" {
+		t.Errorf("Unexpected methods: %v", config.CORS.AllowedMethods)
+	}
+	if strings.Join(config.CORS.AllowedHeaders, " ") != "Authorization Content-Type" {
+		t.Errorf("Unexpected headers: %v", config.CORS.AllowedHeaders)
+	}
+	if config.CORS.AllowCredentials {
+		t.Error("Expected credentials to be disallowed")
+	}
+	if config.CORS.MaxAge != 600 {
+		t.Errorf("Expected max age 600, got %d", config.CORS.MaxAge)
+	}
+
+	// Any origin is fine without credentials
+	if _, err := Load(envMap(map[string]string{"CORS_ALLOWED_ORIGINS": "*", "CORS_ALLOW_CREDENTIALS": "false"})); err != nil {
+		t.Errorf("Expected any origin without credentials to be valid, got %v", err)
+	}
+}
+
+func TestLoadTLS(t *testing.T) {
+	config, err := Load(envMap(map[string]string{
+		"PORT":               "443",
+		"TLS_CERT_FILE":      "/etc/conduit/cert.pem",
+		"TLS_KEY_FILE":       "/etc/conduit/key.pem",
+		"TLS_MIN_VERSION":    "1.3",
+		"HTTP_REDIRECT_PORT": "80",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if !config.TLS.Enabled() {
+		t.Error("Expected TLS to be enabled")
+	}
+	if config.TLS.CertFile != "/etc/conduit/cert.pem" || config.TLS.KeyFile != "/etc/conduit/key.pem" {
+		t.Errorf("Unexpected certificate files: %s, %s", config.TLS.CertFile, config.TLS.KeyFile)
+	}
+	if config.TLS.MinVersion != tls.VersionTLS13 {
+		t.Errorf("Expected TLS 1.3, got %x", config.TLS.MinVersion)
+	}
+	if config.TLS.RedirectPort != "80" {
+		t.Errorf("Expected redirect port 80, got %s", config.TLS.RedirectPort)
+	}
+	if config.Security.HSTSMaxAge != middleware.DefaultHSTSMaxAge {
+		t.Errorf("Expected HSTS by default with TLS, got max age %d", config.Security.HSTSMaxAge)
+	}
+
+	// HSTS can still be switched off
+	config, err = Load(envMap(map[string]string{
+		"TLS_CERT_FILE": "/etc/conduit/cert.pem",
+		"TLS_KEY_FILE":  "/etc/conduit/key.pem",
+		"HSTS_MAX_AGE":  "0",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Security.HSTSMaxAge != 0 {
+		t.Errorf("Expected HSTS to be off, got max age %d", config.Security.HSTSMaxAge)
+	}
+}
+
+func TestLoadSecurityHeaders(t *testing.T) {
+	config, err := Load(envMap(map[string]string{
+		"CONTENT_SECURITY_POLICY": "default-src 'self'; script-src 'self' {script-hashes}",
+		"CSP_REPORT_ONLY":         "true",
+		"FRAME_OPTIONS":           "sameorigin",
+		"REFERRER_POLICY":         "off",
+		"HSTS_MAX_AGE":            "63072000",
+		"HSTS_INCLUDE_SUBDOMAINS": "true",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	security := config.Security
+	if security.ContentSecurityPolicy != "default-src 'self'; script-src 'self' {script-hashes}" {
+		t.Errorf("Unexpected policy: %q", security.ContentSecurityPolicy)
+	}
+	if !security.ReportOnly {
+		t.Error("Expected report-only mode")
+	}
+	if security.FrameOptions != "SAMEORIGIN" {
+		t.Errorf("Expected frame options SAMEORIGIN, got %q", security.FrameOptions)
+	}
+	if security.ReferrerPolicy != "" {
+		t.Errorf("Expected referrer policy to be off, got %q", security.ReferrerPolicy)
+	}
+	if security.HSTSMaxAge != 63072000 || !security.HSTSIncludeSubdomains {
+		t.Errorf("Unexpected HSTS settings: %d, %v", security.HSTSMaxAge, security.HSTSIncludeSubdomains)
+	}
+
+	// The policy can be switched off
+	config, err = Load(envMap(map[string]string{"CONTENT_SECURITY_POLICY": "off"}))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Security.ContentSecurityPolicy != "" {
+		t.Errorf("Expected no policy, got %q", config.Security.ContentSecurityPolicy)
+	}
+}
+
+func TestLoadRateLimits(t *testing.T) {
+	config, err := Load(envMap(map[string]string{
+		"RATE_LIMITS":     "POST /api/articles 5/1h user",
+		"TRUSTED_PROXIES": "10.0.0.0/8, 192.168.1.1, 10.1.2.3/16",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if len(config.RateLimits) != 1 || config.RateLimits[0].String() != "POST /api/articles 5/1h0m0s user" {
+		t.Errorf("Unexpected rate limits: %v", config.RateLimits)
+	}
+
+	var proxies []string
+	for _, prefix := range config.TrustedProxies {
+		proxies = append(proxies, prefix.String())
+	}
+	if strings.Join(proxies, " ") != "10.0.0.0/8 192.168.1.1/32 10.1.0.0/16" {
+		t.Errorf("Unexpected trusted proxies: %v", proxies)
+	}
+
+	// Rate limiting can be switched off
+	config, err = Load(envMap(map[string]string{"RATE_LIMITS": "off"}))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(config.RateLimits) != 0 {
+		t.Errorf("Expected no rate limits, got %v", config.RateLimits)
+	}
+}
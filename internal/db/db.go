@@ -2,9 +2,14 @@ package db
 
 import (
 	"errors"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/policy"
+	"github.com/denga/go-real-world-example/internal/util"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -17,31 +22,67 @@ var (
 // InternalUser extends api.User with a password field for internal use
 type InternalUser struct {
 	api.User
-	Password string // Hashed password
+	Password      string      // Hashed password
+	EmailVerified bool        // Whether the user confirmed their email address
+	Role          policy.Role // Role deciding what else the user may do
+
+	// Moderation
+	SuspendedAt      *time.Time // When the account was suspended, nil if it isn't
+	SuspensionReason string     // Why the account was suspended
+	SessionsValidAt  time.Time  // Session tokens issued before this are revoked
+
+	// Two-factor authentication
+	TOTPSecret        string   // Secret of the enabled authenticator
+	TOTPPendingSecret string   // Secret awaiting confirmation during enrollment
+	TOTPLastStep      int64    // Last accepted time step, to reject replayed codes
+	RecoveryCodes     []string // Hashes of the unused recovery codes
+}
+
+// Suspended reports whether the account is suspended
+func (u *InternalUser) Suspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// TwoFactorEnabled reports whether the user has confirmed an authenticator
+func (u *InternalUser) TwoFactorEnabled() bool {
+	return u.TOTPSecret != ""
 }
 
 // InMemoryDB is a simple in-memory database implementation
 type InMemoryDB struct {
-	users     map[string]*InternalUser        // key: email
-	usernames map[string]string               // key: username, value: email
-	articles  map[string]*api.Article         // key: slug
-	comments  map[string]map[int]*api.Comment // key: article slug, value: map of comments by ID
-	follows   map[string]map[string]bool      // key: follower username, value: map of followed usernames
-	favorites map[string]map[string]bool      // key: article slug, value: map of usernames who favorited
-	tags      map[string]bool                 // set of unique tags
-	mutex     sync.RWMutex
+	users          map[string]*InternalUser         // key: email
+	usernames      map[string]string                // key: username, value: email
+	articles       map[string]*api.Article          // key: slug
+	comments       map[string]map[int]*api.Comment  // key: article slug, value: map of comments by ID
+	follows        map[string]map[string]bool       // key: follower username, value: map of followed usernames
+	favorites      map[string]map[string]bool       // key: article slug, value: map of usernames who favorited
+	tags           map[string]int                   // key: tag, value: number of articles using it
+	tokens         map[string]*ActionToken          // key: token hash
+	identities     map[identityKey]string           // key: provider and subject, value: email
+	personalTokens map[string]*PersonalToken        // key: token hash
+	slugHistory    map[string]string                // key: previous slug, value: current slug
+	revisions      map[string][]api.ArticleRevision // key: article slug, value: revisions, oldest first
+	tagFollows     map[string]map[string]bool       // key: follower username, value: set of followed tags
+	lastCommentID  int                              // comment IDs are never reused
+	mutex          sync.RWMutex
 }
 
 // NewInMemoryDB creates a new in-memory database
 func NewInMemoryDB() *InMemoryDB {
 	return &InMemoryDB{
-		users:     make(map[string]*InternalUser),
-		usernames: make(map[string]string),
-		articles:  make(map[string]*api.Article),
-		comments:  make(map[string]map[int]*api.Comment),
-		follows:   make(map[string]map[string]bool),
-		favorites: make(map[string]map[string]bool),
-		tags:      make(map[string]bool),
+		users:          make(map[string]*InternalUser),
+		usernames:      make(map[string]string),
+		articles:       make(map[string]*api.Article),
+		comments:       make(map[string]map[int]*api.Comment),
+		follows:        make(map[string]map[string]bool),
+		favorites:      make(map[string]map[string]bool),
+		tags:           make(map[string]int),
+		tokens:         make(map[string]*ActionToken),
+		identities:     make(map[identityKey]string),
+		personalTokens: make(map[string]*PersonalToken),
+		slugHistory:    make(map[string]string),
+		revisions:      make(map[string][]api.ArticleRevision),
+		tagFollows:     make(map[string]map[string]bool),
 	}
 }
 
@@ -70,6 +111,7 @@ func (db *InMemoryDB) CreateUser(user api.User, password string) error {
 	internalUser := &InternalUser{
 		User:     user,
 		Password: string(hashedPassword),
+		Role:     policy.RoleUser,
 	}
 	db.users[user.Email] = internalUser
 	db.usernames[user.Username] = user.Email
@@ -107,10 +149,19 @@ func (db *InMemoryDB) GetInternalUserByEmail(email string) (*InternalUser, error
 	return &user, nil
 }
 
+// dummyPasswordHash is compared against when a user does not exist, so that
+// unknown emails take as long to reject as wrong passwords
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
 // VerifyUserPassword verifies a user's password
 func (db *InMemoryDB) VerifyUserPassword(email, password string) error {
 	internalUser, err := db.GetInternalUserByEmail(email)
 	if err != nil {
+		// Spend the same bcrypt work as for an existing user
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
 		return err
 	}
 
@@ -140,8 +191,21 @@ func (db *InMemoryDB) GetUserByUsername(username string) (*api.User, error) {
 	return &user, nil
 }
 
-// UpdateUser updates an existing user
+// UpdateUser updates an existing user. A new password revokes the session
+// tokens issued before it.
 func (db *InMemoryDB) UpdateUser(email string, updates api.UpdateUser) (*api.User, error) {
+	// Hash a new password before changing anything, so that a failure
+	// doesn't leave the user half updated. Hash outside the lock, bcrypt is
+	// slow on purpose.
+	var hashedPassword string
+	if updates.Password != nil {
+		hash, err := bcrypt.GenerateFromPassword([]byte(*updates.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		hashedPassword = string(hash)
+	}
+
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
@@ -157,11 +221,23 @@ func (db *InMemoryDB) UpdateUser(email string, updates api.UpdateUser) (*api.Use
 			if _, exists := db.users[*updates.Email]; exists {
 				return nil, ErrConflict
 			}
-			// Update email
+			// Update email, the new address has to be verified again
 			delete(db.users, email)
 			db.users[*updates.Email] = internalUser
 			db.usernames[internalUser.Username] = *updates.Email
 			internalUser.Email = *updates.Email
+			internalUser.EmailVerified = false
+			db.deleteActionTokens(email, "")
+			for key, linked := range db.identities {
+				if linked == email {
+					db.identities[key] = *updates.Email
+				}
+			}
+			for _, token := range db.personalTokens {
+				if token.Email == email {
+					token.Email = *updates.Email
+				}
+			}
 		}
 	}
 
@@ -171,15 +247,21 @@ func (db *InMemoryDB) UpdateUser(email string, updates api.UpdateUser) (*api.Use
 			if _, exists := db.usernames[*updates.Username]; exists {
 				return nil, ErrConflict
 			}
-			// Update username
+			// Update username, followed tags go with it
 			delete(db.usernames, internalUser.Username)
 			db.usernames[*updates.Username] = internalUser.Email
+			if tags, exists := db.tagFollows[internalUser.Username]; exists {
+				delete(db.tagFollows, internalUser.Username)
+				db.tagFollows[*updates.Username] = tags
+			}
 			internalUser.Username = *updates.Username
 		}
 	}
 
 	if updates.Password != nil {
-		internalUser.Password = *updates.Password
+		internalUser.Password = hashedPassword
+		internalUser.SessionsValidAt = time.Now()
+		db.deleteActionTokens(internalUser.Email, PurposePasswordReset)
 	}
 
 	if updates.Bio != nil {
@@ -201,23 +283,52 @@ func (db *InMemoryDB) CreateArticle(article api.Article) error {
 	defer db.mutex.Unlock()
 
 	// Check if slug already exists
-	if _, exists := db.articles[article.Slug]; exists {
+	if db.slugTaken(article.Slug, "") {
 		return ErrConflict
 	}
 
+	db.storeArticle(article)
+	return nil
+}
+
+// CreateArticleWithUniqueSlug creates a new article under its slug, or the
+// slug with the lowest free numeric suffix, and returns the slug used. The
+// slug is picked and reserved under one lock, so concurrent creates of
+// articles with the same title get different slugs.
+func (db *InMemoryDB) CreateArticleWithUniqueSlug(article api.Article) string {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	article.Slug = util.UniqueSlug(article.Slug, func(slug string) bool {
+		return db.slugTaken(slug, "")
+	})
+
+	db.storeArticle(article)
+	return article.Slug
+}
+
+// storeArticle stores a new article under its slug. The caller must hold
+// the lock and make sure the slug is free.
+func (db *InMemoryDB) storeArticle(article api.Article) {
+	// Articles are published right away unless requested otherwise
+	if article.Status == "" {
+		article.Status = StatusPublished
+	}
+
 	// Store article
 	db.articles[article.Slug] = &article
 
-	// Add tags to the set
-	for _, tag := range article.TagList {
-		db.tags[tag] = true
+	if db.listed(&article) {
+		db.addTags(article.TagList)
 	}
 
 	// Initialize comments and favorites for this article
 	db.comments[article.Slug] = make(map[int]*api.Comment)
 	db.favorites[article.Slug] = make(map[string]bool)
 
-	return nil
+	// The article as created is the first revision
+	db.revisions[article.Slug] = nil
+	db.addRevision(db.articles[article.Slug], article.Author, article.CreatedAt)
 }
 
 // GetArticle retrieves an article by slug
@@ -230,33 +341,139 @@ func (db *InMemoryDB) GetArticle(slug string) (*api.Article, error) {
 		return nil, ErrNotFound
 	}
 
-	return article, nil
+	// Return a copy, the publisher changes stored articles in the background
+	result := *article
+	return &result, nil
 }
 
-// UpdateArticle updates an existing article
-func (db *InMemoryDB) UpdateArticle(slug string, updates api.UpdateArticle) (*api.Article, error) {
+// UpdateArticle updates an existing article and stores the result as a new
+// revision by the editor, if its content changed. A new title gives the
+// article a new slug, and the previous slug is remembered so that
+// ResolveSlug can redirect old links. A status is set together with the
+// publish time of the updates, and an article that becomes public is dated
+// to now. Changed tags are counted for GetTags.
+func (db *InMemoryDB) UpdateArticle(slug string, updates api.UpdateArticle, editor string) (*api.Article, error) {
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
+	return db.updateArticle(slug, updates, editor)
+}
+
+// updateArticle implements UpdateArticle. The caller must hold the lock.
+func (db *InMemoryDB) updateArticle(slug string, updates api.UpdateArticle, editor string) (*api.Article, error) {
 	article, exists := db.articles[slug]
 	if !exists {
 		return nil, ErrNotFound
 	}
 
+	// Tags are counted again below, in case the tags or the status change
+	if db.listed(article) {
+		db.removeTags(article.TagList)
+	}
+
 	// Update fields if provided
-	if updates.Title != nil {
+	changed := false
+	if updates.Title != nil && *updates.Title != article.Title {
 		article.Title = *updates.Title
+		newSlug := util.UniqueSlug(util.GenerateSlug(article.Title), func(candidate string) bool {
+			return db.slugTaken(candidate, slug)
+		})
+		db.renameArticle(slug, newSlug)
+		changed = true
 	}
 
-	if updates.Description != nil {
+	if updates.Description != nil && *updates.Description != article.Description {
 		article.Description = *updates.Description
+		changed = true
 	}
 
-	if updates.Body != nil {
+	if updates.Body != nil && *updates.Body != article.Body {
 		article.Body = *updates.Body
+		changed = true
 	}
 
-	return article, nil
+	// Tags aren't part of revisions either
+	if updates.TagList != nil && !slices.Equal(*updates.TagList, article.TagList) {
+		article.TagList = slices.Clone(*updates.TagList)
+		article.UpdatedAt = time.Now()
+	}
+
+	// The publish time goes with the status. Publication isn't content, so
+	// it doesn't make a revision.
+	if updates.Status != nil {
+		wasPublic := IsPublic(*article)
+		article.Status = *updates.Status
+		article.PublishAt = updates.PublishAt
+		if !wasPublic && IsPublic(*article) {
+			publish(article, time.Now())
+		}
+	}
+
+	if db.listed(article) {
+		db.addTags(article.TagList)
+	}
+
+	if changed {
+		article.UpdatedAt = time.Now()
+		db.addRevision(article, db.profile(editor), article.UpdatedAt)
+	}
+
+	// Return a copy, the stored article changes with later updates
+	updated := *article
+	return &updated, nil
+}
+
+// slugTaken reports whether a slug is used by an article other than the one
+// with the slug own, either as its current or a previous slug. The caller
+// must hold the lock.
+func (db *InMemoryDB) slugTaken(slug, own string) bool {
+	if slug == own {
+		return false
+	}
+	if _, exists := db.articles[slug]; exists {
+		return true
+	}
+	current, exists := db.slugHistory[slug]
+	return exists && current != own
+}
+
+// renameArticle moves an article with its comments, favorites and revisions
+// to a new slug and records the old one. The caller must hold the lock.
+func (db *InMemoryDB) renameArticle(oldSlug, newSlug string) {
+	if oldSlug == newSlug {
+		return
+	}
+
+	article := db.articles[oldSlug]
+	article.Slug = newSlug
+	db.articles[newSlug] = article
+	db.comments[newSlug] = db.comments[oldSlug]
+	db.favorites[newSlug] = db.favorites[oldSlug]
+	db.revisions[newSlug] = db.revisions[oldSlug]
+	delete(db.articles, oldSlug)
+	delete(db.comments, oldSlug)
+	delete(db.favorites, oldSlug)
+	delete(db.revisions, oldSlug)
+
+	// Older slugs redirect straight to the new one, and a slug the article
+	// had before is current again
+	for previous, current := range db.slugHistory {
+		if current == oldSlug {
+			db.slugHistory[previous] = newSlug
+		}
+	}
+	db.slugHistory[oldSlug] = newSlug
+	delete(db.slugHistory, newSlug)
+}
+
+// ResolveSlug returns the current slug of an article that was renamed from
+// the given slug, or false if the slug was never used by a renamed article
+func (db *InMemoryDB) ResolveSlug(slug string) (string, bool) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	current, exists := db.slugHistory[slug]
+	return current, exists
 }
 
 // DeleteArticle deletes an article by slug
@@ -268,14 +485,31 @@ func (db *InMemoryDB) DeleteArticle(slug string) error {
 		return ErrNotFound
 	}
 
+	db.deleteArticle(slug)
+	return nil
+}
+
+// deleteArticle deletes an article with its comments, favorites, revisions
+// and previous slugs. The caller must hold the lock.
+func (db *InMemoryDB) deleteArticle(slug string) {
+	// Release its tags
+	if article, exists := db.articles[slug]; exists && db.listed(article) {
+		db.removeTags(article.TagList)
+	}
 	// Delete article
 	delete(db.articles, slug)
 	// Delete comments
 	delete(db.comments, slug)
 	// Delete favorites
 	delete(db.favorites, slug)
-
-	return nil
+	// Delete revisions
+	delete(db.revisions, slug)
+	// Old links of deleted articles are gone, and the slugs free again
+	for previous, current := range db.slugHistory {
+		if current == slug {
+			delete(db.slugHistory, previous)
+		}
+	}
 }
 
 // ListArticles returns a list of articles with optional filtering
@@ -304,6 +538,11 @@ func (db *InMemoryDB) ListArticles(tag, author, favorited string, limit, offset
 			continue
 		}
 
+		// Hide drafts and articles of suspended users
+		if !db.listed(article) {
+			continue
+		}
+
 		// Filter by favorited if provided
 		if favorited != "" {
 			if _, exists := db.favorites[article.Slug][favorited]; !exists {
@@ -330,7 +569,44 @@ func (db *InMemoryDB) ListArticles(tag, author, favorited string, limit, offset
 	return articles[offset:end], totalCount, nil
 }
 
-// GetTags returns all unique tags
+// listed reports whether an article is listed, which it is when it is
+// published and its author isn't suspended. Only the tags of listed articles
+// are counted. The caller must hold the lock.
+func (db *InMemoryDB) listed(article *api.Article) bool {
+	return article.Status == StatusPublished && !db.isSuspended(article.Author.Username)
+}
+
+// recountTags counts the tags of all listed articles again, after articles
+// were hidden or shown all at once. The caller must hold the lock.
+func (db *InMemoryDB) recountTags() {
+	clear(db.tags)
+	for _, article := range db.articles {
+		if db.listed(article) {
+			db.addTags(article.TagList)
+		}
+	}
+}
+
+// addTags counts an article using the tags. The caller must hold the lock.
+func (db *InMemoryDB) addTags(tags []string) {
+	for _, tag := range tags {
+		db.tags[tag]++
+	}
+}
+
+// removeTags stops counting an article using the tags, and forgets tags no
+// article uses anymore. The caller must hold the lock.
+func (db *InMemoryDB) removeTags(tags []string) {
+	for _, tag := range tags {
+		if db.tags[tag] <= 1 {
+			delete(db.tags, tag)
+		} else {
+			db.tags[tag]--
+		}
+	}
+}
+
+// GetTags returns all tags used by at least one listed article
 func (db *InMemoryDB) GetTags() []string {
 	db.mutex.RLock()
 	defer db.mutex.RUnlock()
@@ -343,6 +619,65 @@ func (db *InMemoryDB) GetTags() []string {
 	return tags
 }
 
+// Orders of TagCounts
+const (
+	TagOrderCount  = "count"  // Most used first
+	TagOrderRecent = "recent" // Most recently active first
+)
+
+// IsValidTagOrder reports whether order is an order of TagCounts
+func IsValidTagOrder(order string) bool {
+	return order == TagOrderCount || order == TagOrderRecent
+}
+
+// TagCounts returns the tags used by listed articles with the number of
+// listed articles using them, in the given order and limited to limit tags if
+// limit is positive. A tag is active when an article with it is created or updated.
+// Ties are broken by count and then alphabetically.
+func (db *InMemoryDB) TagCounts(order string, limit int) []api.TagCount {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	counts := make([]api.TagCount, 0, len(db.tags))
+	for tag, count := range db.tags {
+		counts = append(counts, api.TagCount{Tag: tag, ArticlesCount: count})
+	}
+
+	lastActive := make(map[string]time.Time)
+	if order == TagOrderRecent {
+		for _, article := range db.articles {
+			if !db.listed(article) {
+				continue
+			}
+			active := article.UpdatedAt
+			if article.CreatedAt.After(active) {
+				active = article.CreatedAt
+			}
+			for _, tag := range article.TagList {
+				if active.After(lastActive[tag]) {
+					lastActive[tag] = active
+				}
+			}
+		}
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		if activeA, activeB := lastActive[a.Tag], lastActive[b.Tag]; !activeA.Equal(activeB) {
+			return activeA.After(activeB)
+		}
+		if a.ArticlesCount != b.ArticlesCount {
+			return a.ArticlesCount > b.ArticlesCount
+		}
+		return a.Tag < b.Tag
+	})
+
+	if limit > 0 && limit < len(counts) {
+		counts = counts[:limit]
+	}
+	return counts
+}
+
 // AddComment adds a comment to an article
 func (db *InMemoryDB) AddComment(slug string, comment api.Comment) (int, error) {
 	db.mutex.Lock()
@@ -354,7 +689,8 @@ func (db *InMemoryDB) AddComment(slug string, comment api.Comment) (int, error)
 	}
 
 	// Generate ID for the comment
-	id := len(db.comments[slug]) + 1
+	db.lastCommentID++
+	id := db.lastCommentID
 	comment.Id = id
 
 	// Store comment
@@ -375,12 +711,31 @@ func (db *InMemoryDB) GetComments(slug string) ([]api.Comment, error) {
 
 	comments := make([]api.Comment, 0, len(db.comments[slug]))
 	for _, comment := range db.comments[slug] {
+		// Hide comments of suspended users
+		if db.isSuspended(comment.Author.Username) {
+			continue
+		}
 		comments = append(comments, *comment)
 	}
 
 	return comments, nil
 }
 
+// GetComment retrieves a comment of an article
+func (db *InMemoryDB) GetComment(slug string, id int) (*api.Comment, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	comment, exists := db.comments[slug][id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	// Return a copy of the comment
+	result := *comment
+	return &result, nil
+}
+
 // DeleteComment deletes a comment from an article
 func (db *InMemoryDB) DeleteComment(slug string, id int) error {
 	db.mutex.Lock()
@@ -538,7 +893,8 @@ func (db *InMemoryDB) IsFavorite(slug, username string) bool {
 	return db.favorites[slug][username]
 }
 
-// GetArticlesFeed returns articles from followed users
+// GetArticlesFeed returns articles from followed users and with followed
+// tags, most recent first, and their total number
 func (db *InMemoryDB) GetArticlesFeed(username string, limit, offset int) ([]api.Article, int, error) {
 	db.mutex.RLock()
 	defer db.mutex.RUnlock()
@@ -548,20 +904,30 @@ func (db *InMemoryDB) GetArticlesFeed(username string, limit, offset int) ([]api
 		return nil, 0, ErrNotFound
 	}
 
-	// Get followed users
-	followed, exists := db.follows[username]
-	if !exists {
-		return []api.Article{}, 0, nil // Not following anyone
-	}
+	// Get followed users and tags
+	followed := db.follows[username]
+	followedTags := db.tagFollows[username]
 
-	// Collect articles from followed users
+	// Collect articles from followed users or with followed tags, each
+	// article once even if it matches several times
 	var articles []api.Article
 	for _, article := range db.articles {
-		if followed[article.Author.Username] {
+		if !followed[article.Author.Username] && !hasFollowedTag(article, followedTags) {
+			continue
+		}
+		if db.listed(article) {
 			articles = append(articles, *article)
 		}
 	}
 
+	// Sort before paginating, so that pages don't overlap
+	sort.Slice(articles, func(i, j int) bool {
+		if !articles[i].CreatedAt.Equal(articles[j].CreatedAt) {
+			return articles[i].CreatedAt.After(articles[j].CreatedAt)
+		}
+		return articles[i].Slug < articles[j].Slug
+	})
+
 	// Calculate total count
 	totalCount := len(articles)
 
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"slices"
+	"sort"
 	"testing"
 	"time"
 
@@ -56,6 +58,11 @@ func TestUserOperations(t *testing.T) {
 		t.Errorf("Expected ErrInvalidCredentials for wrong password, got %v", err)
 	}
 
+	err = db.VerifyUserPassword("unknown@example.com", password)
+	if err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for unknown email, got %v", err)
+	}
+
 	// Test UpdateUser
 	newEmail := "newemail@example.com"
 	newUsername := "newusername"
@@ -86,6 +93,24 @@ func TestUserOperations(t *testing.T) {
 		t.Errorf("Expected updated image %s, got %s", newImage, updatedUser.Image)
 	}
 
+	// Test that passwords changed through UpdateUser are hashed and revoke
+	// earlier sessions
+	newPassword := "newpassword456"
+	changedAt := time.Now()
+	if _, err := db.UpdateUser(newEmail, api.UpdateUser{Password: &newPassword}); err != nil {
+		t.Fatalf("Failed to update password: %v", err)
+	}
+	if err := db.VerifyUserPassword(newEmail, newPassword); err != nil {
+		t.Errorf("Failed to verify updated password: %v", err)
+	}
+	internalUser, _ := db.GetInternalUserByEmail(newEmail)
+	if internalUser.Password == newPassword {
+		t.Error("Expected updated password to be stored hashed")
+	}
+	if internalUser.SessionsValidAt.Before(changedAt) {
+		t.Errorf("Expected sessions to be revoked at the password change, got %v", internalUser.SessionsValidAt)
+	}
+
 	// Test conflict errors
 	conflictUser := api.User{
 		Username: "conflictuser",
@@ -168,7 +193,7 @@ func TestArticleOperations(t *testing.T) {
 		Body:        &newBody,
 	}
 
-	updatedArticle, err := db.UpdateArticle(article.Slug, updates)
+	updatedArticle, err := db.UpdateArticle(article.Slug, updates, user.Username)
 	if err != nil {
 		t.Fatalf("Failed to update article: %v", err)
 	}
@@ -181,6 +206,9 @@ func TestArticleOperations(t *testing.T) {
 	if updatedArticle.Body != newBody {
 		t.Errorf("Expected updated body %s, got %s", newBody, updatedArticle.Body)
 	}
+	if updatedArticle.Slug != "updated-title" {
+		t.Errorf("Expected slug updated-title after the title change, got %s", updatedArticle.Slug)
+	}
 
 	// Test ListArticles
 	articles, count, err := db.ListArticles("", "", "", 10, 0)
@@ -213,18 +241,129 @@ func TestArticleOperations(t *testing.T) {
 	}
 
 	// Test DeleteArticle
-	err = db.DeleteArticle(article.Slug)
+	err = db.DeleteArticle(updatedArticle.Slug)
 	if err != nil {
 		t.Fatalf("Failed to delete article: %v", err)
 	}
 
 	// Verify article is deleted
-	_, err = db.GetArticle(article.Slug)
+	_, err = db.GetArticle(updatedArticle.Slug)
 	if err != ErrNotFound {
 		t.Errorf("Expected ErrNotFound after deletion, got %v", err)
 	}
 }
 
+func TestSlugHistory(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+	if err := db.CreateUser(api.User{Username: "author", Email: "author@example.com"}, "password"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := db.CreateUser(api.User{Username: "reader", Email: "reader@example.com"}, "password"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	author := api.Profile{Username: "author"}
+	if err := db.CreateArticle(api.Article{Title: "First Title", Slug: "first-title", Author: author}); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	if _, err := db.AddComment("first-title", api.Comment{Body: "Nice", Author: api.Profile{Username: "reader"}}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	if err := db.FavoriteArticle("first-title", "reader"); err != nil {
+		t.Fatalf("Failed to favorite article: %v", err)
+	}
+
+	rename := func(slug, title string) string {
+		t.Helper()
+		updated, err := db.UpdateArticle(slug, api.UpdateArticle{Title: &title}, "author")
+		if err != nil {
+			t.Fatalf("Failed to update article: %v", err)
+		}
+		return updated.Slug
+	}
+
+	// A new title moves the article with its comments and favorites
+	if slug := rename("first-title", "Second Title"); slug != "second-title" {
+		t.Fatalf("Expected slug second-title, got %s", slug)
+	}
+	if _, err := db.GetArticle("first-title"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for the old slug, got %v", err)
+	}
+	if comments, _ := db.GetComments("second-title"); len(comments) != 1 {
+		t.Errorf("Expected 1 comment under the new slug, got %d", len(comments))
+	}
+	if !db.IsFavorite("second-title", "reader") {
+		t.Errorf("Expected favorite to move to the new slug")
+	}
+	if current, ok := db.ResolveSlug("first-title"); !ok || current != "second-title" {
+		t.Errorf("Expected first-title to resolve to second-title, got %q, %v", current, ok)
+	}
+
+	// Older slugs resolve straight to the newest one
+	rename("second-title", "Third Title")
+	for _, old := range []string{"first-title", "second-title"} {
+		if current, ok := db.ResolveSlug(old); !ok || current != "third-title" {
+			t.Errorf("Expected %s to resolve to third-title, got %q, %v", old, current, ok)
+		}
+	}
+
+	// Unchanged titles keep the slug
+	title, description := "Third Title", "New description"
+	if updated, err := db.UpdateArticle("third-title", api.UpdateArticle{Title: &title, Description: &description}, "author"); err != nil || updated.Slug != "third-title" {
+		t.Errorf("Expected slug third-title for an unchanged title, got %v", err)
+	}
+
+	// Previous slugs stay reserved for their article
+	if created := db.CreateArticleWithUniqueSlug(api.Article{Title: "First Title", Slug: "first-title", Author: author}); created != "first-title-1" {
+		t.Errorf("Expected slug first-title-1 next to the previous slug, got %s", created)
+	}
+	if err := db.CreateArticle(api.Article{Title: "Second Title", Slug: "second-title", Author: author}); err != ErrConflict {
+		t.Errorf("Expected ErrConflict for a previous slug, got %v", err)
+	}
+
+	// The article can take a previous slug back
+	if slug := rename("third-title", "First Title"); slug != "first-title" {
+		t.Errorf("Expected the article to reclaim first-title, got %s", slug)
+	}
+	if _, ok := db.ResolveSlug("first-title"); ok {
+		t.Errorf("Expected first-title to be current again")
+	}
+	if current, ok := db.ResolveSlug("third-title"); !ok || current != "first-title" {
+		t.Errorf("Expected third-title to resolve to first-title, got %q, %v", current, ok)
+	}
+
+	// Deleting the article frees its previous slugs
+	if err := db.DeleteArticle("first-title"); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	for _, old := range []string{"second-title", "third-title"} {
+		if _, ok := db.ResolveSlug(old); ok {
+			t.Errorf("Expected %s to be forgotten after deletion", old)
+		}
+	}
+}
+
+func TestCreateArticleWithUniqueSlug(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+
+	// Take the slug and its first suffixes
+	expected := []string{"same-title", "same-title-1", "same-title-2"}
+	for _, slug := range expected {
+		if created := db.CreateArticleWithUniqueSlug(api.Article{Title: "Same Title", Slug: "same-title"}); created != slug {
+			t.Errorf("Expected slug %s, got %s", slug, created)
+		}
+		if article, err := db.GetArticle(slug); err != nil || article.Slug != slug {
+			t.Errorf("Expected article stored under %s, got %v", slug, err)
+		}
+	}
+
+	// Reserved slugs are skipped
+	if created := db.CreateArticleWithUniqueSlug(api.Article{Title: "Feed", Slug: "feed"}); created != "feed-1" {
+		t.Errorf("Expected slug feed-1, got %s", created)
+	}
+}
+
 func TestCommentOperations(t *testing.T) {
 	// Create a new in-memory database
 	db := NewInMemoryDB()
@@ -290,6 +429,18 @@ func TestCommentOperations(t *testing.T) {
 		t.Errorf("Expected comment body %s, got %s", comment.Body, comments[0].Body)
 	}
 
+	// Test GetComment
+	retrievedComment, err := db.GetComment(article.Slug, commentID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if retrievedComment.Author.Username != author.Username {
+		t.Errorf("Expected comment author %s, got %s", author.Username, retrievedComment.Author.Username)
+	}
+	if _, err := db.GetComment(article.Slug, 42); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for unknown comment, got %v", err)
+	}
+
 	// Test DeleteComment
 	err = db.DeleteComment(article.Slug, commentID)
 	if err != nil {
@@ -504,3 +655,132 @@ func TestGetTags(t *testing.T) {
 		t.Errorf("Missing tags: %v", expectedTags)
 	}
 }
+
+func TestTagCounts(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+	author := api.Profile{Username: "author"}
+	db.CreateArticle(api.Article{Title: "First", Slug: "first", Author: author, TagList: []string{"go", "web"}, CreatedAt: time.Now()})
+	db.CreateArticle(api.Article{Title: "Second", Slug: "second", Author: author, TagList: []string{"go"}, CreatedAt: time.Now()})
+
+	expectTags := func(step string, expected ...string) {
+		t.Helper()
+		tags := db.GetTags()
+		sort.Strings(tags)
+		if !slices.Equal(tags, expected) {
+			t.Errorf("%s: expected tags %v, got %v", step, expected, tags)
+		}
+	}
+	expectTags("Created", "go", "web")
+
+	// Replaced tags are gone once no article uses them
+	tags := []string{"go", "rest"}
+	updated, err := db.UpdateArticle("first", api.UpdateArticle{TagList: &tags}, "author")
+	if err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	if !slices.Equal(updated.TagList, tags) {
+		t.Errorf("Expected tags %v, got %v", tags, updated.TagList)
+	}
+	expectTags("Updated", "go", "rest")
+
+	// Tags are kept while another article uses them
+	if err := db.DeleteArticle("first"); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	expectTags("Deleted first", "go")
+	if err := db.DeleteArticle("second"); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	expectTags("Deleted second")
+}
+
+func TestTagCountsOrder(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+	author := api.Profile{Username: "author"}
+	earlier := time.Now().Add(-time.Hour)
+	db.CreateArticle(api.Article{Title: "Old", Slug: "old", Author: author, TagList: []string{"go", "web"}, CreatedAt: earlier, UpdatedAt: earlier})
+	db.CreateArticle(api.Article{Title: "Older", Slug: "older", Author: author, TagList: []string{"web"}, CreatedAt: earlier, UpdatedAt: earlier})
+	db.CreateArticle(api.Article{Title: "New", Slug: "new", Author: author, TagList: []string{"rust"}, CreatedAt: time.Now()})
+
+	tagsOf := func(counts []api.TagCount) []string {
+		tags := make([]string, len(counts))
+		for i, count := range counts {
+			tags[i] = count.Tag
+		}
+		return tags
+	}
+
+	// Most used first, ties alphabetically
+	counts := db.TagCounts(TagOrderCount, 0)
+	if tags := tagsOf(counts); !slices.Equal(tags, []string{"web", "go", "rust"}) || counts[0].ArticlesCount != 2 {
+		t.Errorf("Expected web twice, then go and rust, got %v", counts)
+	}
+
+	// Most recently active first, updating an article makes its tags active
+	if tags := tagsOf(db.TagCounts(TagOrderRecent, 0)); !slices.Equal(tags, []string{"rust", "web", "go"}) {
+		t.Errorf("Expected rust, web and go, got %v", tags)
+	}
+	body := "Updated"
+	if _, err := db.UpdateArticle("old", api.UpdateArticle{Body: &body}, "author"); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	if tags := tagsOf(db.TagCounts(TagOrderRecent, 2)); !slices.Equal(tags, []string{"web", "go"}) {
+		t.Errorf("Expected web and go, got %v", tags)
+	}
+}
+
+func TestTagCountsOfListedArticles(t *testing.T) {
+	// Create a new in-memory database with a published, a draft and a
+	// scheduled article
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "author", Email: "author@example.com"}, "password123")
+	author := api.Profile{Username: "author"}
+	db.CreateArticle(api.Article{Title: "Published", Slug: "published", Author: author, TagList: []string{"go"}, CreatedAt: time.Now()})
+	db.CreateArticle(api.Article{Title: "Draft", Slug: "draft", Author: author, TagList: []string{"go", "secret"}, Status: StatusDraft, CreatedAt: time.Now()})
+	publishAt := time.Now().Add(time.Hour)
+	db.CreateArticle(api.Article{Title: "Scheduled", Slug: "scheduled", Author: author, TagList: []string{"later"}, Status: StatusScheduled, PublishAt: &publishAt, CreatedAt: time.Now()})
+
+	expectCounts := func(step string, expected ...api.TagCount) {
+		t.Helper()
+		counts := db.TagCounts(TagOrderCount, 0)
+		if !slices.Equal(counts, expected) {
+			t.Errorf("%s: expected tag counts %v, got %v", step, expected, counts)
+		}
+	}
+
+	// Drafts and scheduled articles don't count
+	expectCounts("Created", api.TagCount{Tag: "go", ArticlesCount: 1})
+	if counts := db.TagCounts(TagOrderRecent, 0); len(counts) != 1 {
+		t.Errorf("Expected one recent tag, got %v", counts)
+	}
+
+	// Publishing the draft counts its tags
+	status := StatusPublished
+	if _, err := db.UpdateArticle("draft", api.UpdateArticle{Status: &status}, "author"); err != nil {
+		t.Fatalf("Failed to publish article: %v", err)
+	}
+	expectCounts("Published draft", api.TagCount{Tag: "go", ArticlesCount: 2}, api.TagCount{Tag: "secret", ArticlesCount: 1})
+
+	// So does the publisher
+	db.PublishDue(time.Now().Add(2 * time.Hour))
+	expectCounts("Published scheduled", api.TagCount{Tag: "go", ArticlesCount: 2}, api.TagCount{Tag: "later", ArticlesCount: 1}, api.TagCount{Tag: "secret", ArticlesCount: 1})
+
+	// Unpublishing and deleting an unpublished article release them
+	status = StatusUnlisted
+	if _, err := db.UpdateArticle("draft", api.UpdateArticle{Status: &status}, "author"); err != nil {
+		t.Fatalf("Failed to unlist article: %v", err)
+	}
+	expectCounts("Unlisted", api.TagCount{Tag: "go", ArticlesCount: 1}, api.TagCount{Tag: "later", ArticlesCount: 1})
+	if err := db.DeleteArticle("draft"); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	expectCounts("Deleted", api.TagCount{Tag: "go", ArticlesCount: 1}, api.TagCount{Tag: "later", ArticlesCount: 1})
+
+	// Articles of suspended authors don't count until the suspension is lifted
+	db.SuspendUser("author@example.com", "spam", time.Now())
+	expectCounts("Suspended")
+	db.UnsuspendUser("author@example.com")
+	expectCounts("Unsuspended", api.TagCount{Tag: "go", ArticlesCount: 1}, api.TagCount{Tag: "later", ArticlesCount: 1})
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"sort"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+// UserComment is a comment together with the article it was written on
+type UserComment struct {
+	ArticleSlug string
+	api.Comment
+}
+
+// UserData is the content and activity of a user, for data exports
+type UserData struct {
+	Articles     []api.Article // Articles written by the user, oldest first
+	Comments     []UserComment // Comments written by the user, oldest first
+	Following    []string      // Usernames of followed users, sorted
+	FollowedTags []string      // Followed tags, sorted
+	Favorites    []string      // Slugs of favorited articles, sorted
+	Identities   []string      // Linked external identity providers, sorted
+}
+
+// GetUserData collects everything a user wrote or did, for a data export
+func (db *InMemoryDB) GetUserData(email string) (*UserData, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	username := internalUser.Username
+
+	data := &UserData{
+		Articles:   []api.Article{},
+		Comments:   []UserComment{},
+		Following:  []string{},
+		Favorites:  []string{},
+		Identities: []string{},
+	}
+
+	for slug, article := range db.articles {
+		if article.Author.Username == username {
+			a := *article
+			a.Favorited = db.favorites[slug][username]
+			data.Articles = append(data.Articles, a)
+		}
+	}
+	sort.Slice(data.Articles, func(i, j int) bool {
+		return data.Articles[i].CreatedAt.Before(data.Articles[j].CreatedAt)
+	})
+
+	for slug, comments := range db.comments {
+		for _, comment := range comments {
+			if comment.Author.Username == username {
+				data.Comments = append(data.Comments, UserComment{ArticleSlug: slug, Comment: *comment})
+			}
+		}
+	}
+	sort.Slice(data.Comments, func(i, j int) bool {
+		return data.Comments[i].Id < data.Comments[j].Id
+	})
+
+	for followed := range db.follows[username] {
+		data.Following = append(data.Following, followed)
+	}
+	sort.Strings(data.Following)
+
+	data.FollowedTags = db.followedTags(username)
+
+	for slug, favoritedBy := range db.favorites {
+		if favoritedBy[username] {
+			data.Favorites = append(data.Favorites, slug)
+		}
+	}
+	sort.Strings(data.Favorites)
+
+	for key, linked := range db.identities {
+		if linked == email {
+			data.Identities = append(data.Identities, key.provider)
+		}
+	}
+	sort.Strings(data.Identities)
+
+	return data, nil
+}
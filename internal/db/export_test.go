@@ -0,0 +1,62 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+func TestGetUserData(t *testing.T) {
+	// Create a new in-memory database with two users interacting
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "alice", Email: "alice@example.com"}, "password123")
+	db.CreateUser(api.User{Username: "bob", Email: "bob@example.com"}, "password123")
+	now := time.Now()
+	db.CreateArticle(api.Article{Slug: "second", Author: api.Profile{Username: "alice"}, CreatedAt: now})
+	db.CreateArticle(api.Article{Slug: "first", Author: api.Profile{Username: "alice"}, CreatedAt: now.Add(-time.Hour)})
+	db.CreateArticle(api.Article{Slug: "bobs", Author: api.Profile{Username: "bob"}, CreatedAt: now})
+	db.AddComment("bobs", api.Comment{Body: "Nice", Author: api.Profile{Username: "alice"}})
+	db.AddComment("first", api.Comment{Body: "Thanks", Author: api.Profile{Username: "bob"}})
+	db.AddComment("first", api.Comment{Body: "You're welcome", Author: api.Profile{Username: "alice"}})
+	db.FollowUser("alice", "bob")
+	db.FollowTag("alice", "go")
+	db.FavoriteArticle("bobs", "alice")
+	db.FavoriteArticle("first", "alice")
+	db.LinkIdentity("company", "sub-1", "alice@example.com")
+
+	data, err := db.GetUserData("alice@example.com")
+	if err != nil {
+		t.Fatalf("Failed to get user data: %v", err)
+	}
+
+	// Only alice's articles, oldest first
+	if len(data.Articles) != 2 || data.Articles[0].Slug != "first" || data.Articles[1].Slug != "second" {
+		t.Errorf("Expected articles first and second, got %v", data.Articles)
+	} else if !data.Articles[0].Favorited || data.Articles[1].Favorited {
+		t.Errorf("Expected only the first article to be favorited")
+	}
+
+	// Only alice's comments, with the article they belong to
+	if len(data.Comments) != 2 || data.Comments[0].ArticleSlug != "bobs" || data.Comments[1].Body != "You're welcome" {
+		t.Errorf("Unexpected comments: %v", data.Comments)
+	}
+
+	if len(data.Following) != 1 || data.Following[0] != "bob" {
+		t.Errorf("Expected following [bob], got %v", data.Following)
+	}
+	if len(data.FollowedTags) != 1 || data.FollowedTags[0] != "go" {
+		t.Errorf("Expected followed tags [go], got %v", data.FollowedTags)
+	}
+	if len(data.Favorites) != 2 || data.Favorites[0] != "bobs" || data.Favorites[1] != "first" {
+		t.Errorf("Expected favorites [bobs first], got %v", data.Favorites)
+	}
+	if len(data.Identities) != 1 || data.Identities[0] != "company" {
+		t.Errorf("Expected identities [company], got %v", data.Identities)
+	}
+
+	// Unknown users
+	if _, err := db.GetUserData("nobody@example.com"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package db
+
+// identityKey identifies an account at an external identity provider
+type identityKey struct {
+	provider string
+	subject  string
+}
+
+// LinkIdentity links the account of an external identity provider to a user.
+// It returns ErrConflict if the account is already linked to another user.
+func (db *InMemoryDB) LinkIdentity(provider, subject, email string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if _, exists := db.users[email]; !exists {
+		return ErrNotFound
+	}
+
+	key := identityKey{provider: provider, subject: subject}
+	if linked, exists := db.identities[key]; exists && linked != email {
+		return ErrConflict
+	}
+
+	db.identities[key] = email
+	return nil
+}
+
+// GetEmailByIdentity returns the email of the user linked to an account at an
+// external identity provider
+func (db *InMemoryDB) GetEmailByIdentity(provider, subject string) (string, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	email, exists := db.identities[identityKey{provider: provider, subject: subject}]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return email, nil
+}
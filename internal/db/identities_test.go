@@ -0,0 +1,54 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+func TestIdentities(t *testing.T) {
+	// Create a new in-memory database with two users
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "user1", Email: "user1@example.com"}, "password123")
+	db.CreateUser(api.User{Username: "user2", Email: "user2@example.com"}, "password123")
+
+	// Unlinked identities are not found
+	if _, err := db.GetEmailByIdentity("company", "sub-1"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Link an identity
+	if err := db.LinkIdentity("company", "sub-1", "user1@example.com"); err != nil {
+		t.Fatalf("Failed to link identity: %v", err)
+	}
+	email, err := db.GetEmailByIdentity("company", "sub-1")
+	if err != nil || email != "user1@example.com" {
+		t.Errorf("Expected user1@example.com, got %s, %v", email, err)
+	}
+
+	// Linking again to the same user is fine, to another user is not
+	if err := db.LinkIdentity("company", "sub-1", "user1@example.com"); err != nil {
+		t.Errorf("Expected relinking to succeed, got %v", err)
+	}
+	if err := db.LinkIdentity("company", "sub-1", "user2@example.com"); err != ErrConflict {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+
+	// Subjects are scoped to their provider
+	if _, err := db.GetEmailByIdentity("other", "sub-1"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for other provider, got %v", err)
+	}
+
+	// Unknown users can't be linked
+	if err := db.LinkIdentity("company", "sub-2", "unknown@example.com"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Links follow email changes
+	newEmail := "renamed@example.com"
+	db.UpdateUser("user1@example.com", api.UpdateUser{Email: &newEmail})
+	email, _ = db.GetEmailByIdentity("company", "sub-1")
+	if email != newEmail {
+		t.Errorf("Expected link to follow email change, got %s", email)
+	}
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalToken is a long-lived API token a user created for scripts and
+// integrations. Only its hash is stored, the token is shown once.
+type PersonalToken struct {
+	ID         string
+	Hash       string // Hash of the token, the token itself is never stored
+	Email      string
+	Name       string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time // nil if the token doesn't expire
+	LastUsedAt *time.Time // nil if the token was never used
+}
+
+// CreatePersonalToken stores a personal token and assigns it an ID
+func (db *InMemoryDB) CreatePersonalToken(token PersonalToken) (*PersonalToken, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if _, exists := db.users[token.Email]; !exists {
+		return nil, ErrNotFound
+	}
+	if _, exists := db.personalTokens[token.Hash]; exists {
+		return nil, ErrConflict
+	}
+
+	token.ID = uuid.NewString()
+	token.Scopes = append([]string(nil), token.Scopes...)
+	db.personalTokens[token.Hash] = &token
+
+	result := token
+	return &result, nil
+}
+
+// ListPersonalTokens returns the personal tokens of a user, oldest first
+func (db *InMemoryDB) ListPersonalTokens(email string) []PersonalToken {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	tokens := []PersonalToken{}
+	for _, token := range db.personalTokens {
+		if token.Email == email {
+			tokens = append(tokens, *token)
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].ID < tokens[j].ID
+		}
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+
+	return tokens
+}
+
+// DeletePersonalToken revokes a personal token of a user
+func (db *InMemoryDB) DeletePersonalToken(email, id string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	for hash, token := range db.personalTokens {
+		if token.ID == id && token.Email == email {
+			delete(db.personalTokens, hash)
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+// AuthenticatePersonalToken looks up a personal token by its hash and records
+// that it was used. It returns ErrNotFound if the token doesn't exist or has
+// expired.
+func (db *InMemoryDB) AuthenticatePersonalToken(hash string, now time.Time) (*PersonalToken, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	token, exists := db.personalTokens[hash]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	if token.ExpiresAt != nil && !now.Before(*token.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+
+	token.LastUsedAt = &now
+
+	result := *token
+	return &result, nil
+}
+
+// deletePersonalTokens revokes all personal tokens of a user. The caller must
+// hold the write lock.
+func (db *InMemoryDB) deletePersonalTokens(email string) {
+	for hash, token := range db.personalTokens {
+		if token.Email == email {
+			delete(db.personalTokens, hash)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+func TestPersonalTokens(t *testing.T) {
+	// Create a new in-memory database with two users
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "user1", Email: "user1@example.com"}, "password123")
+	db.CreateUser(api.User{Username: "user2", Email: "user2@example.com"}, "password123")
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Create tokens
+	first, err := db.CreatePersonalToken(PersonalToken{Hash: "hash-1", Email: "user1@example.com", Name: "CI", Scopes: []string{"articles:write"}, CreatedAt: now})
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	if first.ID == "" {
+		t.Error("Expected token to get an ID")
+	}
+	if _, err := db.CreatePersonalToken(PersonalToken{Hash: "hash-2", Email: "user1@example.com", Name: "Bot", CreatedAt: now.Add(time.Minute)}); err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	if _, err := db.CreatePersonalToken(PersonalToken{Hash: "hash-3", Email: "unknown@example.com"}); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for unknown user, got %v", err)
+	}
+
+	// Tokens are listed per user, oldest first
+	tokens := db.ListPersonalTokens("user1@example.com")
+	if len(tokens) != 2 || tokens[0].Name != "CI" || tokens[1].Name != "Bot" {
+		t.Errorf("Unexpected tokens: %+v", tokens)
+	}
+	if len(db.ListPersonalTokens("user2@example.com")) != 0 {
+		t.Error("Expected no tokens for user2")
+	}
+
+	// Authenticating records the last use
+	token, err := db.AuthenticatePersonalToken("hash-1", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+	if token.Email != "user1@example.com" || len(token.Scopes) != 1 {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+	tokens = db.ListPersonalTokens("user1@example.com")
+	if tokens[0].LastUsedAt == nil || !tokens[0].LastUsedAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected last use to be recorded, got %v", tokens[0].LastUsedAt)
+	}
+	if tokens[1].LastUsedAt != nil {
+		t.Error("Expected unused token to have no last use")
+	}
+	if _, err := db.AuthenticatePersonalToken("unknown", now); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Other users can't revoke the token
+	if err := db.DeletePersonalToken("user2@example.com", first.ID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Tokens follow email changes
+	newEmail := "renamed@example.com"
+	db.UpdateUser("user1@example.com", api.UpdateUser{Email: &newEmail})
+	if token, _ := db.AuthenticatePersonalToken("hash-1", now); token == nil || token.Email != newEmail {
+		t.Errorf("Expected token to follow email change, got %+v", token)
+	}
+
+	// Revoked tokens no longer authenticate
+	if err := db.DeletePersonalToken(newEmail, first.ID); err != nil {
+		t.Fatalf("Failed to delete token: %v", err)
+	}
+	if _, err := db.AuthenticatePersonalToken("hash-1", now); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound after revoking, got %v", err)
+	}
+}
+
+func TestPersonalTokenExpiry(t *testing.T) {
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "user1", Email: "user1@example.com"}, "password123")
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(24 * time.Hour)
+	db.CreatePersonalToken(PersonalToken{Hash: "hash-1", Email: "user1@example.com", CreatedAt: now, ExpiresAt: &expiresAt})
+
+	if _, err := db.AuthenticatePersonalToken("hash-1", expiresAt.Add(-time.Second)); err != nil {
+		t.Errorf("Expected token to be valid before expiry, got %v", err)
+	}
+	if _, err := db.AuthenticatePersonalToken("hash-1", expiresAt); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound after expiry, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"sort"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+// Publication statuses of articles
+const (
+	StatusDraft     = "draft"     // Only visible to the author
+	StatusScheduled = "scheduled" // Only visible to the author until its publish time
+	StatusPublished = "published" // Visible to everyone and listed
+	StatusUnlisted  = "unlisted"  // Visible to everyone with the link, but not listed
+)
+
+// IsValidStatus reports whether status is a publication status
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusDraft, StatusScheduled, StatusPublished, StatusUnlisted:
+		return true
+	}
+	return false
+}
+
+// IsPublic reports whether everyone may read an article, which drafts and
+// scheduled articles only their author may
+func IsPublic(article api.Article) bool {
+	return article.Status == StatusPublished || article.Status == StatusUnlisted
+}
+
+// ListDrafts returns the draft and scheduled articles of an author, most
+// recently updated first, and their total number
+func (db *InMemoryDB) ListDrafts(author string, limit, offset int) ([]api.Article, int) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	var drafts []api.Article
+	for _, article := range db.articles {
+		if article.Author.Username == author && !IsPublic(*article) {
+			drafts = append(drafts, *article)
+		}
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].UpdatedAt.After(drafts[j].UpdatedAt)
+	})
+
+	// Apply pagination
+	totalCount := len(drafts)
+	if offset >= len(drafts) {
+		return []api.Article{}, totalCount
+	}
+
+	end := offset + limit
+	if end > len(drafts) {
+		end = len(drafts)
+	}
+
+	return drafts[offset:end], totalCount
+}
+
+// PublishDue publishes the scheduled articles whose publish time is not after
+// now, dated now, and returns their slugs
+func (db *InMemoryDB) PublishDue(now time.Time) []string {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	var published []string
+	for slug, article := range db.articles {
+		if article.Status != StatusScheduled || article.PublishAt == nil || article.PublishAt.After(now) {
+			continue
+		}
+		article.Status = StatusPublished
+		article.PublishAt = nil
+		publish(article, now)
+		if db.listed(article) {
+			db.addTags(article.TagList)
+		}
+		published = append(published, slug)
+	}
+
+	sort.Strings(published)
+	return published
+}
+
+// publish dates an article that becomes public to its publish time, so that
+// it's ordered and counted as new rather than as of when it was drafted
+func publish(article *api.Article, at time.Time) {
+	article.CreatedAt = at
+	article.UpdatedAt = at
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+// createStatusArticle creates an article of author with the given status
+func createStatusArticle(t *testing.T, db *InMemoryDB, slug, status string, publishAt *time.Time) {
+	t.Helper()
+	err := db.CreateArticle(api.Article{
+		Title:     slug,
+		Slug:      slug,
+		Author:    api.Profile{Username: "author"},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Status:    status,
+		PublishAt: publishAt,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+}
+
+func TestIsValidStatus(t *testing.T) {
+	for status, expected := range map[string]bool{
+		StatusDraft:     true,
+		StatusScheduled: true,
+		StatusPublished: true,
+		StatusUnlisted:  true,
+		"":              false,
+		"archived":      false,
+	} {
+		if IsValidStatus(status) != expected {
+			t.Errorf("%q: expected %v", status, expected)
+		}
+	}
+}
+
+func TestListDrafts(t *testing.T) {
+	db := NewInMemoryDB()
+	later := time.Now().Add(time.Hour)
+	createStatusArticle(t, db, "published", "", nil)
+	createStatusArticle(t, db, "unlisted", StatusUnlisted, nil)
+	createStatusArticle(t, db, "draft", StatusDraft, nil)
+	createStatusArticle(t, db, "scheduled", StatusScheduled, &later)
+
+	// Articles default to published
+	if article, _ := db.GetArticle("published"); article.Status != StatusPublished {
+		t.Errorf("Expected status %s, got %s", StatusPublished, article.Status)
+	}
+
+	// Only drafts and scheduled articles are drafts, newest first
+	drafts, count := db.ListDrafts("author", 20, 0)
+	if count != 2 || len(drafts) != 2 || drafts[0].Slug != "scheduled" || drafts[1].Slug != "draft" {
+		t.Errorf("Expected scheduled and draft, got %d: %+v", count, drafts)
+	}
+	if drafts, count := db.ListDrafts("author", 20, 2); count != 2 || len(drafts) != 0 {
+		t.Errorf("Expected an empty page of 2 drafts, got %d: %+v", count, drafts)
+	}
+	if _, count := db.ListDrafts("other", 20, 0); count != 0 {
+		t.Errorf("Expected no drafts of other authors, got %d", count)
+	}
+
+	// Only published articles are listed
+	articles, count, err := db.ListArticles("", "", "", 20, 0)
+	if err != nil {
+		t.Fatalf("Failed to list articles: %v", err)
+	}
+	if count != 1 || articles[0].Slug != "published" {
+		t.Errorf("Expected only the published article, got %d: %+v", count, articles)
+	}
+}
+
+func TestPublishDue(t *testing.T) {
+	db := NewInMemoryDB()
+	now := time.Now()
+	earlier, later := now.Add(-time.Minute), now.Add(time.Minute)
+	createStatusArticle(t, db, "due", StatusScheduled, &earlier)
+	createStatusArticle(t, db, "exactly-due", StatusScheduled, &now)
+	createStatusArticle(t, db, "not-due", StatusScheduled, &later)
+	createStatusArticle(t, db, "draft", StatusDraft, nil)
+
+	published := db.PublishDue(now)
+	if len(published) != 2 || published[0] != "due" || published[1] != "exactly-due" {
+		t.Errorf("Expected due and exactly-due to be published, got %v", published)
+	}
+	article, _ := db.GetArticle("due")
+	if article.Status != StatusPublished || article.PublishAt != nil {
+		t.Errorf("Expected a published article without publish time, got %s %v", article.Status, article.PublishAt)
+	}
+	if article, _ := db.GetArticle("not-due"); article.Status != StatusScheduled {
+		t.Errorf("Expected status %s, got %s", StatusScheduled, article.Status)
+	}
+
+	// Published articles are not published again
+	if published := db.PublishDue(later); len(published) != 1 || published[0] != "not-due" {
+		t.Errorf("Expected only not-due to be published, got %v", published)
+	}
+}
+
+func TestPublishedArticlesAreDatedToTheirPublishTime(t *testing.T) {
+	db := NewInMemoryDB()
+	for _, username := range []string{"author", "reader"} {
+		if err := db.CreateUser(api.User{Username: username, Email: username + "@example.com"}, "password"); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	if err := db.FollowUser("reader", "author"); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+
+	// Drafted a month ago, published now
+	now := time.Now()
+	drafted := now.AddDate(0, -1, 0)
+	for _, article := range []api.Article{
+		{Slug: "scheduled", Status: StatusScheduled, PublishAt: &now},
+		{Slug: "draft", Status: StatusDraft},
+		{Slug: "older", Status: StatusPublished, CreatedAt: now.AddDate(0, 0, -1)},
+	} {
+		article.Title = article.Slug
+		article.Author = api.Profile{Username: "author"}
+		if article.CreatedAt.IsZero() {
+			article.CreatedAt = drafted
+		}
+		article.UpdatedAt = article.CreatedAt
+		if err := db.CreateArticle(article); err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+
+	db.PublishDue(now)
+	if article, _ := db.GetArticle("scheduled"); !article.CreatedAt.Equal(now) || !article.UpdatedAt.Equal(now) {
+		t.Errorf("Expected the scheduled article to be dated %v, got %v and %v", now, article.CreatedAt, article.UpdatedAt)
+	}
+	status := StatusPublished
+	published, err := db.UpdateArticle("draft", api.UpdateArticle{Status: &status}, "author")
+	if err != nil {
+		t.Fatalf("Failed to publish draft: %v", err)
+	}
+	if !published.CreatedAt.After(now) || !published.UpdatedAt.Equal(published.CreatedAt) {
+		t.Errorf("Expected the draft to be dated to its publication, got %v and %v", published.CreatedAt, published.UpdatedAt)
+	}
+
+	// Newly published articles come before older ones in the feed
+	articles, count, err := db.GetArticlesFeed("reader", 20, 0)
+	if err != nil {
+		t.Fatalf("Failed to get feed: %v", err)
+	}
+	if count != 3 || articles[0].Slug != "draft" || articles[1].Slug != "scheduled" || articles[2].Slug != "older" {
+		t.Errorf("Expected draft, scheduled and older, got %d: %+v", count, articles)
+	}
+
+	// Updating a published article keeps its date
+	if updated, _ := db.UpdateArticle("older", api.UpdateArticle{Status: &status}, "author"); !updated.CreatedAt.Equal(now.AddDate(0, 0, -1)) {
+		t.Errorf("Expected the published article to keep its date, got %v", updated.CreatedAt)
+	}
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+// ListRevisions returns the revisions of an article, oldest first
+func (db *InMemoryDB) ListRevisions(slug string) ([]api.ArticleRevision, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	revisions, exists := db.revisions[slug]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	// Return a copy, revisions are added by later updates
+	return append([]api.ArticleRevision(nil), revisions...), nil
+}
+
+// GetRevision returns a revision of an article by its number, starting at 1
+func (db *InMemoryDB) GetRevision(slug string, number int) (*api.ArticleRevision, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	return db.getRevision(slug, number)
+}
+
+// getRevision implements GetRevision. The caller must hold the lock.
+func (db *InMemoryDB) getRevision(slug string, number int) (*api.ArticleRevision, error) {
+	revisions := db.revisions[slug]
+	if number < 1 || number > len(revisions) {
+		return nil, ErrNotFound
+	}
+
+	revision := revisions[number-1]
+	return &revision, nil
+}
+
+// RestoreRevision sets the title, description and body of an article back to
+// those of an earlier revision, which is stored as a new revision by the
+// editor. Like any other update, a different title changes the slug.
+func (db *InMemoryDB) RestoreRevision(slug string, number int, editor string) (*api.Article, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	revision, err := db.getRevision(slug, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.updateArticle(slug, api.UpdateArticle{
+		Title:       &revision.Title,
+		Description: &revision.Description,
+		Body:        &revision.Body,
+	}, editor)
+}
+
+// addRevision stores the current content of an article as its next
+// revision. The caller must hold the lock.
+func (db *InMemoryDB) addRevision(article *api.Article, author api.Profile, at time.Time) {
+	db.revisions[article.Slug] = append(db.revisions[article.Slug], api.ArticleRevision{
+		Number:      len(db.revisions[article.Slug]) + 1,
+		Title:       article.Title,
+		Description: article.Description,
+		Body:        article.Body,
+		Author:      author,
+		CreatedAt:   at,
+	})
+}
+
+// profile returns the public profile of a user, or just the username if the
+// user doesn't exist. The caller must hold the lock.
+func (db *InMemoryDB) profile(username string) api.Profile {
+	email, exists := db.usernames[username]
+	if !exists {
+		return api.Profile{Username: username}
+	}
+	user := db.users[email]
+	return api.Profile{Username: user.Username, Bio: user.Bio, Image: user.Image}
+}
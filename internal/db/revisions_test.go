@@ -0,0 +1,86 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+func TestRevisions(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+	if err := db.CreateUser(api.User{Username: "author", Email: "author@example.com", Bio: "Writes"}, "password"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	author := api.Profile{Username: "author"}
+	err := db.CreateArticle(api.Article{Title: "First", Description: "Description", Body: "Body", Slug: "first", Author: author, CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	// The article as created is the first revision
+	revisions, err := db.ListRevisions("first")
+	if err != nil {
+		t.Fatalf("Failed to list revisions: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Number != 1 || revisions[0].Title != "First" {
+		t.Fatalf("Expected revision 1 of First, got %+v", revisions)
+	}
+
+	// Every update adds a revision, with the profile of the editor
+	body := "New body"
+	if _, err := db.UpdateArticle("first", api.UpdateArticle{Body: &body}, "author"); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	title := "Second"
+	if _, err := db.UpdateArticle("first", api.UpdateArticle{Title: &title}, "author"); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	revision, err := db.GetRevision("second", 3)
+	if err != nil {
+		t.Fatalf("Failed to get revision: %v", err)
+	}
+	if revision.Title != "Second" || revision.Body != "New body" || revision.Author.Bio != "Writes" {
+		t.Errorf("Expected revision 3 with title, body and editor, got %+v", revision)
+	}
+
+	// Updates without changes add no revision
+	if _, err := db.UpdateArticle("second", api.UpdateArticle{Body: &body}, "author"); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	if revisions, _ := db.ListRevisions("second"); len(revisions) != 3 {
+		t.Errorf("Expected 3 revisions, got %d", len(revisions))
+	}
+
+	// Unknown revisions are not found
+	for _, number := range []int{0, 4} {
+		if _, err := db.GetRevision("second", number); err != ErrNotFound {
+			t.Errorf("Expected ErrNotFound for revision %d, got %v", number, err)
+		}
+	}
+
+	// Restoring adds the old content as a new revision, with its slug
+	restored, err := db.RestoreRevision("second", 1, "author")
+	if err != nil {
+		t.Fatalf("Failed to restore revision: %v", err)
+	}
+	if restored.Slug != "first" || restored.Title != "First" || restored.Body != "Body" {
+		t.Errorf("Expected the content of revision 1, got %+v", restored)
+	}
+	revisions, _ = db.ListRevisions("first")
+	if len(revisions) != 4 || revisions[3].Title != "First" || revisions[0].Body != "Body" {
+		t.Errorf("Expected revision 4 restoring revision 1, got %+v", revisions)
+	}
+	if _, err := db.RestoreRevision("first", 9, "author"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for an unknown revision, got %v", err)
+	}
+
+	// Deleting the article deletes its revisions
+	if err := db.DeleteArticle("first"); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	if _, err := db.ListRevisions("first"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound after deletion, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package db
+
+import "github.com/denga/go-real-world-example/internal/policy"
+
+// SetUserRole changes the role of a user. It returns ErrConflict if that
+// would leave no admin.
+func (db *InMemoryDB) SetUserRole(email string, role policy.Role) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	if internalUser.Role == policy.RoleAdmin && role != policy.RoleAdmin && db.countRole(policy.RoleAdmin) == 1 {
+		return ErrConflict
+	}
+
+	internalUser.Role = role
+	return nil
+}
+
+// countRole counts the users with a role. The caller must hold the lock.
+func (db *InMemoryDB) countRole(role policy.Role) int {
+	count := 0
+	for _, user := range db.users {
+		if user.Role == role {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+func TestSetUserRole(t *testing.T) {
+	// Create a new in-memory database with two users
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "user1", Email: "user1@example.com"}, "password123")
+	db.CreateUser(api.User{Username: "user2", Email: "user2@example.com"}, "password123")
+
+	// New users are regular users
+	user, _ := db.GetInternalUserByEmail("user1@example.com")
+	if user.Role != policy.RoleUser {
+		t.Errorf("Expected role %q, got %q", policy.RoleUser, user.Role)
+	}
+
+	// Promote both users
+	if err := db.SetUserRole("user1@example.com", policy.RoleAdmin); err != nil {
+		t.Fatalf("Failed to set role: %v", err)
+	}
+	if err := db.SetUserRole("user2@example.com", policy.RoleAdmin); err != nil {
+		t.Fatalf("Failed to set role: %v", err)
+	}
+	user, _ = db.GetInternalUserByEmail("user1@example.com")
+	if user.Role != policy.RoleAdmin {
+		t.Errorf("Expected role %q, got %q", policy.RoleAdmin, user.Role)
+	}
+
+	// One admin can step down, the last one can't
+	if err := db.SetUserRole("user1@example.com", policy.RoleModerator); err != nil {
+		t.Fatalf("Failed to set role: %v", err)
+	}
+	if err := db.SetUserRole("user2@example.com", policy.RoleUser); err != ErrConflict {
+		t.Errorf("Expected ErrConflict when removing the last admin, got %v", err)
+	}
+
+	// Unknown users
+	if err := db.SetUserRole("unknown@example.com", policy.RoleAdmin); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
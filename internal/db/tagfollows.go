@@ -0,0 +1,87 @@
+package db
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/util"
+)
+
+// ErrTooManyFollowedTags is returned when a user already follows
+// util.MaxFollowedTags tags
+var ErrTooManyFollowedTags = errors.New("too many followed tags")
+
+// FollowTag makes a user follow a tag. Tags can be followed before any
+// article uses them, up to util.MaxFollowedTags.
+func (db *InMemoryDB) FollowTag(username, tag string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if _, exists := db.usernames[username]; !exists {
+		return ErrNotFound
+	}
+
+	if _, exists := db.tagFollows[username]; !exists {
+		db.tagFollows[username] = make(map[string]bool)
+	}
+	if !db.tagFollows[username][tag] && len(db.tagFollows[username]) >= util.MaxFollowedTags {
+		return ErrTooManyFollowedTags
+	}
+	db.tagFollows[username][tag] = true
+
+	return nil
+}
+
+// UnfollowTag makes a user unfollow a tag
+func (db *InMemoryDB) UnfollowTag(username, tag string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if _, exists := db.usernames[username]; !exists {
+		return ErrNotFound
+	}
+
+	delete(db.tagFollows[username], tag)
+	if len(db.tagFollows[username]) == 0 {
+		delete(db.tagFollows, username)
+	}
+
+	return nil
+}
+
+// IsFollowingTag checks if a user follows a tag
+func (db *InMemoryDB) IsFollowingTag(username, tag string) bool {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	return db.tagFollows[username][tag]
+}
+
+// FollowedTags returns the tags a user follows, sorted
+func (db *InMemoryDB) FollowedTags(username string) []string {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	return db.followedTags(username)
+}
+
+// followedTags implements FollowedTags. The caller must hold the lock.
+func (db *InMemoryDB) followedTags(username string) []string {
+	tags := make([]string, 0, len(db.tagFollows[username]))
+	for tag := range db.tagFollows[username] {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// hasFollowedTag reports whether an article has one of the followed tags
+func hasFollowedTag(article *api.Article, followed map[string]bool) bool {
+	for _, tag := range article.TagList {
+		if followed[tag] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/util"
+)
+
+func TestTagFollows(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+	if err := db.CreateUser(api.User{Username: "reader", Email: "reader@example.com"}, "password"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	// Tags can be followed before any article uses them
+	for _, tag := range []string{"rust", "go"} {
+		if err := db.FollowTag("reader", tag); err != nil {
+			t.Fatalf("Failed to follow tag: %v", err)
+		}
+	}
+	if !db.IsFollowingTag("reader", "go") {
+		t.Errorf("Expected reader to follow go")
+	}
+	if tags := db.FollowedTags("reader"); !slices.Equal(tags, []string{"go", "rust"}) {
+		t.Errorf("Expected followed tags [go rust], got %v", tags)
+	}
+
+	if err := db.UnfollowTag("reader", "rust"); err != nil {
+		t.Fatalf("Failed to unfollow tag: %v", err)
+	}
+	if db.IsFollowingTag("reader", "rust") {
+		t.Errorf("Expected reader to no longer follow rust")
+	}
+
+	// Unknown users can't follow tags
+	if err := db.FollowTag("unknown", "go"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if tags := db.FollowedTags("unknown"); len(tags) != 0 {
+		t.Errorf("Expected no followed tags, got %v", tags)
+	}
+
+	// Deleted users follow nothing
+	if err := db.DeleteUser("reader@example.com"); err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+	if db.IsFollowingTag("reader", "go") {
+		t.Errorf("Expected the followed tags of deleted users to be gone")
+	}
+}
+
+func TestTagFollowsLimit(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+	if err := db.CreateUser(api.User{Username: "reader", Email: "reader@example.com"}, "password"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	for i := 0; i < util.MaxFollowedTags; i++ {
+		if err := db.FollowTag("reader", "tag"+strconv.Itoa(i)); err != nil {
+			t.Fatalf("Failed to follow tag: %v", err)
+		}
+	}
+	if err := db.FollowTag("reader", "one-more"); err != ErrTooManyFollowedTags {
+		t.Errorf("Expected ErrTooManyFollowedTags, got %v", err)
+	}
+
+	// Following a tag again doesn't count
+	if err := db.FollowTag("reader", "tag0"); err != nil {
+		t.Errorf("Expected a followed tag to be followed again, got %v", err)
+	}
+}
+
+func TestTagFollowsMoveWithUsername(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+	if err := db.CreateUser(api.User{Username: "reader", Email: "reader@example.com"}, "password"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := db.FollowTag("reader", "go"); err != nil {
+		t.Fatalf("Failed to follow tag: %v", err)
+	}
+
+	username := "renamed"
+	if _, err := db.UpdateUser("reader@example.com", api.UpdateUser{Username: &username}); err != nil {
+		t.Fatalf("Failed to update user: %v", err)
+	}
+	if !db.IsFollowingTag("renamed", "go") {
+		t.Errorf("Expected the renamed user to still follow go")
+	}
+
+	// The next user with the old name doesn't inherit them
+	if err := db.CreateUser(api.User{Username: "reader", Email: "new@example.com"}, "password"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if tags := db.FollowedTags("reader"); len(tags) != 0 {
+		t.Errorf("Expected the new reader to follow nothing, got %v", tags)
+	}
+}
+
+func TestFeedWithFollowedTags(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+	for _, username := range []string{"reader", "author", "other"} {
+		if err := db.CreateUser(api.User{Username: username, Email: username + "@example.com"}, "password"); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	create := func(slug, author, status string, tags ...string) {
+		err := db.CreateArticle(api.Article{Title: slug, Slug: slug, Author: api.Profile{Username: author}, TagList: tags, Status: status, CreatedAt: time.Now()})
+		if err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+	create("by-author", "author", "")
+	create("by-author-tagged", "author", "", "go")
+	create("tagged", "other", "", "web", "go")
+	create("tagged-draft", "other", StatusDraft, "go")
+	create("unrelated", "other", "", "rust")
+
+	// Without follows the feed is empty
+	if _, count, err := db.GetArticlesFeed("reader", 20, 0); err != nil || count != 0 {
+		t.Errorf("Expected an empty feed, got %d articles and %v", count, err)
+	}
+
+	// Articles of followed authors and with followed tags, each once
+	db.FollowUser("reader", "author")
+	db.FollowTag("reader", "go")
+	db.FollowTag("reader", "web")
+	articles, count, err := db.GetArticlesFeed("reader", 20, 0)
+	if err != nil {
+		t.Fatalf("Failed to get feed: %v", err)
+	}
+	slugs := make([]string, len(articles))
+	for i, article := range articles {
+		slugs[i] = article.Slug
+	}
+	slices.Sort(slugs)
+	if count != 3 || !slices.Equal(slugs, []string{"by-author", "by-author-tagged", "tagged"}) {
+		t.Errorf("Expected by-author, by-author-tagged and tagged, got %d: %v", count, slugs)
+	}
+}
+
+func TestFeedPagination(t *testing.T) {
+	// Create a new in-memory database
+	db := NewInMemoryDB()
+	for _, username := range []string{"reader", "author", "other"} {
+		if err := db.CreateUser(api.User{Username: username, Email: username + "@example.com"}, "password"); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	// Articles of a followed author and with a followed tag, two of each
+	// created at the same time
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		createdAt := start.Add(time.Duration(i) * time.Minute)
+		for _, slug := range []string{"author-" + strconv.Itoa(i) + "a", "author-" + strconv.Itoa(i) + "b"} {
+			db.CreateArticle(api.Article{Title: slug, Slug: slug, Author: api.Profile{Username: "author"}, CreatedAt: createdAt})
+		}
+		for _, slug := range []string{"tagged-" + strconv.Itoa(i) + "a", "tagged-" + strconv.Itoa(i) + "b"} {
+			db.CreateArticle(api.Article{Title: slug, Slug: slug, Author: api.Profile{Username: "other"}, TagList: []string{"go"}, CreatedAt: createdAt})
+		}
+	}
+	db.FollowUser("reader", "author")
+	db.FollowTag("reader", "go")
+
+	// Newest first, articles created at the same time by slug
+	var expected []string
+	for i := 4; i >= 0; i-- {
+		n := strconv.Itoa(i)
+		expected = append(expected, "author-"+n+"a", "author-"+n+"b", "tagged-"+n+"a", "tagged-"+n+"b")
+	}
+
+	// Pages cover every article once, in the same order every time
+	for attempt := 0; attempt < 5; attempt++ {
+		var slugs []string
+		for offset := 0; offset < len(expected); offset += 3 {
+			articles, count, err := db.GetArticlesFeed("reader", 3, offset)
+			if err != nil {
+				t.Fatalf("Failed to get feed: %v", err)
+			}
+			if count != len(expected) {
+				t.Errorf("Expected %d articles, got %d", len(expected), count)
+			}
+			for _, article := range articles {
+				slugs = append(slugs, article.Slug)
+			}
+		}
+		if !slices.Equal(slugs, expected) {
+			t.Fatalf("Expected %v, got %v", expected, slugs)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Purposes of action tokens
+const (
+	// PurposePasswordReset tokens allow setting a new password
+	PurposePasswordReset = "password-reset"
+	// PurposeEmailVerification tokens confirm the email address of an account
+	PurposeEmailVerification = "email-verification"
+)
+
+// ActionToken is a single-use token sent by email to confirm an action
+type ActionToken struct {
+	Hash      string // Hash of the token, the token itself is never stored
+	Email     string
+	Purpose   string
+	ExpiresAt time.Time
+}
+
+// CreateActionToken stores a token. Outstanding tokens of the same purpose
+// for the user are invalidated, so only the most recent link works.
+func (db *InMemoryDB) CreateActionToken(token ActionToken) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if _, exists := db.users[token.Email]; !exists {
+		return ErrNotFound
+	}
+
+	db.deleteActionTokens(token.Email, token.Purpose)
+	db.tokens[token.Hash] = &token
+
+	return nil
+}
+
+// ConsumeActionToken looks up a token by its hash and deletes it. It returns
+// the email of the user the token was issued for, or ErrNotFound if the token
+// doesn't exist, has expired or was issued for another purpose.
+func (db *InMemoryDB) ConsumeActionToken(hash, purpose string, now time.Time) (string, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	token, exists := db.tokens[hash]
+	if !exists || token.Purpose != purpose {
+		return "", ErrNotFound
+	}
+
+	delete(db.tokens, hash)
+	if !now.Before(token.ExpiresAt) {
+		return "", ErrNotFound
+	}
+
+	return token.Email, nil
+}
+
+// deleteActionTokens removes the tokens of a user, limited to one purpose if
+// purpose is not empty. The caller must hold the write lock.
+func (db *InMemoryDB) deleteActionTokens(email, purpose string) {
+	for hash, token := range db.tokens {
+		if token.Email == email && (purpose == "" || token.Purpose == purpose) {
+			delete(db.tokens, hash)
+		}
+	}
+}
+
+// SetPassword replaces the password of a user and invalidates outstanding
+// password reset tokens and session tokens issued before at
+func (db *InMemoryDB) SetPassword(email, password string, at time.Time) error {
+	// Hash outside the lock, bcrypt is slow on purpose
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	internalUser.Password = string(hashedPassword)
+	internalUser.SessionsValidAt = at
+	db.deleteActionTokens(email, PurposePasswordReset)
+
+	return nil
+}
+
+// SetEmailVerified marks the email address of a user as verified
+func (db *InMemoryDB) SetEmailVerified(email string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	internalUser.EmailVerified = true
+	db.deleteActionTokens(email, PurposeEmailVerification)
+
+	return nil
+}
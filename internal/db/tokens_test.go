@@ -0,0 +1,91 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+func TestActionTokens(t *testing.T) {
+	// Create a new in-memory database with a user
+	db := NewInMemoryDB()
+	email := "test@example.com"
+	if err := db.CreateUser(api.User{Username: "testuser", Email: email}, "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Tokens can only be created for existing users
+	if err := db.CreateActionToken(ActionToken{Hash: "h0", Email: "unknown@example.com", Purpose: PurposePasswordReset}); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for unknown user, got %v", err)
+	}
+
+	// A token is consumed once
+	db.CreateActionToken(ActionToken{Hash: "h1", Email: email, Purpose: PurposePasswordReset, ExpiresAt: now.Add(time.Hour)})
+	if got, err := db.ConsumeActionToken("h1", PurposePasswordReset, now); err != nil || got != email {
+		t.Errorf("Expected token for %s, got %s, %v", email, got, err)
+	}
+	if _, err := db.ConsumeActionToken("h1", PurposePasswordReset, now); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for used token, got %v", err)
+	}
+
+	// Tokens are bound to their purpose
+	db.CreateActionToken(ActionToken{Hash: "h2", Email: email, Purpose: PurposeEmailVerification, ExpiresAt: now.Add(time.Hour)})
+	if _, err := db.ConsumeActionToken("h2", PurposePasswordReset, now); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for wrong purpose, got %v", err)
+	}
+
+	// Expired tokens are rejected
+	db.CreateActionToken(ActionToken{Hash: "h3", Email: email, Purpose: PurposePasswordReset, ExpiresAt: now})
+	if _, err := db.ConsumeActionToken("h3", PurposePasswordReset, now); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for expired token, got %v", err)
+	}
+
+	// A new token invalidates older ones of the same purpose
+	db.CreateActionToken(ActionToken{Hash: "h4", Email: email, Purpose: PurposePasswordReset, ExpiresAt: now.Add(time.Hour)})
+	db.CreateActionToken(ActionToken{Hash: "h5", Email: email, Purpose: PurposePasswordReset, ExpiresAt: now.Add(time.Hour)})
+	if _, err := db.ConsumeActionToken("h4", PurposePasswordReset, now); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for superseded token, got %v", err)
+	}
+
+	// Setting the password invalidates outstanding reset tokens and sessions
+	if err := db.SetPassword(email, "newpassword456", now); err != nil {
+		t.Fatalf("Failed to set password: %v", err)
+	}
+	if _, err := db.ConsumeActionToken("h5", PurposePasswordReset, now); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound after password change, got %v", err)
+	}
+	if user, _ := db.GetInternalUserByEmail(email); !user.SessionsValidAt.Equal(now) {
+		t.Errorf("Expected sessions to be valid from %v, got %v", now, user.SessionsValidAt)
+	}
+	if err := db.VerifyUserPassword(email, "newpassword456"); err != nil {
+		t.Errorf("Failed to verify new password: %v", err)
+	}
+}
+
+func TestSetEmailVerified(t *testing.T) {
+	db := NewInMemoryDB()
+	email := "test@example.com"
+	db.CreateUser(api.User{Username: "testuser", Email: email}, "password123")
+
+	if err := db.SetEmailVerified(email); err != nil {
+		t.Fatalf("Failed to verify email: %v", err)
+	}
+	user, _ := db.GetInternalUserByEmail(email)
+	if !user.EmailVerified {
+		t.Error("Expected email to be verified")
+	}
+
+	// Changing the email requires verifying it again
+	newEmail := "new@example.com"
+	db.UpdateUser(email, api.UpdateUser{Email: &newEmail})
+	user, _ = db.GetInternalUserByEmail(newEmail)
+	if user.EmailVerified {
+		t.Error("Expected new email to be unverified")
+	}
+
+	if err := db.SetEmailVerified("unknown@example.com"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for unknown user, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package db
+
+// SetPendingTOTPSecret stores a TOTP secret that becomes active once the
+// user confirms it with a valid code
+func (db *InMemoryDB) SetPendingTOTPSecret(email, secret string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	if internalUser.TwoFactorEnabled() {
+		return ErrConflict
+	}
+
+	internalUser.TOTPPendingSecret = secret
+	return nil
+}
+
+// EnableTOTP activates the pending TOTP secret and replaces the recovery codes
+func (db *InMemoryDB) EnableTOTP(email string, step int64, recoveryCodeHashes []string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists || internalUser.TOTPPendingSecret == "" {
+		return ErrNotFound
+	}
+
+	internalUser.TOTPSecret = internalUser.TOTPPendingSecret
+	internalUser.TOTPPendingSecret = ""
+	internalUser.TOTPLastStep = step
+	internalUser.RecoveryCodes = append([]string(nil), recoveryCodeHashes...)
+	return nil
+}
+
+// DisableTOTP removes the TOTP secret and all recovery codes
+func (db *InMemoryDB) DisableTOTP(email string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	internalUser.TOTPSecret = ""
+	internalUser.TOTPPendingSecret = ""
+	internalUser.TOTPLastStep = 0
+	internalUser.RecoveryCodes = nil
+	return nil
+}
+
+// UseTOTPStep records that a code of the given time step was accepted. It
+// returns ErrConflict if a code of that step or a later one was used before.
+func (db *InMemoryDB) UseTOTPStep(email string, step int64) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	if step <= internalUser.TOTPLastStep {
+		return ErrConflict
+	}
+
+	internalUser.TOTPLastStep = step
+	return nil
+}
+
+// UseRecoveryCode consumes a recovery code by its hash. It returns
+// ErrInvalidCredentials if the code is unknown or was already used.
+func (db *InMemoryDB) UseRecoveryCode(email, codeHash string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	for i, hash := range internalUser.RecoveryCodes {
+		if hash == codeHash {
+			// Build a new slice so copies handed out earlier are unaffected
+			remaining := make([]string, 0, len(internalUser.RecoveryCodes)-1)
+			remaining = append(remaining, internalUser.RecoveryCodes[:i]...)
+			remaining = append(remaining, internalUser.RecoveryCodes[i+1:]...)
+			internalUser.RecoveryCodes = remaining
+			return nil
+		}
+	}
+
+	return ErrInvalidCredentials
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/denga/go-real-world-example/api"
+)
+
+func TestTwoFactorOperations(t *testing.T) {
+	// Create a new in-memory database with a user
+	db := NewInMemoryDB()
+	email := "test@example.com"
+	if err := db.CreateUser(api.User{Username: "testuser", Email: email}, "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	// Enabling without a pending secret fails
+	if err := db.EnableTOTP(email, 1, nil); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound without pending secret, got %v", err)
+	}
+
+	// Enroll and enable
+	if err := db.SetPendingTOTPSecret(email, "SECRET"); err != nil {
+		t.Fatalf("Failed to set pending secret: %v", err)
+	}
+	if err := db.EnableTOTP(email, 10, []string{"hash1", "hash2"}); err != nil {
+		t.Fatalf("Failed to enable TOTP: %v", err)
+	}
+
+	user, _ := db.GetInternalUserByEmail(email)
+	if !user.TwoFactorEnabled() || user.TOTPSecret != "SECRET" || user.TOTPPendingSecret != "" {
+		t.Errorf("Expected active secret after enabling, got %+v", user)
+	}
+
+	// A second enrollment is rejected while enabled
+	if err := db.SetPendingTOTPSecret(email, "OTHER"); err != ErrConflict {
+		t.Errorf("Expected ErrConflict when already enabled, got %v", err)
+	}
+
+	// Time steps can't be reused
+	if err := db.UseTOTPStep(email, 10); err != ErrConflict {
+		t.Errorf("Expected ErrConflict for replayed step, got %v", err)
+	}
+	if err := db.UseTOTPStep(email, 11); err != nil {
+		t.Errorf("Expected later step to be accepted, got %v", err)
+	}
+
+	// Recovery codes are single use
+	if err := db.UseRecoveryCode(email, "hash1"); err != nil {
+		t.Errorf("Expected recovery code to be accepted, got %v", err)
+	}
+	if err := db.UseRecoveryCode(email, "hash1"); err != ErrInvalidCredentials {
+		t.Errorf("Expected ErrInvalidCredentials for used recovery code, got %v", err)
+	}
+	if len(user.RecoveryCodes) != 2 {
+		t.Errorf("Expected earlier copy to keep 2 recovery codes, got %d", len(user.RecoveryCodes))
+	}
+
+	// Disable
+	if err := db.DisableTOTP(email); err != nil {
+		t.Fatalf("Failed to disable TOTP: %v", err)
+	}
+	user, _ = db.GetInternalUserByEmail(email)
+	if user.TwoFactorEnabled() || len(user.RecoveryCodes) != 0 {
+		t.Errorf("Expected 2FA to be cleared, got %+v", user)
+	}
+
+	// Unknown users
+	if err := db.SetPendingTOTPSecret("unknown@example.com", "SECRET"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for unknown user, got %v", err)
+	}
+}
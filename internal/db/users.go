@@ -0,0 +1,182 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// UserFilter selects users in ListUsers
+type UserFilter struct {
+	Search    string // Matches part of the username or email, ignoring case
+	Suspended *bool  // Only suspended or only active users, nil for both
+}
+
+// ListUsers returns users matching the filter ordered by username, and the
+// total number of matches
+func (db *InMemoryDB) ListUsers(filter UserFilter, limit, offset int) ([]InternalUser, int) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	search := strings.ToLower(filter.Search)
+	var users []InternalUser
+	for _, user := range db.users {
+		if search != "" && !strings.Contains(strings.ToLower(user.Username), search) && !strings.Contains(strings.ToLower(user.Email), search) {
+			continue
+		}
+		if filter.Suspended != nil && user.Suspended() != *filter.Suspended {
+			continue
+		}
+		users = append(users, *user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	// Apply pagination
+	totalCount := len(users)
+	if offset >= len(users) {
+		return []InternalUser{}, totalCount
+	}
+
+	end := offset + limit
+	if end > len(users) {
+		end = len(users)
+	}
+
+	return users[offset:end], totalCount
+}
+
+// SuspendUser suspends an account. Suspended users can't sign in and their
+// articles and comments are hidden.
+func (db *InMemoryDB) SuspendUser(email, reason string, at time.Time) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	internalUser.SuspendedAt = &at
+	internalUser.SuspensionReason = reason
+	db.recountTags()
+	return nil
+}
+
+// UnsuspendUser lifts the suspension of an account
+func (db *InMemoryDB) UnsuspendUser(email string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	internalUser.SuspendedAt = nil
+	internalUser.SuspensionReason = ""
+	db.recountTags()
+	return nil
+}
+
+// RevokeCredentials locks a user out until they reset their password: the
+// password stops working, and session tokens issued before at as well as all
+// personal access tokens are revoked
+func (db *InMemoryDB) RevokeCredentials(email string, at time.Time) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+
+	// No password hashes to this value
+	internalUser.Password = "!"
+	internalUser.SessionsValidAt = at
+	db.deletePersonalTokens(email)
+
+	return nil
+}
+
+// DeleteUser deletes a user together with their articles, comments, follows,
+// followed tags, favorites and credentials. It returns ErrConflict for the last admin.
+func (db *InMemoryDB) DeleteUser(email string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	internalUser, exists := db.users[email]
+	if !exists {
+		return ErrNotFound
+	}
+	if internalUser.Role == policy.RoleAdmin && db.countRole(policy.RoleAdmin) == 1 {
+		return ErrConflict
+	}
+	username := internalUser.Username
+
+	// Articles of the user, with their comments, favorites and previous slugs
+	for slug, article := range db.articles {
+		if article.Author.Username == username {
+			db.deleteArticle(slug)
+		}
+	}
+
+	// Comments on other articles
+	for _, comments := range db.comments {
+		for id, comment := range comments {
+			if comment.Author.Username == username {
+				delete(comments, id)
+			}
+		}
+	}
+
+	// Favorites of other articles
+	for slug, favoritedBy := range db.favorites {
+		if favoritedBy[username] {
+			delete(favoritedBy, username)
+			if article, exists := db.articles[slug]; exists {
+				article.FavoritesCount = len(favoritedBy)
+			}
+		}
+	}
+
+	// Follows in both directions
+	delete(db.follows, username)
+	for _, followed := range db.follows {
+		delete(followed, username)
+	}
+	delete(db.tagFollows, username)
+
+	// Credentials
+	db.deleteActionTokens(email, "")
+	db.deletePersonalTokens(email)
+	for key, linked := range db.identities {
+		if linked == email {
+			delete(db.identities, key)
+		}
+	}
+
+	delete(db.usernames, username)
+	delete(db.users, email)
+
+	return nil
+}
+
+// IsSuspended reports whether the user with the given username is suspended
+func (db *InMemoryDB) IsSuspended(username string) bool {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	return db.isSuspended(username)
+}
+
+// isSuspended reports whether the user with the given username is suspended.
+// The caller must hold the lock.
+func (db *InMemoryDB) isSuspended(username string) bool {
+	email, exists := db.usernames[username]
+	return exists && db.users[email].Suspended()
+}
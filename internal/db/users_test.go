@@ -0,0 +1,230 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+func TestListUsers(t *testing.T) {
+	// Create a new in-memory database with some users
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "carol", Email: "carol@example.com"}, "password123")
+	db.CreateUser(api.User{Username: "alice", Email: "alice@example.org"}, "password123")
+	db.CreateUser(api.User{Username: "bob", Email: "bob@example.com"}, "password123")
+	db.SuspendUser("bob@example.com", "spam", time.Now())
+
+	// Users are ordered by username
+	users, count := db.ListUsers(UserFilter{}, 20, 0)
+	if count != 3 || len(users) != 3 {
+		t.Fatalf("Expected 3 users, got %d (%d)", len(users), count)
+	}
+	if users[0].Username != "alice" || users[1].Username != "bob" || users[2].Username != "carol" {
+		t.Errorf("Unexpected order: %s, %s, %s", users[0].Username, users[1].Username, users[2].Username)
+	}
+
+	// Pagination
+	users, count = db.ListUsers(UserFilter{}, 1, 1)
+	if count != 3 || len(users) != 1 || users[0].Username != "bob" {
+		t.Errorf("Expected bob on the second page, got %v (%d)", users, count)
+	}
+	users, _ = db.ListUsers(UserFilter{}, 20, 5)
+	if len(users) != 0 {
+		t.Errorf("Expected empty page, got %d users", len(users))
+	}
+
+	// Search matches usernames and emails, ignoring case
+	users, count = db.ListUsers(UserFilter{Search: "EXAMPLE.COM"}, 20, 0)
+	if count != 2 {
+		t.Errorf("Expected 2 users with example.com addresses, got %d", count)
+	}
+	users, _ = db.ListUsers(UserFilter{Search: "ali"}, 20, 0)
+	if len(users) != 1 || users[0].Username != "alice" {
+		t.Errorf("Expected alice, got %v", users)
+	}
+
+	// Filter by suspension
+	suspended := true
+	users, _ = db.ListUsers(UserFilter{Suspended: &suspended}, 20, 0)
+	if len(users) != 1 || users[0].Username != "bob" || users[0].SuspensionReason != "spam" {
+		t.Errorf("Expected suspended bob, got %v", users)
+	}
+}
+
+func TestSuspendUserHidesContent(t *testing.T) {
+	// Create a new in-memory database with two users
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "author", Email: "author@example.com"}, "password123")
+	db.CreateUser(api.User{Username: "reader", Email: "reader@example.com"}, "password123")
+	db.CreateArticle(api.Article{Slug: "article", Author: api.Profile{Username: "author"}})
+	db.CreateArticle(api.Article{Slug: "other", Author: api.Profile{Username: "reader"}})
+	db.AddComment("other", api.Comment{Body: "Nice", Author: api.Profile{Username: "author"}})
+	db.FollowUser("reader", "author")
+
+	// Suspend the author
+	if err := db.SuspendUser("author@example.com", "spam", time.Now()); err != nil {
+		t.Fatalf("Failed to suspend user: %v", err)
+	}
+	user, _ := db.GetInternalUserByEmail("author@example.com")
+	if !user.Suspended() {
+		t.Error("Expected user to be suspended")
+	}
+	if !db.IsSuspended("author") || db.IsSuspended("reader") || db.IsSuspended("unknown") {
+		t.Error("Expected only author to be suspended")
+	}
+
+	// Their articles and comments are hidden
+	if _, count, _ := db.ListArticles("", "", "", 20, 0); count != 1 {
+		t.Errorf("Expected 1 visible article, got %d", count)
+	}
+	if _, count, _ := db.GetArticlesFeed("reader", 20, 0); count != 0 {
+		t.Errorf("Expected empty feed, got %d articles", count)
+	}
+	if comments, _ := db.GetComments("other"); len(comments) != 0 {
+		t.Errorf("Expected no visible comments, got %d", len(comments))
+	}
+
+	// Unsuspending shows them again
+	if err := db.UnsuspendUser("author@example.com"); err != nil {
+		t.Fatalf("Failed to unsuspend user: %v", err)
+	}
+	if _, count, _ := db.ListArticles("", "", "", 20, 0); count != 2 {
+		t.Errorf("Expected 2 visible articles, got %d", count)
+	}
+	if comments, _ := db.GetComments("other"); len(comments) != 1 {
+		t.Errorf("Expected 1 visible comment, got %d", len(comments))
+	}
+
+	// Unknown users
+	if err := db.SuspendUser("unknown@example.com", "", time.Now()); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRevokeCredentials(t *testing.T) {
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "user1", Email: "user1@example.com"}, "password123")
+	db.CreatePersonalToken(PersonalToken{Hash: "hash-1", Email: "user1@example.com"})
+
+	now := time.Now()
+	if err := db.RevokeCredentials("user1@example.com", now); err != nil {
+		t.Fatalf("Failed to revoke credentials: %v", err)
+	}
+
+	// The password no longer works
+	if err := db.VerifyUserPassword("user1@example.com", "password123"); err != ErrInvalidCredentials {
+		t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+	}
+
+	// Personal tokens are revoked and sessions invalidated
+	if _, err := db.AuthenticatePersonalToken("hash-1", now); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for revoked token, got %v", err)
+	}
+	user, _ := db.GetInternalUserByEmail("user1@example.com")
+	if !user.SessionsValidAt.Equal(now) {
+		t.Errorf("Expected sessions to be valid from %v, got %v", now, user.SessionsValidAt)
+	}
+
+	// A new password works again
+	db.SetPassword("user1@example.com", "new-password", time.Now())
+	if err := db.VerifyUserPassword("user1@example.com", "new-password"); err != nil {
+		t.Errorf("Expected new password to work, got %v", err)
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	// Create a new in-memory database with two users who interact
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "gone", Email: "gone@example.com"}, "password123")
+	db.CreateUser(api.User{Username: "stays", Email: "stays@example.com"}, "password123")
+	db.CreateArticle(api.Article{Slug: "gone-article", Author: api.Profile{Username: "gone"}})
+	db.CreateArticle(api.Article{Slug: "stays-article", Author: api.Profile{Username: "stays"}})
+	db.AddComment("gone-article", api.Comment{Body: "On deleted article", Author: api.Profile{Username: "stays"}})
+	db.AddComment("stays-article", api.Comment{Body: "By deleted user", Author: api.Profile{Username: "gone"}})
+	db.AddComment("stays-article", api.Comment{Body: "By remaining user", Author: api.Profile{Username: "stays"}})
+	db.FavoriteArticle("stays-article", "gone")
+	db.FavoriteArticle("stays-article", "stays")
+	db.FollowUser("gone", "stays")
+	db.FollowUser("stays", "gone")
+	db.CreatePersonalToken(PersonalToken{Hash: "hash-1", Email: "gone@example.com"})
+	db.LinkIdentity("company", "sub-1", "gone@example.com")
+
+	if err := db.DeleteUser("gone@example.com"); err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+
+	// The user is gone
+	if _, err := db.GetUserByEmail("gone@example.com"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for email, got %v", err)
+	}
+	if _, err := db.GetUserByUsername("gone"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for username, got %v", err)
+	}
+
+	// Their article is gone, the other one lost their comment and favorite
+	if _, err := db.GetArticle("gone-article"); err != ErrNotFound {
+		t.Errorf("Expected article to be deleted, got %v", err)
+	}
+	comments, _ := db.GetComments("stays-article")
+	if len(comments) != 1 || comments[0].Author.Username != "stays" {
+		t.Errorf("Expected only the remaining user's comment, got %v", comments)
+	}
+	if db.IsFavorite("stays-article", "gone") {
+		t.Error("Expected favorite to be removed")
+	}
+	article, _ := db.GetArticle("stays-article")
+	if article.FavoritesCount != 1 {
+		t.Errorf("Expected favorites count 1, got %d", article.FavoritesCount)
+	}
+
+	// Follows in both directions are gone
+	if db.IsFollowing("stays", "gone") || db.IsFollowing("gone", "stays") {
+		t.Error("Expected follows to be removed")
+	}
+
+	// Credentials are gone
+	if _, err := db.AuthenticatePersonalToken("hash-1", time.Now()); err != ErrNotFound {
+		t.Errorf("Expected personal token to be deleted, got %v", err)
+	}
+	if _, err := db.GetEmailByIdentity("company", "sub-1"); err != ErrNotFound {
+		t.Errorf("Expected identity link to be deleted, got %v", err)
+	}
+
+	// The username and email can be used again
+	if err := db.CreateUser(api.User{Username: "gone", Email: "gone@example.com"}, "password123"); err != nil {
+		t.Errorf("Expected username and email to be free, got %v", err)
+	}
+}
+
+func TestDeleteLastAdmin(t *testing.T) {
+	db := NewInMemoryDB()
+	db.CreateUser(api.User{Username: "admin", Email: "admin@example.com"}, "password123")
+	db.SetUserRole("admin@example.com", policy.RoleAdmin)
+
+	if err := db.DeleteUser("admin@example.com"); err != ErrConflict {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+	if err := db.DeleteUser("unknown@example.com"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCommentIDsAreNotReused(t *testing.T) {
+	db := NewInMemoryDB()
+	db.CreateArticle(api.Article{Slug: "article"})
+
+	first, _ := db.AddComment("article", api.Comment{Body: "First"})
+	second, _ := db.AddComment("article", api.Comment{Body: "Second"})
+	db.DeleteComment("article", first)
+
+	// A new comment must not overwrite the second one
+	third, _ := db.AddComment("article", api.Comment{Body: "Third"})
+	if third == second || third == first {
+		t.Errorf("Expected a new comment ID, got %d", third)
+	}
+	if comment, _ := db.GetComment("article", second); comment == nil || comment.Body != "Second" {
+		t.Errorf("Expected second comment to be kept, got %v", comment)
+	}
+}
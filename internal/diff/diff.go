@@ -0,0 +1,249 @@
+// Package diff compares texts line by line.
+package diff
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxLines is the maximum number of lines of each text Lines compares. The
+// time to compare grows with the number of lines times the number of changes.
+const MaxLines = 10000
+
+// ErrTooLarge is returned for texts with more than MaxLines lines
+var ErrTooLarge = errors.New("text too large to compare")
+
+// Op is what happens to a line between two texts
+type Op string
+
+// Ops of lines in a diff
+const (
+	Equal  Op = "equal"  // The line is in both texts
+	Insert Op = "insert" // The line is only in the new text
+	Delete Op = "delete" // The line is only in the old text
+)
+
+// Line is a line of a diff
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns a shortest line diff turning the text a into b, computed
+// with the linear space variant of the Myers algorithm. Within a change,
+// deleted lines come before inserted ones. A trailing newline doesn't count
+// as an extra empty line.
+func Lines(a, b string) ([]Line, error) {
+	aLines, bLines := splitLines(a), splitLines(b)
+	if len(aLines) > MaxLines || len(bLines) > MaxLines {
+		return nil, ErrTooLarge
+	}
+
+	// Compare numbers instead of strings
+	ids := make(map[string]int)
+	d := &differ{a: intern(aLines, ids), b: intern(bLines, ids)}
+	path := d.path(0, 0, len(aLines), len(bLines))
+
+	lines := make([]Line, 0, len(aLines)+len(bLines))
+	emit := func(op Op, x, y int) {
+		if op == Insert {
+			lines = append(lines, Line{Op: op, Text: bLines[y]})
+		} else {
+			lines = append(lines, Line{Op: op, Text: aLines[x]})
+		}
+	}
+	for i := 1; i < len(path); i++ {
+		d.walk(path[i-1], path[i], emit)
+	}
+	return deletesFirst(lines), nil
+}
+
+// splitLines splits a text into lines
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// intern replaces lines by numbers, the same for equal lines
+func intern(lines []string, ids map[string]int) []int {
+	interned := make([]int, len(lines))
+	for i, line := range lines {
+		id, exists := ids[line]
+		if !exists {
+			id = len(ids)
+			ids[line] = id
+		}
+		interned[i] = id
+	}
+	return interned
+}
+
+// point is a position in the edit graph, after x lines of a and y lines of b
+type point struct{ x, y int }
+
+// differ finds shortest edit scripts between a and b
+type differ struct {
+	a, b []int
+}
+
+// path returns the points of a shortest path through the edit graph from
+// (left, top) to (right, bottom). It splits the box at the middle snake of
+// the path and recurses into both halves, so that only linear space is
+// needed. Consecutive points are at most one edit and a diagonal apart.
+func (d *differ) path(left, top, right, bottom int) []point {
+	start, finish, ok := d.midpoint(left, top, right, bottom)
+	if !ok {
+		return nil
+	}
+
+	head := d.path(left, top, start.x, start.y)
+	tail := d.path(finish.x, finish.y, right, bottom)
+	if head == nil {
+		head = []point{start}
+	}
+	if tail == nil {
+		tail = []point{finish}
+	}
+	return append(head, tail...)
+}
+
+// midpoint finds the middle snake of a shortest path through the box by
+// searching forwards from the top left and backwards from the bottom right
+// until the searches overlap. It returns false for empty boxes.
+func (d *differ) midpoint(left, top, right, bottom int) (point, point, bool) {
+	width, height := right-left, bottom-top
+	if width+height == 0 {
+		return point{}, point{}, false
+	}
+
+	limit := (width + height + 1) / 2
+	offset := limit + 1
+	forward := make([]int, 2*offset+1)  // furthest x on diagonal k = x - y
+	backward := make([]int, 2*offset+1) // furthest y on diagonal c = k - delta
+	forward[offset+1] = left
+	backward[offset+1] = bottom
+	delta := width - height
+
+	for steps := 0; steps <= limit; steps++ {
+		// Forwards, taking a step on every other diagonal
+		for k := steps; k >= -steps; k -= 2 {
+			var x, px int
+			if k == -steps || (k != steps && forward[offset+k-1] < forward[offset+k+1]) {
+				// Move down from diagonal k+1
+				px = forward[offset+k+1]
+				x = px
+			} else {
+				// Move right from diagonal k-1
+				px = forward[offset+k-1]
+				x = px + 1
+			}
+			y := top + (x - left) - k
+			py := y
+			if steps > 0 && x == px {
+				py = y - 1
+			}
+
+			// Follow equal lines
+			for x < right && y < bottom && d.a[x] == d.b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			c := k - delta
+			if delta%2 != 0 && c >= -(steps-1) && c <= steps-1 && y >= backward[offset+c] {
+				return point{px, py}, point{x, y}, true
+			}
+		}
+
+		// Backwards, the same from the bottom right
+		for c := steps; c >= -steps; c -= 2 {
+			var y, py int
+			if c == -steps || (c != steps && backward[offset+c-1] > backward[offset+c+1]) {
+				// Move up from diagonal c+1
+				py = backward[offset+c+1]
+				y = py
+			} else {
+				// Move left from diagonal c-1
+				py = backward[offset+c-1]
+				y = py - 1
+			}
+			k := c + delta
+			x := left + (y - top) + k
+			px := x
+			if steps > 0 && y == py {
+				px = x + 1
+			}
+
+			// Follow equal lines
+			for x > left && y > top && d.a[x-1] == d.b[y-1] {
+				x--
+				y--
+			}
+			backward[offset+c] = y
+
+			if delta%2 == 0 && k >= -steps && k <= steps && x <= forward[offset+k] {
+				return point{x, y}, point{px, py}, true
+			}
+		}
+	}
+
+	// A path of at most limit steps from each side always exists
+	panic("diff: no middle snake")
+}
+
+// walk emits the lines between two consecutive points of a path: equal
+// lines, at most one edit, and equal lines again
+func (d *differ) walk(from, to point, emit func(op Op, x, y int)) {
+	x, y := d.diagonal(from, to, emit)
+	switch {
+	case to.x-x < to.y-y:
+		emit(Insert, x, y)
+		y++
+	case to.x-x > to.y-y:
+		emit(Delete, x, y)
+		x++
+	}
+	d.diagonal(point{x, y}, to, emit)
+}
+
+// diagonal emits equal lines from a point towards another one and returns
+// where it stopped
+func (d *differ) diagonal(from, to point, emit func(op Op, x, y int)) (int, int) {
+	x, y := from.x, from.y
+	for x < to.x && y < to.y && d.a[x] == d.b[y] {
+		emit(Equal, x, y)
+		x++
+		y++
+	}
+	return x, y
+}
+
+// deletesFirst moves the deleted lines of every change before its inserted
+// lines, which keeps the diff valid and shortest
+func deletesFirst(lines []Line) []Line {
+	for start := 0; start < len(lines); {
+		if lines[start].Op == Equal {
+			start++
+			continue
+		}
+		end := start
+		for end < len(lines) && lines[end].Op != Equal {
+			end++
+		}
+
+		change := make([]Line, 0, end-start)
+		for _, op := range []Op{Delete, Insert} {
+			for _, line := range lines[start:end] {
+				if line.Op == op {
+					change = append(change, line)
+				}
+			}
+		}
+		copy(lines[start:end], change)
+		start = end
+	}
+	return lines
+}
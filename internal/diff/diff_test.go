@@ -0,0 +1,185 @@
+package diff
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected []Line
+	}{
+		{
+			name:     "Equal",
+			a:        "one\ntwo\n",
+			b:        "one\ntwo",
+			expected: []Line{{Equal, "one"}, {Equal, "two"}},
+		},
+		{
+			name:     "Both empty",
+			a:        "",
+			b:        "",
+			expected: []Line{},
+		},
+		{
+			name:     "From empty",
+			a:        "",
+			b:        "one\ntwo",
+			expected: []Line{{Insert, "one"}, {Insert, "two"}},
+		},
+		{
+			name:     "To empty",
+			a:        "one",
+			b:        "",
+			expected: []Line{{Delete, "one"}},
+		},
+		{
+			name:     "Changed line",
+			a:        "one\ntwo\nthree",
+			b:        "one\n2\nthree",
+			expected: []Line{{Equal, "one"}, {Delete, "two"}, {Insert, "2"}, {Equal, "three"}},
+		},
+		{
+			name:     "Inserted and deleted lines",
+			a:        "a\nb\nc\nd",
+			b:        "b\nc\nx\nd\ne",
+			expected: []Line{{Delete, "a"}, {Equal, "b"}, {Equal, "c"}, {Insert, "x"}, {Equal, "d"}, {Insert, "e"}},
+		},
+	}
+
+	for _, tt := range tests {
+		if lines, err := Lines(tt.a, tt.b); err != nil || !reflect.DeepEqual(lines, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, lines)
+		}
+	}
+}
+
+func TestLinesIsShortest(t *testing.T) {
+	// The classic example of the Myers paper has 5 edits
+	a := strings.Join(strings.Split("ABCABBA", ""), "\n")
+	b := strings.Join(strings.Split("CBABAC", ""), "\n")
+
+	lines, _ := Lines(a, b)
+	if edits := countEdits(t, a, b, lines); edits != 5 {
+		t.Errorf("Expected 5 edits, got %d: %v", edits, lines)
+	}
+}
+
+// countEdits checks that the diff rebuilds both texts and returns its number of edits
+func countEdits(t *testing.T, a, b string, lines []Line) int {
+	t.Helper()
+
+	edits := 0
+	var oldLines, newLines []string
+	for _, line := range lines {
+		switch line.Op {
+		case Equal:
+			oldLines = append(oldLines, line.Text)
+			newLines = append(newLines, line.Text)
+		case Delete:
+			oldLines = append(oldLines, line.Text)
+			edits++
+		case Insert:
+			newLines = append(newLines, line.Text)
+			edits++
+		}
+	}
+
+	if strings.Join(oldLines, "\n") != a || strings.Join(newLines, "\n") != b {
+		t.Errorf("Expected the diff to rebuild both texts, got %v", lines)
+	}
+	return edits
+}
+
+// lcsLength returns the length of the longest common subsequence of two
+// lists of lines, the slow way
+func lcsLength(a, b []string) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	return lengths[0][0]
+}
+
+func TestLinesIsShortestRandom(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+	randomText := func() []string {
+		lines := make([]string, random.IntN(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + random.IntN(3)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		aLines, bLines := randomText(), randomText()
+		a, b := strings.Join(aLines, "\n"), strings.Join(bLines, "\n")
+		lines, err := Lines(a, b)
+		if err != nil {
+			t.Fatalf("Failed to compare: %v", err)
+		}
+
+		// A shortest diff keeps a longest common subsequence
+		expected := len(aLines) + len(bLines) - 2*lcsLength(aLines, bLines)
+		if edits := countEdits(t, a, b, lines); edits != expected {
+			t.Fatalf("%q to %q: expected %d edits, got %d: %v", a, b, expected, edits, lines)
+		}
+	}
+}
+
+func TestLinesLargeInput(t *testing.T) {
+	// Texts without a common line need the most edits
+	aLines := make([]string, 5000)
+	bLines := make([]string, 5000)
+	for i := range aLines {
+		aLines[i] = "old " + strconv.Itoa(i)
+		bLines[i] = "new " + strconv.Itoa(i)
+	}
+	a, b := strings.Join(aLines, "\n"), strings.Join(bLines, "\n")
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	lines, err := Lines(a, b)
+	runtime.ReadMemStats(&after)
+
+	if err != nil {
+		t.Fatalf("Failed to compare: %v", err)
+	}
+	if edits := countEdits(t, a, b, lines); edits != 10000 {
+		t.Errorf("Expected 10000 edits, got %d", edits)
+	}
+
+	// Memory grows linearly, not with the square of the changes
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("Expected less than 64 MB allocated, got %d MB", allocated>>20)
+	}
+}
+
+func TestLinesTooLarge(t *testing.T) {
+	large := strings.Repeat("line\n", MaxLines+1)
+	if _, err := Lines(large, "line"); err != ErrTooLarge {
+		t.Errorf("Expected ErrTooLarge, got %v", err)
+	}
+	if _, err := Lines("line", large); err != ErrTooLarge {
+		t.Errorf("Expected ErrTooLarge, got %v", err)
+	}
+	if _, err := Lines(strings.Repeat("line\n", MaxLines), ""); err != nil {
+		t.Errorf("Expected %d lines to be compared, got %v", MaxLines, err)
+	}
+}
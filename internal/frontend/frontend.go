@@ -0,0 +1,330 @@
+// Package frontend serves the static export of the Next.js frontend.
+package frontend
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// notFoundPage is the page Next.js exports for unknown routes
+const notFoundPage = "404.html"
+
+// staticPrefix is where Next.js puts build output with content hashes in the
+// file names, which never changes under the same URL
+const staticPrefix = "_next/static/"
+
+// Cache-Control values of hashed assets and of everything else, which
+// browsers revalidate with the ETag
+const (
+	immutableCacheControl  = "public, max-age=31536000, immutable"
+	revalidateCacheControl = "no-cache"
+)
+
+// encodings are the content codings of precompressed variants, with their
+// file suffixes, in order of preference
+var encodings = []struct {
+	name   string
+	suffix string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// RedirectFunc returns the URL path a page permanently moved to, like the
+// page of a renamed article, or false if it didn't move
+type RedirectFunc func(urlPath string) (string, bool)
+
+// Options are the optional hooks of the frontend handler
+type Options struct {
+	// Meta returns the meta tags of pages
+	Meta MetaFunc
+	// Redirect returns where pages moved to
+	Redirect RedirectFunc
+}
+
+// handler serves the files of the frontend
+type handler struct {
+	root     fs.FS
+	meta     MetaFunc
+	redirect RedirectFunc
+
+	// etags caches the ETags of files, which never change in an embedded FS
+	etags sync.Map
+}
+
+// Handler serves the files of the frontend. Page URLs are resolved like the
+// Next.js static export lays them out: /login is served from login.html or
+// login/index.html. Dynamic routes only have pages for the parameters known
+// at build time, so other parameters get a page of the same route, which
+// loads its data from the API in the browser. Everything else gets the 404
+// page. Paths under /api are never served from the frontend.
+//
+// Files with precompressed .br or .gz variants next to them are served
+// compressed to clients accepting it. Hashed assets under /_next/static are
+// cached forever, everything else is revalidated with ETags.
+//
+// Pages for which options.Meta returns meta tags are served with those
+// instead of the exported ones, so that link previews show the content.
+// Pages for which options.Redirect returns a path are permanently
+// redirected there. Both hooks are optional.
+func Handler(root fs.FS, options Options) http.Handler {
+	return &handler{root: root, meta: options.Meta, redirect: options.Redirect}
+}
+
+// ServeHTTP implements http.Handler
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api" || strings.HasPrefix(r.URL.Path, "/api/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	if h.redirect != nil {
+		if target, ok := h.redirect(strings.TrimSuffix(path.Clean(r.URL.Path), "/")); ok {
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return
+		}
+	}
+
+	name, ok := resolve(h.root, r.URL.Path)
+	if !ok {
+		h.serveNotFound(w, r)
+		return
+	}
+	h.serveFile(w, r, name)
+}
+
+// resolve returns the file serving a URL path
+func resolve(root fs.FS, urlPath string) (string, bool) {
+	p := strings.Trim(path.Clean("/"+urlPath), "/")
+	if p == "" {
+		p = "index"
+	}
+
+	for _, name := range []string{p, p + ".html", p + "/index.html"} {
+		if isFile(root, name) {
+			return name, true
+		}
+	}
+	if strings.HasPrefix(p, "_next/") || mime.TypeByExtension(path.Ext(p)) != "" {
+		// Missing assets don't get pages
+		return "", false
+	}
+	return dynamicPage(root, p)
+}
+
+// dynamicPage finds a page of a dynamic route matching p, like
+// article/some-slug.html for article/other-slug. One segment, the last
+// possible one, is treated as the parameter. The first segment never is, so
+// that unknown top-level pages don't get some other top-level page, and the
+// 404 page is never used for a route.
+func dynamicPage(root fs.FS, p string) (string, bool) {
+	segments := strings.Split(p, "/")
+	for i := len(segments) - 1; i >= 1; i-- {
+		pattern := make([]string, len(segments))
+		for j, segment := range segments {
+			pattern[j] = escapeGlob(segment)
+		}
+		pattern[i] = "*"
+
+		for _, candidate := range []string{strings.Join(pattern, "/") + ".html", strings.Join(pattern, "/") + "/index.html"} {
+			matches, err := fs.Glob(root, candidate)
+			if err != nil {
+				continue
+			}
+			// Glob returns sorted matches, so the choice is stable
+			for _, match := range matches {
+				if match != notFoundPage {
+					return match, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// escapeGlob escapes the special characters of fs.Glob patterns
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`\*?[`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isFile reports whether name is a regular file in root
+func isFile(root fs.FS, name string) bool {
+	info, err := fs.Stat(root, name)
+	return err == nil && !info.IsDir()
+}
+
+// serveNotFound serves the 404 page, or a plain 404 without one
+func (h *handler) serveNotFound(w http.ResponseWriter, r *http.Request) {
+	content, err := fs.ReadFile(h.root, notFoundPage)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", revalidateCacheControl)
+	w.WriteHeader(http.StatusNotFound)
+	if r.Method != http.MethodHead {
+		w.Write(content)
+	}
+}
+
+// serveFile writes a file of the frontend, or its best precompressed variant
+func (h *handler) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	if h.meta != nil && path.Ext(name) == ".html" {
+		if meta, ok := h.meta(strings.TrimSuffix(path.Clean(r.URL.Path), "/")); ok {
+			h.servePage(w, r, name, meta)
+			return
+		}
+	}
+
+	servedName := name
+	for _, encoding := range encodings {
+		if !isFile(h.root, name+encoding.suffix) {
+			continue
+		}
+		// Caches have to keep the variants apart
+		w.Header().Set("Vary", "Accept-Encoding")
+		if servedName == name && acceptsEncoding(r.Header.Get("Accept-Encoding"), encoding.name) {
+			servedName = name + encoding.suffix
+			w.Header().Set("Content-Encoding", encoding.name)
+		}
+	}
+
+	content, err := fs.ReadFile(h.root, servedName)
+	if err != nil {
+		http.Error(w, "Could not read file", http.StatusInternalServerError)
+		return
+	}
+
+	// Set the MIME type of the uncompressed file
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	w.Header().Set("Content-Type", contentType)
+
+	if strings.HasPrefix(name, staticPrefix) {
+		w.Header().Set("Cache-Control", immutableCacheControl)
+	} else {
+		w.Header().Set("Cache-Control", revalidateCacheControl)
+	}
+	w.Header().Set("ETag", h.etag(servedName, content))
+
+	// ServeContent answers If-None-Match and range requests
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
+}
+
+// servePage writes an HTML page with the given meta tags. It is never
+// compressed, since the precompressed variants have the exported tags.
+func (h *handler) servePage(w http.ResponseWriter, r *http.Request, name string, meta Meta) {
+	content, err := fs.ReadFile(h.root, name)
+	if err != nil {
+		http.Error(w, "Could not read file", http.StatusInternalServerError)
+		return
+	}
+	content = injectMeta(content, meta)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", revalidateCacheControl)
+	w.Header().Set("ETag", contentETag(content))
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
+}
+
+// etag returns the ETag of a file, which is cached
+func (h *handler) etag(name string, content []byte) string {
+	if etag, ok := h.etags.Load(name); ok {
+		return etag.(string)
+	}
+	etag := contentETag(content)
+	h.etags.Store(name, etag)
+	return etag
+}
+
+// contentETag returns a strong ETag, a hash of the content
+func contentETag(content []byte) string {
+	sum := sha256.Sum256(content)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows a
+// content coding, which it doesn't if it is missing or has a q-value of 0
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), encoding) {
+			continue
+		}
+
+		params = strings.ReplaceAll(params, " ", "")
+		if q, ok := strings.CutPrefix(params, "q="); ok {
+			value, err := strconv.ParseFloat(q, 64)
+			return err == nil && value > 0
+		}
+		return true
+	}
+	return false
+}
+
+// scriptPattern matches script elements with their attributes and content
+var scriptPattern = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script>`)
+
+// srcPattern matches a src attribute
+var srcPattern = regexp.MustCompile(`(?i)(^|\s)src\s*=`)
+
+// InlineScriptHashes returns the CSP hash sources, like 'sha256-...', of the
+// inline scripts in the HTML files of the frontend. Next.js bootstraps its
+// static pages with inline scripts, which a Content-Security-Policy has to
+// allow by hash.
+func InlineScriptHashes(root fs.FS) ([]string, error) {
+	seen := map[string]bool{}
+	err := fs.WalkDir(root, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(name, ".html") {
+			return err
+		}
+
+		content, err := fs.ReadFile(root, name)
+		if err != nil {
+			return err
+		}
+		for _, match := range scriptPattern.FindAllSubmatch(content, -1) {
+			if srcPattern.Match(match[1]) {
+				continue
+			}
+			sum := sha256.Sum256(match[2])
+			seen["'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'"] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(seen))
+	for hash := range seen {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	return hashes, nil
+}
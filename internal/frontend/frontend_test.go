@@ -0,0 +1,327 @@
+package frontend
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// hashSource returns the CSP hash source of a script
+func hashSource(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+func TestHandlerContentTypes(t *testing.T) {
+	root := fstest.MapFS{
+		"index.html":        {Data: []byte("<html></html>")},
+		"_next/app.js":      {Data: []byte("console.log(1)")},
+		"_next/app.css":     {Data: []byte("body{}")},
+		"fonts/inter.woff2": {Data: []byte("woff2")},
+	}
+	handler := Handler(root, Options{})
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/", "text/html; charset=utf-8"},
+		{"/_next/app.js", "text/javascript; charset=utf-8"},
+		{"/_next/app.css", "text/css; charset=utf-8"},
+		{"/fonts/inter.woff2", "font/woff2"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: expected status %d, got %d", tt.path, http.StatusOK, rr.Code)
+		}
+		if contentType := rr.Header().Get("Content-Type"); contentType != tt.expected {
+			t.Errorf("%s: expected Content-Type %s, got %s", tt.path, tt.expected, contentType)
+		}
+	}
+}
+
+// exportFS returns a static export with the layout of the frontend
+func exportFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":                         {Data: []byte("home")},
+		"login.html":                         {Data: []byte("login")},
+		"404.html":                           {Data: []byte("not found")},
+		"settings/index.html":                {Data: []byte("settings")},
+		"article/getting-started.html":       {Data: []byte("article getting-started")},
+		"article/react-hooks.html":           {Data: []byte("article react-hooks")},
+		"profile/johndoe.html":               {Data: []byte("profile johndoe")},
+		"profile/johndoe/favorites.html":     {Data: []byte("favorites johndoe")},
+		"_next/static/chunks/main-abc123.js": {Data: []byte("main")},
+		"_next/static/css/app.css":           {Data: []byte("body{}")},
+	}
+}
+
+func TestHandlerRoutes(t *testing.T) {
+	handler := Handler(exportFS(), Options{})
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"/", http.StatusOK, "home"},
+		{"/index.html", http.StatusOK, "home"},
+		{"/login", http.StatusOK, "login"},
+		{"/login/", http.StatusOK, "login"},
+		{"/settings", http.StatusOK, "settings"},
+		{"/article/react-hooks", http.StatusOK, "article react-hooks"},
+		// Unknown parameters of dynamic routes get a page of the route
+		{"/article/some-new-slug", http.StatusOK, "article getting-started"},
+		{"/profile/alice", http.StatusOK, "profile johndoe"},
+		{"/profile/john.doe", http.StatusOK, "profile johndoe"},
+		{"/profile/alice/favorites", http.StatusOK, "favorites johndoe"},
+		{"/profile/[alice]*", http.StatusOK, "profile johndoe"},
+		{"/_next/static/chunks/main-abc123.js", http.StatusOK, "main"},
+		// Unknown pages and assets get the 404 page
+		{"/does/not/exist", http.StatusNotFound, "not found"},
+		{"/unknown", http.StatusNotFound, "not found"},
+		{"/typo-page", http.StatusNotFound, "not found"},
+		{"/unknown/", http.StatusNotFound, "not found"},
+		{"/_next/static/chunks/missing.js", http.StatusNotFound, "not found"},
+		{"/favicon.svg", http.StatusNotFound, "not found"},
+		// The API's 404s are never replaced by pages
+		{"/api", http.StatusNotFound, "404 page not found\n"},
+		{"/api/unknown", http.StatusNotFound, "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.expectedStatus, rr.Code)
+		}
+		if rr.Body.String() != tt.expectedBody {
+			t.Errorf("%s: expected body %q, got %q", tt.path, tt.expectedBody, rr.Body.String())
+		}
+	}
+}
+
+func TestHandlerWithoutNotFoundPage(t *testing.T) {
+	root := exportFS()
+	delete(root, "404.html")
+	handler := Handler(root, Options{})
+
+	req := httptest.NewRequest("GET", "/does/not/exist", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestHandlerRedirect(t *testing.T) {
+	handler := Handler(exportFS(), Options{Redirect: func(urlPath string) (string, bool) {
+		if urlPath == "/article/old-slug" {
+			return "/article/new-slug", true
+		}
+		return "", false
+	}})
+
+	tests := []struct {
+		path             string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{"/article/old-slug", http.StatusMovedPermanently, "/article/new-slug"},
+		{"/article/old-slug/", http.StatusMovedPermanently, "/article/new-slug"},
+		{"/article/old-slug?tab=comments", http.StatusMovedPermanently, "/article/new-slug?tab=comments"},
+		{"/article/new-slug", http.StatusOK, ""},
+		{"/api/articles/old-slug", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.expectedStatus, rr.Code)
+		}
+		if location := rr.Header().Get("Location"); location != tt.expectedLocation {
+			t.Errorf("%s: expected Location %q, got %q", tt.path, tt.expectedLocation, location)
+		}
+	}
+}
+
+func TestHandlerPrecompressed(t *testing.T) {
+	root := fstest.MapFS{
+		"_next/static/chunks/main-abc123.js":    {Data: []byte("plain")},
+		"_next/static/chunks/main-abc123.js.br": {Data: []byte("brotli")},
+		"_next/static/chunks/main-abc123.js.gz": {Data: []byte("gzip")},
+		"_next/static/css/app.css":              {Data: []byte("plain css")},
+		"_next/static/css/app.css.gz":           {Data: []byte("gzip css")},
+		"favicon.ico":                           {Data: []byte("icon")},
+	}
+	handler := Handler(root, Options{})
+
+	tests := []struct {
+		path             string
+		acceptEncoding   string
+		expectedBody     string
+		expectedEncoding string
+		expectedVary     string
+	}{
+		{"/_next/static/chunks/main-abc123.js", "gzip, deflate, br", "brotli", "br", "Accept-Encoding"},
+		{"/_next/static/chunks/main-abc123.js", "gzip", "gzip", "gzip", "Accept-Encoding"},
+		{"/_next/static/chunks/main-abc123.js", "br;q=0, gzip;q=0.5", "gzip", "gzip", "Accept-Encoding"},
+		{"/_next/static/chunks/main-abc123.js", "", "plain", "", "Accept-Encoding"},
+		{"/_next/static/css/app.css", "br", "plain css", "", "Accept-Encoding"},
+		{"/_next/static/css/app.css", "br, gzip", "gzip css", "gzip", "Accept-Encoding"},
+		{"/favicon.ico", "br, gzip", "icon", "", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Body.String() != tt.expectedBody {
+			t.Errorf("%s with %q: expected body %q, got %q", tt.path, tt.acceptEncoding, tt.expectedBody, rr.Body.String())
+		}
+		if encoding := rr.Header().Get("Content-Encoding"); encoding != tt.expectedEncoding {
+			t.Errorf("%s with %q: expected Content-Encoding %q, got %q", tt.path, tt.acceptEncoding, tt.expectedEncoding, encoding)
+		}
+		if vary := rr.Header().Get("Vary"); vary != tt.expectedVary {
+			t.Errorf("%s with %q: expected Vary %q, got %q", tt.path, tt.acceptEncoding, tt.expectedVary, vary)
+		}
+	}
+
+	// The type is the one of the uncompressed file
+	req := httptest.NewRequest("GET", "/_next/static/chunks/main-abc123.js", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if contentType := rr.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/javascript") {
+		t.Errorf("Expected JavaScript content type, got %s", contentType)
+	}
+}
+
+func TestHandlerCaching(t *testing.T) {
+	handler := Handler(exportFS(), Options{})
+
+	tests := []struct {
+		path         string
+		cacheControl string
+	}{
+		{"/_next/static/chunks/main-abc123.js", "public, max-age=31536000, immutable"},
+		{"/", "no-cache"},
+		{"/article/some-new-slug", "no-cache"},
+		{"/does/not/exist", "no-cache"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if cacheControl := rr.Header().Get("Cache-Control"); cacheControl != tt.cacheControl {
+			t.Errorf("%s: expected Cache-Control %q, got %q", tt.path, tt.cacheControl, cacheControl)
+		}
+	}
+}
+
+func TestHandlerETag(t *testing.T) {
+	handler := Handler(exportFS(), Options{})
+
+	req := httptest.NewRequest("GET", "/login", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	etag := rr.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) || len(etag) < 3 {
+		t.Fatalf("Expected a strong ETag, got %q", etag)
+	}
+
+	// A matching ETag gets 304 without a body
+	req = httptest.NewRequest("GET", "/login", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotModified {
+		t.Errorf("Expected status %d, got %d", http.StatusNotModified, rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("Expected no body, got %q", rr.Body.String())
+	}
+
+	// Another file has another ETag
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr.Header().Get("ETag") == etag {
+		t.Error("Expected different ETags for different files")
+	}
+}
+
+func TestAcceptsEncoding(t *testing.T) {
+	tests := []struct {
+		header   string
+		encoding string
+		expected bool
+	}{
+		{"gzip, deflate, br", "br", true},
+		{"gzip, deflate", "br", false},
+		{"GZIP", "gzip", true},
+		{"br;q=0", "br", false},
+		{"br; q=0.8", "br", true},
+		{"br;q=invalid", "br", false},
+		{"", "gzip", false},
+	}
+
+	for _, tt := range tests {
+		if got := acceptsEncoding(tt.header, tt.encoding); got != tt.expected {
+			t.Errorf("acceptsEncoding(%q, %q): expected %v, got %v", tt.header, tt.encoding, tt.expected, got)
+		}
+	}
+}
+
+func TestInlineScriptHashes(t *testing.T) {
+	root := fstest.MapFS{
+		"index.html": {Data: []byte(`<html><head>
+<script src="/_next/app.js" async></script>
+<script>self.__next_f=self.__next_f||[]</script>
+</head><body><SCRIPT type="text/javascript">self.__next_f.push([1,"a"])</SCRIPT></body></html>`)},
+		"login/index.html": {Data: []byte(`<script>self.__next_f=self.__next_f||[]</script>`)},
+		"_next/app.js":     {Data: []byte(`document.write("<script>alert(1)</script>")`)},
+	}
+
+	hashes, err := InlineScriptHashes(root)
+	if err != nil {
+		t.Fatalf("Failed to hash scripts: %v", err)
+	}
+
+	// Scripts with a src attribute, duplicates and non-HTML files are skipped
+	expected := map[string]bool{
+		hashSource(`self.__next_f=self.__next_f||[]`): true,
+		hashSource(`self.__next_f.push([1,"a"])`):     true,
+	}
+	if len(hashes) != len(expected) {
+		t.Fatalf("Expected %d hashes, got %v", len(expected), hashes)
+	}
+	for _, hash := range hashes {
+		if !expected[hash] {
+			t.Errorf("Unexpected hash %s", hash)
+		}
+	}
+}
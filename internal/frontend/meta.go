@@ -0,0 +1,85 @@
+package frontend
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+)
+
+// Meta describes a page for link previews in chats and social networks
+type Meta struct {
+	// Title is the title of the page, without the site name
+	Title string
+	// Description is a short summary of the page
+	Description string
+	// Type is the Open Graph type, like "article" or "profile"
+	Type string
+	// URL is the canonical absolute URL of the page
+	URL string
+	// Image is the absolute URL of a preview image, empty for none
+	Image string
+	// SiteName is the name of the site, appended to the document title
+	SiteName string
+}
+
+// MetaFunc returns the meta tags of the page at a URL path, or false to keep
+// the tags of the exported page
+type MetaFunc func(urlPath string) (Meta, bool)
+
+var (
+	// titlePattern matches the title element
+	titlePattern = regexp.MustCompile(`(?is)<title\b[^>]*>.*?</title>`)
+	// metaPattern matches the description, Open Graph and Twitter card meta tags
+	metaPattern = regexp.MustCompile(`(?is)<meta\s[^>]*\b(?:name|property)\s*=\s*"(?:description|og:[^"]*|twitter:[^"]*)"[^>]*>`)
+	// headEndPattern matches the end of the head element
+	headEndPattern = regexp.MustCompile(`(?i)</head>`)
+)
+
+// injectMeta replaces the title and preview meta tags of an HTML page
+func injectMeta(page []byte, meta Meta) []byte {
+	loc := headEndPattern.FindIndex(page)
+	if loc == nil {
+		return page
+	}
+
+	head := titlePattern.ReplaceAll(page[:loc[0]], nil)
+	head = metaPattern.ReplaceAll(head, nil)
+
+	var out bytes.Buffer
+	out.Grow(len(page) + 1024)
+	out.Write(head)
+	meta.writeTags(&out)
+	out.Write(page[loc[0]:])
+	return out.Bytes()
+}
+
+// writeTags writes the title and meta tags
+func (m Meta) writeTags(b *bytes.Buffer) {
+	title := m.Title
+	if m.SiteName != "" {
+		title += " | " + m.SiteName
+	}
+	b.WriteString("<title>" + html.EscapeString(title) + "</title>")
+
+	tag := func(attribute, key, value string) {
+		if value != "" {
+			b.WriteString(`<meta ` + attribute + `="` + key + `" content="` + html.EscapeString(value) + `"/>`)
+		}
+	}
+	tag("name", "description", m.Description)
+	tag("property", "og:type", m.Type)
+	tag("property", "og:title", m.Title)
+	tag("property", "og:description", m.Description)
+	tag("property", "og:url", m.URL)
+	tag("property", "og:image", m.Image)
+	tag("property", "og:site_name", m.SiteName)
+
+	card := "summary"
+	if m.Image != "" {
+		card = "summary_large_image"
+	}
+	tag("name", "twitter:card", card)
+	tag("name", "twitter:title", m.Title)
+	tag("name", "twitter:description", m.Description)
+	tag("name", "twitter:image", m.Image)
+}
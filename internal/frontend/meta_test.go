@@ -0,0 +1,121 @@
+package frontend
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// exportedPage is a page with the generic head of the static export
+const exportedPage = `<!DOCTYPE html><html><head><meta charSet="utf-8"/>` +
+	`<title>Real World Example</title>` +
+	`<meta name="description" content="A real world example using Next.js and chadcn UI"/>` +
+	`<meta property="og:title" content="Generic"/>` +
+	`<meta name="twitter:card" content="summary"/>` +
+	`<link rel="stylesheet" href="/_next/static/css/app.css"/></head><body>Article</body></html>`
+
+func TestInjectMeta(t *testing.T) {
+	page := string(injectMeta([]byte(exportedPage), Meta{
+		Title:       `Say "hello" <world>`,
+		Description: "Greetings & more",
+		Type:        "article",
+		URL:         "https://conduit.example.com/article/hello",
+		Image:       "https://images.example.com/alice.png",
+		SiteName:    "Real World Example",
+	}))
+
+	expected := []string{
+		`<title>Say &#34;hello&#34; &lt;world&gt; | Real World Example</title>`,
+		`<meta name="description" content="Greetings &amp; more"/>`,
+		`<meta property="og:type" content="article"/>`,
+		`<meta property="og:title" content="Say &#34;hello&#34; &lt;world&gt;"/>`,
+		`<meta property="og:url" content="https://conduit.example.com/article/hello"/>`,
+		`<meta property="og:image" content="https://images.example.com/alice.png"/>`,
+		`<meta property="og:site_name" content="Real World Example"/>`,
+		`<meta name="twitter:card" content="summary_large_image"/>`,
+		`<meta name="twitter:image" content="https://images.example.com/alice.png"/>`,
+	}
+	for _, tag := range expected {
+		if !strings.Contains(page, tag) {
+			t.Errorf("Expected %s in page, got:\n%s", tag, page)
+		}
+	}
+
+	// The generic tags are gone, everything else is kept
+	for _, generic := range []string{"A real world example", "Generic", `content="summary"`, "<title>Real World Example</title>"} {
+		if strings.Contains(page, generic) {
+			t.Errorf("Expected %q to be removed, got:\n%s", generic, page)
+		}
+	}
+	for _, kept := range []string{`<meta charSet="utf-8"/>`, `<link rel="stylesheet"`, "</head><body>Article</body>"} {
+		if !strings.Contains(page, kept) {
+			t.Errorf("Expected %q to be kept, got:\n%s", kept, page)
+		}
+	}
+}
+
+func TestInjectMetaWithoutImage(t *testing.T) {
+	page := string(injectMeta([]byte(exportedPage), Meta{Title: "alice"}))
+
+	if !strings.Contains(page, `<meta name="twitter:card" content="summary"/>`) {
+		t.Errorf("Expected summary card without image, got:\n%s", page)
+	}
+	if strings.Contains(page, "og:image") || strings.Contains(page, "og:description") {
+		t.Errorf("Expected no empty tags, got:\n%s", page)
+	}
+}
+
+func TestInjectMetaWithoutHead(t *testing.T) {
+	page := []byte("<p>fragment</p>")
+	if got := string(injectMeta(page, Meta{Title: "alice"})); got != "<p>fragment</p>" {
+		t.Errorf("Expected page without head to be unchanged, got %q", got)
+	}
+}
+
+func TestHandlerMeta(t *testing.T) {
+	root := fstest.MapFS{
+		"article/hello.html":    {Data: []byte(exportedPage)},
+		"article/hello.html.br": {Data: []byte("brotli")},
+		"login.html":            {Data: []byte(exportedPage)},
+	}
+	var paths []string
+	handler := Handler(root, Options{Meta: func(urlPath string) (Meta, bool) {
+		paths = append(paths, urlPath)
+		if strings.HasPrefix(urlPath, "/article/") {
+			return Meta{Title: "From the API"}, true
+		}
+		return Meta{}, false
+	}})
+
+	// Article pages get the meta tags, uncompressed
+	req := httptest.NewRequest("GET", "/article/other-slug/", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "<title>From the API</title>") {
+		t.Errorf("Expected injected title, got:\n%s", rr.Body.String())
+	}
+	if encoding := rr.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("Expected no Content-Encoding, got %s", encoding)
+	}
+	if rr.Header().Get("ETag") == "" {
+		t.Error("Expected an ETag")
+	}
+	if len(paths) != 1 || paths[0] != "/article/other-slug" {
+		t.Errorf("Expected meta lookup for /article/other-slug, got %v", paths)
+	}
+
+	// Other pages keep the exported tags
+	req = httptest.NewRequest("GET", "/login", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Body.String() != exportedPage {
+		t.Errorf("Expected exported page, got:\n%s", rr.Body.String())
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/mail"
+	"github.com/denga/go-real-world-example/internal/middleware"
+)
+
+const (
+	// passwordResetExpiry is how long a password reset link is valid
+	passwordResetExpiry = time.Hour
+	// emailVerificationExpiry is how long an email verification link is valid
+	emailVerificationExpiry = 48 * time.Hour
+)
+
+// sendActionEmail creates a single-use token for the user and mails a link
+// containing it. path is the frontend page that handles the link.
+func (h *Handler) sendActionEmail(email, purpose string, expiry time.Duration, path, subject, text string) error {
+	token, err := auth.GenerateOneTimeToken()
+	if err != nil {
+		return err
+	}
+
+	err = h.DB.CreateActionToken(db.ActionToken{
+		Hash:      auth.HashOneTimeToken(token),
+		Email:     email,
+		Purpose:   purpose,
+		ExpiresAt: h.now().Add(expiry),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s%s?token=%s", strings.TrimRight(h.AppURL, "/"), path, url.QueryEscape(token))
+	return h.Mailer.Send(mail.Message{
+		To:      email,
+		Subject: subject,
+		Body:    fmt.Sprintf("%s\n\n%s\n\nThe link expires in %s and can only be used once.\n", text, link, expiry),
+	})
+}
+
+// sendVerificationEmail mails a link that confirms the user's email address
+func (h *Handler) sendVerificationEmail(email string) error {
+	return h.sendActionEmail(email, db.PurposeEmailVerification, emailVerificationExpiry,
+		"/verify-email", "Verify your email address",
+		"Please confirm your email address by opening this link:")
+}
+
+// RequestPasswordReset mails a password reset link to the user
+func (h *Handler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var request api.PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Unknown addresses get the same response so that the endpoint can't be
+	// used to find out which emails have an account. The email is sent in
+	// the background, so that the response time doesn't tell either.
+	h.mails.Add(1)
+	go func() {
+		defer h.mails.Done()
+		err := h.sendActionEmail(request.User.Email, db.PurposePasswordReset, passwordResetExpiry,
+			"/reset-password", "Reset your password",
+			"Someone asked to reset the password of your account. If it was you, choose a new password here:")
+		if err != nil && err != db.ErrNotFound {
+			log.Printf("Error sending password reset email: %v", err)
+		}
+	}()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ConfirmPasswordReset sets a new password using a password reset token
+func (h *Handler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var request api.PasswordResetConfirmationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if request.User.Password == "" {
+		http.Error(w, "Password can't be empty", http.StatusUnprocessableEntity)
+		return
+	}
+
+	// The token is consumed even if setting the password fails below
+	email, err := h.DB.ConsumeActionToken(auth.HashOneTimeToken(request.User.Token), db.PurposePasswordReset, h.now())
+	if err != nil {
+		http.Error(w, "Invalid or expired token", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := h.DB.SetPassword(email, request.User.Password, h.now()); err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Invalid or expired token", http.StatusUnprocessableEntity)
+		} else {
+			http.Error(w, "Error setting password", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Proving access to the mailbox lifts a lockout
+	h.LoginThrottle.Unlock(email)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// VerifyEmail marks the user's email address as verified using a verification token
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var request api.EmailVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	email, err := h.DB.ConsumeActionToken(auth.HashOneTimeToken(request.User.Token), db.PurposeEmailVerification, h.now())
+	if err != nil {
+		http.Error(w, "Invalid or expired token", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := h.markEmailVerified(email); err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Invalid or expired token", http.StatusUnprocessableEntity)
+		} else {
+			http.Error(w, "Error verifying email", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ResendVerificationEmail sends a new verification email to the current user
+func (h *Handler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get user from database
+	user, err := h.DB.GetInternalUserByEmail(email)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving user", http.StatusInternalServerError)
+		}
+		return
+	}
+	if user.EmailVerified {
+		http.Error(w, "Email is already verified", http.StatusConflict)
+		return
+	}
+
+	if err := h.sendVerificationEmail(email); err != nil {
+		http.Error(w, "Error sending verification email", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/mail"
+	"github.com/denga/go-real-world-example/internal/middleware"
+)
+
+// linkPattern matches the link in action emails
+var linkPattern = regexp.MustCompile(`https?://\S+`)
+
+// lastMailLink returns the path and token of the link in the last sent email
+func lastMailLink(t *testing.T, handler *Handler, to string) (string, string) {
+	t.Helper()
+
+	handler.mails.Wait()
+	msg, ok := handler.Mailer.(*mail.Outbox).Last()
+	if !ok {
+		t.Fatal("Expected an email to be sent")
+	}
+	if msg.To != to {
+		t.Fatalf("Expected email to %s, got %s", to, msg.To)
+	}
+
+	link, err := url.Parse(linkPattern.FindString(msg.Body))
+	if err != nil {
+		t.Fatalf("Failed to parse link: %v", err)
+	}
+	return link.Path, link.Query().Get("token")
+}
+
+// confirmPasswordReset calls ConfirmPasswordReset and returns the response recorder
+func confirmPasswordReset(handler *Handler, token, password string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.PasswordResetConfirmationRequest{
+		User: api.PasswordResetConfirmation{Token: token, Password: password},
+	})
+	req := httptest.NewRequest("POST", "/api/users/password-reset/confirm", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.ConfirmPasswordReset(rr, req)
+	return rr
+}
+
+// verifyEmail calls VerifyEmail and returns the response recorder
+func verifyEmail(handler *Handler, token string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.EmailVerificationRequest{User: api.EmailVerification{Token: token}})
+	req := httptest.NewRequest("POST", "/api/users/verify-email", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.VerifyEmail(rr, req)
+	return rr
+}
+
+// requestPasswordReset calls RequestPasswordReset and returns the response recorder
+func requestPasswordReset(handler *Handler, email string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.PasswordResetRequest{User: api.PasswordReset{Email: email}})
+	req := httptest.NewRequest("POST", "/api/users/password-reset", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.RequestPasswordReset(rr, req)
+	return rr
+}
+
+func TestPasswordReset(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	// Request a reset
+	if rr := requestPasswordReset(handler, user.Email); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	path, token := lastMailLink(t, handler, user.Email)
+	if path != "/reset-password" || token == "" {
+		t.Fatalf("Unexpected reset link path %s with token %q", path, token)
+	}
+
+	// Set a new password
+	if rr := confirmPasswordReset(handler, token, "newpassword456"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if err := testDB.VerifyUserPassword(user.Email, "newpassword456"); err != nil {
+		t.Errorf("Expected new password to work, got %v", err)
+	}
+
+	// The token can't be used twice
+	if rr := confirmPasswordReset(handler, token, "another789"); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+}
+
+func TestPasswordResetRevokesSessions(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, oldToken := setupTestUser(testDB, handler.AuthConfig)
+	protected := middleware.Auth(handler.AuthConfig, testDB)(http.HandlerFunc(handler.GetCurrentUser))
+
+	// Reset the password a second after the old token was issued, since
+	// tokens only carry whole seconds
+	handler.now = func() time.Time { return time.Now().Add(time.Second) }
+	requestPasswordReset(handler, user.Email)
+	_, token := lastMailLink(t, handler, user.Email)
+	if rr := confirmPasswordReset(handler, token, "newpassword456"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	// The old session token no longer works
+	req := httptest.NewRequest("GET", "/api/user", nil)
+	addAuthHeader(req, oldToken)
+	rr := httptest.NewRecorder()
+	protected.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+	if body := strings.TrimSpace(rr.Body.String()); body != "Token revoked" {
+		t.Errorf("Expected error %q, got %q", "Token revoked", body)
+	}
+}
+
+func TestPasswordResetExpires(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return now }
+
+	requestPasswordReset(handler, user.Email)
+	_, token := lastMailLink(t, handler, user.Email)
+
+	// The link is no longer valid after the expiry
+	now = now.Add(passwordResetExpiry)
+	if rr := confirmPasswordReset(handler, token, "newpassword456"); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+	if err := testDB.VerifyUserPassword(user.Email, "password123"); err != nil {
+		t.Errorf("Expected old password to still work, got %v", err)
+	}
+}
+
+func TestPasswordResetForUnknownEmail(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	// The response doesn't reveal whether the account exists
+	if rr := requestPasswordReset(handler, "unknown@example.com"); rr.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	handler.mails.Wait()
+	if len(handler.Mailer.(*mail.Outbox).Messages()) != 0 {
+		t.Error("Expected no email for unknown account")
+	}
+}
+
+func TestEmailVerification(t *testing.T) {
+	handler, testDB := setupTestHandler()
+
+	// Register a user
+	body, _ := json.Marshal(api.NewUserRequest{
+		User: api.NewUser{Username: "newuser", Email: "new@example.com", Password: "password123"},
+	})
+	req := httptest.NewRequest("POST", "/api/users", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.CreateUser(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", http.StatusCreated, rr.Code)
+	}
+
+	// Registration sends a verification email
+	path, token := lastMailLink(t, handler, "new@example.com")
+	if path != "/verify-email" {
+		t.Errorf("Expected verification link, got %s", path)
+	}
+
+	// A resent email invalidates the first link
+	req = httptest.NewRequest("POST", "/api/user/verify-email", nil)
+	req = addUserToContext(req, "new@example.com")
+	rr = httptest.NewRecorder()
+	handler.ResendVerificationEmail(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr := verifyEmail(handler, token); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d for superseded token, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+
+	// The new link verifies the address
+	_, token = lastMailLink(t, handler, "new@example.com")
+	if rr := verifyEmail(handler, token); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	user, _ := testDB.GetInternalUserByEmail("new@example.com")
+	if !user.EmailVerified {
+		t.Error("Expected email to be verified")
+	}
+
+	// Verified users can't request another email
+	req = httptest.NewRequest("POST", "/api/user/verify-email", nil)
+	req = addUserToContext(req, "new@example.com")
+	rr = httptest.NewRecorder()
+	handler.ResendVerificationEmail(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d, got %d", http.StatusConflict, rr.Code)
+	}
+}
+
+func TestEmailChangeSendsVerificationEmail(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	testDB.SetEmailVerified(user.Email)
+
+	// Change the email address
+	newEmail := "changed@example.com"
+	body, _ := json.Marshal(api.UpdateUserRequest{User: api.UpdateUser{Email: &newEmail}})
+	req := httptest.NewRequest("PUT", "/api/user", bytes.NewBuffer(body))
+	req = addUserToContext(req, user.Email)
+	rr := httptest.NewRecorder()
+	handler.UpdateCurrentUser(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if changed, _ := testDB.GetInternalUserByEmail(newEmail); changed.EmailVerified {
+		t.Error("Expected the new address to be unverified")
+	}
+
+	// The new address gets a verification link
+	path, token := lastMailLink(t, handler, newEmail)
+	if path != "/verify-email" {
+		t.Errorf("Expected verification link, got %s", path)
+	}
+	if rr := verifyEmail(handler, token); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if changed, _ := testDB.GetInternalUserByEmail(newEmail); !changed.EmailVerified {
+		t.Error("Expected the new address to be verified")
+	}
+
+	// Other changes don't send emails
+	bio := "New bio"
+	body, _ = json.Marshal(api.UpdateUserRequest{User: api.UpdateUser{Bio: &bio}})
+	req = httptest.NewRequest("PUT", "/api/user", bytes.NewBuffer(body))
+	req = addUserToContext(req, newEmail)
+	rr = httptest.NewRecorder()
+	handler.UpdateCurrentUser(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if messages := handler.Mailer.(*mail.Outbox).Messages(); len(messages) != 1 {
+		t.Errorf("Expected only the verification email, got %d", len(messages))
+	}
+}
+
+func TestResetTokenCannotVerifyEmail(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	requestPasswordReset(handler, user.Email)
+	_, token := lastMailLink(t, handler, user.Email)
+
+	// Tokens only work for the action they were issued for
+	if rr := verifyEmail(handler, token); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// GetLoginAttempts returns the audit trail of failed login attempts
+func (h *Handler) GetLoginAttempts(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authorize(w, r, policy.ViewLoginAudit, ""); !ok {
+		return
+	}
+
+	// Prepare response
+	response := api.LoginAttemptsResponse{
+		Attempts: []api.LoginAttempt{},
+	}
+	for _, attempt := range h.LoginThrottle.FailedAttempts() {
+		response.Attempts = append(response.Attempts, api.LoginAttempt{
+			Email:     attempt.Email,
+			Ip:        attempt.IP,
+			Reason:    attempt.Reason,
+			CreatedAt: attempt.Time,
+		})
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetLockouts returns the accounts that are currently locked
+func (h *Handler) GetLockouts(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authorize(w, r, policy.ViewLoginAudit, ""); !ok {
+		return
+	}
+
+	// Prepare response
+	response := api.LockoutsResponse{
+		Lockouts: []api.Lockout{},
+	}
+	for _, lockout := range h.LoginThrottle.Lockouts() {
+		response.Lockouts = append(response.Lockouts, api.Lockout{
+			Email:       lockout.Email,
+			Failures:    lockout.Failures,
+			LockedUntil: lockout.LockedUntil,
+		})
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteLockout unlocks an account and resets its failed login counter
+func (h *Handler) DeleteLockout(w http.ResponseWriter, r *http.Request, email string) {
+	if _, ok := h.authorize(w, r, policy.UnlockAccount, ""); !ok {
+		return
+	}
+
+	if !h.LoginThrottle.Unlock(email) {
+		http.Error(w, "No failed logins recorded for this account", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+func TestAdminEndpointsRequireAdmin(t *testing.T) {
+	handler, testDB := setupTestHandler()
+
+	// Create a test user who is not an admin
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	// Create request
+	req := httptest.NewRequest("GET", "/api/admin/login-attempts", nil)
+	req = addUserToContext(req, user.Email)
+
+	// Create response recorder
+	rr := httptest.NewRecorder()
+
+	// Call handler
+	handler.GetLoginAttempts(rr, req)
+
+	// Check response
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestGetLoginAttempts(t *testing.T) {
+	handler, testDB := setupTestHandler()
+
+	// Create a test user and make them an admin
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	testDB.SetUserRole(user.Email, policy.RoleAdmin)
+
+	// Record a failed attempt
+	handler.LoginThrottle.UnknownAccountFailure("victim@example.com", "198.51.100.7")
+
+	// Create request
+	req := httptest.NewRequest("GET", "/api/admin/login-attempts", nil)
+	req = addUserToContext(req, user.Email)
+
+	// Create response recorder
+	rr := httptest.NewRecorder()
+
+	// Call handler
+	handler.GetLoginAttempts(rr, req)
+
+	// Check response
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	// Parse response
+	var resp api.LoginAttemptsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if len(resp.Attempts) != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", len(resp.Attempts))
+	}
+	if resp.Attempts[0].Email != "victim@example.com" || resp.Attempts[0].Ip != "198.51.100.7" {
+		t.Errorf("Unexpected attempt: %+v", resp.Attempts[0])
+	}
+}
+
+func TestLockoutsAndUnlock(t *testing.T) {
+	handler, testDB := setupTestHandler()
+
+	// Create a test user and make them an admin
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	testDB.SetUserRole(user.Email, policy.RoleAdmin)
+
+	// Lock an account
+	for i := 0; i < auth.DefaultThrottleConfig().LockoutThreshold; i++ {
+		handler.LoginThrottle.Failure("victim@example.com", "198.51.100.7", "invalid password")
+	}
+
+	// List lockouts
+	req := httptest.NewRequest("GET", "/api/admin/lockouts", nil)
+	req = addUserToContext(req, user.Email)
+	rr := httptest.NewRecorder()
+	handler.GetLockouts(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp api.LockoutsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Lockouts) != 1 || resp.Lockouts[0].Email != "victim@example.com" {
+		t.Fatalf("Unexpected lockouts: %+v", resp.Lockouts)
+	}
+
+	// Unlock the account
+	req = httptest.NewRequest("DELETE", "/api/admin/lockouts/victim@example.com", nil)
+	req = addUserToContext(req, user.Email)
+	rr = httptest.NewRecorder()
+	handler.DeleteLockout(rr, req, "victim@example.com")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if len(handler.LoginThrottle.Lockouts()) != 0 {
+		t.Error("Expected no lockouts after unlock")
+	}
+
+	// Unlocking again reports nothing to unlock
+	rr = httptest.NewRecorder()
+	handler.DeleteLockout(rr, req, "victim@example.com")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/middleware"
+)
+
+// publication validates the requested status and publication time of an
+// article. Articles are published right away by default, and a publication
+// time on its own schedules the article. It returns a message describing the
+// problem if the request is invalid.
+func (h *Handler) publication(status *string, publishAt *time.Time) (string, *time.Time, string) {
+	requested := db.StatusPublished
+	if status != nil {
+		requested = *status
+	} else if publishAt != nil {
+		requested = db.StatusScheduled
+	}
+
+	if !db.IsValidStatus(requested) {
+		return "", nil, "Status must be draft, scheduled, published or unlisted"
+	}
+	if requested != db.StatusScheduled {
+		if publishAt != nil {
+			return "", nil, "Only scheduled articles have a publication time"
+		}
+		return requested, nil, ""
+	}
+	if publishAt == nil || !publishAt.After(h.now()) {
+		return "", nil, "Scheduled articles need a publication time in the future"
+	}
+
+	return requested, publishAt, ""
+}
+
+// GetUserDrafts lists the drafts and scheduled articles of the current user,
+// most recently updated first
+func (h *Handler) GetUserDrafts(w http.ResponseWriter, r *http.Request, params api.GetUserDraftsParams) {
+	// Set default values for limit and offset
+	limit := 20
+	if params.Limit != nil {
+		limit = int(*params.Limit)
+	}
+
+	offset := 0
+	if params.Offset != nil {
+		offset = int(*params.Offset)
+	}
+
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get user from database
+	user, err := h.DB.GetUserByEmail(email)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving user", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Get drafts from database
+	articles, count := h.DB.ListDrafts(user.Username, limit, offset)
+
+	// Prepare response
+	response := multipleArticlesResponse(articles, count)
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// createArticleWithStatus calls CreateArticle with a publication status and time
+func createArticleWithStatus(handler *Handler, email, title string, status *string, publishAt *time.Time) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.NewArticleRequest{Article: api.NewArticle{
+		Title:       title,
+		Description: "Description",
+		Body:        "Body",
+		Status:      status,
+		PublishAt:   publishAt,
+	}})
+	req := httptest.NewRequest("POST", "/api/articles", bytes.NewBuffer(body))
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.CreateArticle(rr, req)
+	return rr
+}
+
+func TestCreateArticleStatus(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	now := time.Now()
+	handler.now = func() time.Time { return now }
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	draft, scheduled, published, unknown := db.StatusDraft, db.StatusScheduled, db.StatusPublished, "archived"
+
+	tests := []struct {
+		name           string
+		status         *string
+		publishAt      *time.Time
+		expectedCode   int
+		expectedStatus string
+	}{
+		{"Default", nil, nil, http.StatusCreated, db.StatusPublished},
+		{"Draft", &draft, nil, http.StatusCreated, db.StatusDraft},
+		{"Scheduled", &scheduled, &future, http.StatusCreated, db.StatusScheduled},
+		{"Implicitly scheduled", nil, &future, http.StatusCreated, db.StatusScheduled},
+		{"Unknown status", &unknown, nil, http.StatusUnprocessableEntity, ""},
+		{"Scheduled without time", &scheduled, nil, http.StatusUnprocessableEntity, ""},
+		{"Scheduled in the past", &scheduled, &past, http.StatusUnprocessableEntity, ""},
+		{"Published with time", &published, &future, http.StatusUnprocessableEntity, ""},
+	}
+
+	for _, tt := range tests {
+		rr := createArticleWithStatus(handler, user.Email, tt.name, tt.status, tt.publishAt)
+		if rr.Code != tt.expectedCode {
+			t.Errorf("%s: expected status code %d, got %d", tt.name, tt.expectedCode, rr.Code)
+			continue
+		}
+		if tt.expectedCode != http.StatusCreated {
+			continue
+		}
+		var resp api.SingleArticleResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if resp.Article.Status != tt.expectedStatus {
+			t.Errorf("%s: expected status %s, got %s", tt.name, tt.expectedStatus, resp.Article.Status)
+		}
+	}
+}
+
+func TestUpdateArticleStatus(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	draft := db.StatusDraft
+	createArticleWithStatus(handler, user.Email, "Draft", &draft, nil)
+
+	update := func(article api.UpdateArticle) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(api.UpdateArticleJSONRequestBody{Article: article})
+		req := httptest.NewRequest("PUT", "/api/articles/draft", bytes.NewBuffer(body))
+		req = addUserToContext(req, user.Email)
+		rr := httptest.NewRecorder()
+		handler.UpdateArticle(rr, req, "draft")
+		return rr
+	}
+
+	// Invalid publications are rejected
+	past := time.Now().Add(-time.Hour)
+	if rr := update(api.UpdateArticle{PublishAt: &past}); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+
+	// A publication time schedules the draft
+	future := time.Now().Add(time.Hour)
+	rr := update(api.UpdateArticle{PublishAt: &future})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	article, _ := testDB.GetArticle("draft")
+	if article.Status != db.StatusScheduled || article.PublishAt == nil || !article.PublishAt.Equal(future) {
+		t.Errorf("Expected the article to be scheduled for %v, got %s %v", future, article.Status, article.PublishAt)
+	}
+
+	// Publishing clears the publication time
+	published := db.StatusPublished
+	if rr := update(api.UpdateArticle{Status: &published}); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	article, _ = testDB.GetArticle("draft")
+	if article.Status != db.StatusPublished || article.PublishAt != nil {
+		t.Errorf("Expected a published article without publication time, got %s %v", article.Status, article.PublishAt)
+	}
+}
+
+func TestGetArticleDraft(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	other := setupRoleUser(t, testDB, "other", policy.RoleUser)
+	draft, unlisted := db.StatusDraft, db.StatusUnlisted
+	createArticleWithStatus(handler, user.Email, "Draft", &draft, nil)
+	createArticleWithStatus(handler, user.Email, "Unlisted", &unlisted, nil)
+
+	tests := []struct {
+		email    string
+		slug     string
+		expected int
+	}{
+		{user.Email, "draft", http.StatusOK},
+		{other, "draft", http.StatusNotFound},
+		{other, "unlisted", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/articles/"+tt.slug, nil)
+		req = addUserToContext(req, tt.email)
+		rr := httptest.NewRecorder()
+		handler.GetArticle(rr, req, tt.slug)
+		if rr.Code != tt.expected {
+			t.Errorf("%s by %s: expected status code %d, got %d", tt.slug, tt.email, tt.expected, rr.Code)
+		}
+
+		// Revisions are hidden alike
+		rr = httptest.NewRecorder()
+		handler.ListArticleRevisions(rr, req, tt.slug)
+		if rr.Code != tt.expected {
+			t.Errorf("Revisions of %s by %s: expected status code %d, got %d", tt.slug, tt.email, tt.expected, rr.Code)
+		}
+	}
+}
+
+func TestGetArticleRenamedDraft(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	other := setupRoleUser(t, testDB, "other", policy.RoleUser)
+	draft := db.StatusDraft
+	createArticleWithStatus(handler, user.Email, "Working Title", &draft, nil)
+	if rr := updateArticleTitle(handler, user.Email, "working-title", "Secret Launch"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	tests := []struct {
+		email    string
+		expected int
+	}{
+		{user.Email, http.StatusMovedPermanently},
+		{other, http.StatusNotFound},
+		{"", http.StatusNotFound},
+	}
+
+	// Only the author is redirected, the new slug would give the title away
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/articles/working-title", nil)
+		if tt.email != "" {
+			req = addUserToContext(req, tt.email)
+		}
+		rr := httptest.NewRecorder()
+		handler.GetArticle(rr, req, "working-title")
+		if rr.Code != tt.expected {
+			t.Errorf("Viewer %q: expected status code %d, got %d", tt.email, tt.expected, rr.Code)
+		}
+		if rr.Code == http.StatusNotFound && rr.Header().Get("Location") != "" {
+			t.Errorf("Viewer %q: expected no Location, got %s", tt.email, rr.Header().Get("Location"))
+		}
+	}
+}
+
+func TestGetUserDrafts(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	draft, unlisted := db.StatusDraft, db.StatusUnlisted
+	createArticle(handler, user.Email, "Published")
+	createArticleWithStatus(handler, user.Email, "Unlisted", &unlisted, nil)
+	createArticleWithStatus(handler, user.Email, "Draft", &draft, nil)
+
+	req := httptest.NewRequest("GET", "/api/user/drafts", nil)
+	req = addUserToContext(req, user.Email)
+	rr := httptest.NewRecorder()
+	handler.GetUserDrafts(rr, req, api.GetUserDraftsParams{})
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp api.MultipleArticlesResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.ArticlesCount != 1 || resp.Articles[0].Slug != "draft" || resp.Articles[0].Status != db.StatusDraft {
+		t.Errorf("Expected only the draft, got %+v", resp)
+	}
+
+	// Drafts and unlisted articles are not listed
+	rr = httptest.NewRecorder()
+	handler.GetArticles(rr, httptest.NewRequest("GET", "/api/articles", nil), api.GetArticlesParams{})
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.ArticlesCount != 1 || resp.Articles[0].Slug != "published" {
+		t.Errorf("Expected only the published article, got %+v", resp)
+	}
+}
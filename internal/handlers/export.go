@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/middleware"
+)
+
+// GetDataExport returns everything stored about the current user
+func (h *Handler) GetDataExport(w http.ResponseWriter, r *http.Request, params api.GetDataExportParams) {
+	format := "json"
+	if params.Format != nil && *params.Format != "" {
+		format = *params.Format
+	}
+	if format != "json" && format != "zip" {
+		http.Error(w, "Format must be json or zip", http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	export, err := h.dataExport(email)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error exporting data", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	filename := "conduit-export-" + export.User.Username
+	w.Header().Set("Cache-Control", "no-store")
+
+	if format == "zip" {
+		archive, err := zipDataExport(export)
+		if err != nil {
+			log.Printf("Error creating data export archive: %v", err)
+			http.Error(w, "Error exporting data", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".zip"))
+		w.Write(archive)
+		return
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+	json.NewEncoder(w).Encode(export)
+}
+
+// dataExport collects the data export of a user
+func (h *Handler) dataExport(email string) (*api.DataExport, error) {
+	user, err := h.DB.GetInternalUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	data, err := h.DB.GetUserData(email)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &api.DataExport{
+		ExportedAt: h.now().UTC(),
+		User: api.ExportedAccount{
+			Username:         user.Username,
+			Email:            user.Email,
+			Bio:              user.Bio,
+			Image:            user.Image,
+			Role:             string(user.Role),
+			EmailVerified:    user.EmailVerified,
+			TwoFactorEnabled: user.TwoFactorEnabled(),
+			Identities:       data.Identities,
+		},
+		Articles:       data.Articles,
+		Comments:       []api.ExportedComment{},
+		Following:      data.Following,
+		FollowedTags:   data.FollowedTags,
+		Favorites:      data.Favorites,
+		PersonalTokens: []api.PersonalToken{},
+	}
+	for _, comment := range data.Comments {
+		export.Comments = append(export.Comments, api.ExportedComment{
+			Id:          comment.Id,
+			ArticleSlug: comment.ArticleSlug,
+			Body:        comment.Body,
+			CreatedAt:   comment.CreatedAt,
+			UpdatedAt:   comment.UpdatedAt,
+		})
+	}
+	for _, token := range h.DB.ListPersonalTokens(email) {
+		export.PersonalTokens = append(export.PersonalTokens, toAPIPersonalToken(token))
+	}
+
+	return export, nil
+}
+
+// zipDataExport packs a data export into a zip archive with the JSON export
+// and one Markdown file per article
+func zipDataExport(export *api.DataExport) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	file, err := archive.Create("export.json")
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return nil, err
+	}
+
+	for _, article := range export.Articles {
+		file, err := archive.Create("articles/" + article.Slug + ".md")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Write(articleMarkdown(article)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// articleMarkdown renders an article as Markdown with its metadata in YAML front matter
+func articleMarkdown(article api.Article) []byte {
+	tags := make([]string, len(article.TagList))
+	for i, tag := range article.TagList {
+		tags[i] = strconv.Quote(tag)
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", strconv.Quote(article.Title))
+	fmt.Fprintf(&b, "description: %s\n", strconv.Quote(article.Description))
+	fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(tags, ", "))
+	fmt.Fprintf(&b, "createdAt: %s\n", article.CreatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "updatedAt: %s\n", article.UpdatedAt.UTC().Format(time.RFC3339))
+	b.WriteString("---\n\n")
+	b.WriteString(article.Body)
+	if !strings.HasSuffix(article.Body, "\n") {
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+// DeleteCurrentUser deletes the account of the current user with all of
+// their content
+func (h *Handler) DeleteCurrentUser(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var request api.DeleteCurrentUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify password
+	if !h.confirmPassword(w, r, email, request.User.Password) {
+		return
+	}
+
+	if err := h.DB.DeleteUser(email); err != nil {
+		switch err {
+		case db.ErrNotFound:
+			http.Error(w, "User not found", http.StatusNotFound)
+		case db.ErrConflict:
+			http.Error(w, "The last admin can't delete their account", http.StatusConflict)
+		default:
+			http.Error(w, "Error deleting user", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Forget failed logins and end the cookie session
+	h.LoginThrottle.Unlock(email)
+	if h.AuthConfig.Mode == auth.ModeCookie {
+		auth.ClearSessionCookies(w, h.AuthConfig)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
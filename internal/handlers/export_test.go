@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// getDataExport calls GetDataExport for the given user and format and returns the response recorder
+func getDataExport(handler *Handler, email, format string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/api/user/export", nil)
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.GetDataExport(rr, req, api.GetDataExportParams{Format: &format})
+	return rr
+}
+
+// deleteCurrentUser calls DeleteCurrentUser for the given user and returns the response recorder
+func deleteCurrentUser(handler *Handler, email, password string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.DeleteCurrentUserJSONRequestBody{User: api.PasswordConfirmation{Password: password}})
+	req := httptest.NewRequest("DELETE", "/api/user", bytes.NewBuffer(body))
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.DeleteCurrentUser(rr, req)
+	return rr
+}
+
+// setupExportData creates an article, a comment, a follow and a favorite of alice
+func setupExportData(t *testing.T, testDB *db.InMemoryDB) string {
+	t.Helper()
+
+	alice := setupRoleUser(t, testDB, "alice", policy.RoleUser)
+	setupRoleUser(t, testDB, "bob", policy.RoleUser)
+	setupArticleWithComment(t, testDB, "bobs-article", "bob", "alice")
+	err := testDB.CreateArticle(api.Article{
+		Slug:        "alices-article",
+		Title:       `Say "hello"`,
+		Description: "Greetings",
+		Body:        "# Hello\n\nWorld",
+		TagList:     []string{"intro"},
+		Author:      api.Profile{Username: "alice"},
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	testDB.FollowUser("alice", "bob")
+	testDB.FollowTag("alice", "intro")
+	testDB.FavoriteArticle("bobs-article", "alice")
+	return alice
+}
+
+func TestGetDataExportJSON(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	alice := setupExportData(t, testDB)
+
+	rr := getDataExport(handler, alice, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if disposition := rr.Header().Get("Content-Disposition"); !strings.Contains(disposition, "conduit-export-alice.json") {
+		t.Errorf("Expected attachment conduit-export-alice.json, got %q", disposition)
+	}
+
+	var export api.DataExport
+	if err := json.NewDecoder(rr.Body).Decode(&export); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if export.User.Username != "alice" || export.User.Email != alice {
+		t.Errorf("Expected alice's account, got %+v", export.User)
+	}
+	if len(export.Articles) != 1 || export.Articles[0].Slug != "alices-article" {
+		t.Errorf("Expected alices-article, got %v", export.Articles)
+	}
+	if len(export.Comments) != 1 || export.Comments[0].ArticleSlug != "bobs-article" {
+		t.Errorf("Expected comment on bobs-article, got %v", export.Comments)
+	}
+	if len(export.Following) != 1 || export.Following[0] != "bob" {
+		t.Errorf("Expected following [bob], got %v", export.Following)
+	}
+	if len(export.Favorites) != 1 || export.Favorites[0] != "bobs-article" {
+		t.Errorf("Expected favorites [bobs-article], got %v", export.Favorites)
+	}
+	if len(export.FollowedTags) != 1 || export.FollowedTags[0] != "intro" {
+		t.Errorf("Expected followed tags [intro], got %v", export.FollowedTags)
+	}
+}
+
+func TestGetDataExportZip(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	alice := setupExportData(t, testDB)
+
+	rr := getDataExport(handler, alice, "zip")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if contentType := rr.Header().Get("Content-Type"); contentType != "application/zip" {
+		t.Errorf("Expected application/zip, got %s", contentType)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	files := map[string]string{}
+	for _, file := range archive.File {
+		f, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", file.Name, err)
+		}
+		content, _ := io.ReadAll(f)
+		f.Close()
+		files[file.Name] = string(content)
+	}
+
+	if _, ok := files["export.json"]; !ok {
+		t.Error("Expected export.json in archive")
+	}
+	markdown, ok := files["articles/alices-article.md"]
+	if !ok {
+		t.Fatalf("Expected articles/alices-article.md in archive, got %v", files)
+	}
+	for _, expected := range []string{`title: "Say \"hello\""`, `tags: ["intro"]`, "---\n\n# Hello\n\nWorld\n"} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", expected, markdown)
+		}
+	}
+}
+
+func TestGetDataExportUnknownFormat(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	alice := setupRoleUser(t, testDB, "alice", policy.RoleUser)
+
+	if rr := getDataExport(handler, alice, "xml"); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+}
+
+func TestDeleteCurrentUser(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	alice := setupExportData(t, testDB)
+
+	// A wrong password keeps the account
+	if rr := deleteCurrentUser(handler, alice, "wrong"); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+	if _, err := testDB.GetUserByEmail(alice); err != nil {
+		t.Fatalf("Expected user to still exist, got %v", err)
+	}
+
+	if rr := deleteCurrentUser(handler, alice, "password123"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	// The account and its content are gone, other users' content is cleaned up
+	if _, err := testDB.GetUserByEmail(alice); err != db.ErrNotFound {
+		t.Errorf("Expected user to be deleted, got %v", err)
+	}
+	if _, err := testDB.GetArticle("alices-article"); err != db.ErrNotFound {
+		t.Errorf("Expected article to be deleted, got %v", err)
+	}
+	comments, _ := testDB.GetComments("bobs-article")
+	if len(comments) != 0 {
+		t.Errorf("Expected comments on bobs-article to be deleted, got %v", comments)
+	}
+	article, _ := testDB.GetArticle("bobs-article")
+	if article.FavoritesCount != 0 {
+		t.Errorf("Expected favorites count 0, got %d", article.FavoritesCount)
+	}
+}
+
+func TestDeleteCurrentUserLastAdmin(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	admin := setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+
+	if rr := deleteCurrentUser(handler, admin, "password123"); rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+}
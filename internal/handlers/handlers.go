@@ -2,30 +2,87 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/denga/go-real-world-example/api"
 	"github.com/denga/go-real-world-example/internal/auth"
 	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/mail"
+	"github.com/denga/go-real-world-example/internal/markdown"
 	"github.com/denga/go-real-world-example/internal/middleware"
+	"github.com/denga/go-real-world-example/internal/oidc"
+	"github.com/denga/go-real-world-example/internal/policy"
 	"github.com/denga/go-real-world-example/internal/util"
 )
 
 // Handler implements the ServerInterface from the generated API code
 type Handler struct {
-	DB         *db.InMemoryDB
-	AuthConfig auth.Config
+	DB            *db.InMemoryDB
+	AuthConfig    auth.Config
+	LoginThrottle *auth.LoginThrottle
+	// Mailer delivers password reset and verification emails
+	Mailer mail.Mailer
+	// AppURL is the public base URL of the frontend, used for links in emails
+	AppURL string
+	// Providers are the external identity providers users can sign in with, by name
+	Providers map[string]*oidc.Provider
+
+	// Markdown renders the bodies of articles to HTML
+	Markdown *markdown.Renderer
+
+	// now returns the current time, replaced in tests to control TOTP codes
+	now func() time.Time
+	// mails tracks emails sent in the background, waited for in tests
+	mails sync.WaitGroup
 }
 
 // NewHandler creates a new Handler
 func NewHandler(db *db.InMemoryDB, authConfig auth.Config) *Handler {
 	return &Handler{
-		DB:         db,
-		AuthConfig: authConfig,
+		DB:            db,
+		AuthConfig:    authConfig,
+		LoginThrottle: auth.NewLoginThrottle(authConfig.Throttle),
+		Mailer:        mail.LogMailer{},
+		AppURL:        "http://localhost:8080",
+		Providers:     make(map[string]*oidc.Provider),
+		Markdown:      markdown.NewRenderer(markdown.DefaultCacheSize),
+		now:           time.Now,
 	}
 }
 
+// startSession moves the user's token into session cookies when cookie mode is
+// enabled. The token is then left out of the response body so that scripts
+// never get to see it.
+func (h *Handler) startSession(w http.ResponseWriter, user *api.User) error {
+	if h.AuthConfig.Mode != auth.ModeCookie {
+		return nil
+	}
+
+	if err := auth.SetSessionCookies(w, user.Token, h.AuthConfig); err != nil {
+		return err
+	}
+	user.Token = ""
+
+	return nil
+}
+
+// writeRetryAfter sets the Retry-After header, rounding the wait up to whole seconds
+func writeRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
+
 // GetArticles returns a list of articles
 func (h *Handler) GetArticles(w http.ResponseWriter, r *http.Request, params api.GetArticlesParams) {
 	// Set default values for limit and offset
@@ -42,7 +99,7 @@ func (h *Handler) GetArticles(w http.ResponseWriter, r *http.Request, params api
 	// Extract filter parameters
 	var tag, author, favorited string
 	if params.Tag != nil {
-		tag = *params.Tag
+		tag = util.NormalizeTag(*params.Tag)
 	}
 	if params.Author != nil {
 		author = *params.Author
@@ -59,36 +116,7 @@ func (h *Handler) GetArticles(w http.ResponseWriter, r *http.Request, params api
 	}
 
 	// Prepare response
-	response := api.MultipleArticlesResponse{
-		ArticlesCount: count,
-	}
-
-	// Convert articles to response format
-	for _, article := range articles {
-		// Create an anonymous struct that matches the expected type
-		responseArticle := struct {
-			Author         api.Profile `json:"author"`
-			CreatedAt      time.Time   `json:"createdAt"`
-			Description    string      `json:"description"`
-			Favorited      bool        `json:"favorited"`
-			FavoritesCount int         `json:"favoritesCount"`
-			Slug           string      `json:"slug"`
-			TagList        []string    `json:"tagList"`
-			Title          string      `json:"title"`
-			UpdatedAt      time.Time   `json:"updatedAt"`
-		}{
-			Author:         article.Author,
-			CreatedAt:      article.CreatedAt,
-			Description:    article.Description,
-			Favorited:      article.Favorited,
-			FavoritesCount: article.FavoritesCount,
-			Slug:           article.Slug,
-			TagList:        article.TagList,
-			Title:          article.Title,
-			UpdatedAt:      article.UpdatedAt,
-		}
-		response.Articles = append(response.Articles, responseArticle)
-	}
+	response := multipleArticlesResponse(articles, count)
 
 	// Write response
 	w.Header().Set("Content-Type", "application/json")
@@ -122,6 +150,12 @@ func (h *Handler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	status, publishAt, problem := h.publication(request.Article.Status, request.Article.PublishAt)
+	if problem != "" {
+		http.Error(w, problem, http.StatusUnprocessableEntity)
+		return
+	}
+
 	// Create author profile
 	author := api.Profile{
 		Username:  user.Username,
@@ -130,12 +164,6 @@ func (h *Handler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 		Following: false, // User can't follow themselves
 	}
 
-	// Generate slug from title
-	slug := util.GenerateUniqueSlug(request.Article.Title, func(s string) bool {
-		article, err := h.DB.GetArticle(s)
-		return err == nil && article != nil
-	})
-
 	// Create article
 	article := api.Article{
 		Title:       request.Article.Title,
@@ -144,30 +172,26 @@ func (h *Handler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		Author:      author,
-		Slug:        slug,
+		Slug:        util.GenerateSlug(request.Article.Title),
 		Favorited:   false,
+		Status:      status,
+		PublishAt:   publishAt,
 	}
 
 	// Handle TagList which is a pointer to a slice
+	article.TagList = []string{}
 	if request.Article.TagList != nil {
-		article.TagList = *request.Article.TagList
-	} else {
-		article.TagList = []string{}
-	}
-
-	// Save article to database
-	if err := h.DB.CreateArticle(article); err != nil {
-		if err == db.ErrConflict {
-			http.Error(w, "Article with this title already exists", http.StatusConflict)
-		} else {
-			http.Error(w, "Error creating article", http.StatusInternalServerError)
+		if article.TagList, ok = normalizeTags(w, *request.Article.TagList); !ok {
+			return
 		}
-		return
 	}
 
+	// Save article to database under a free slug
+	article.Slug = h.DB.CreateArticleWithUniqueSlug(article)
+
 	// Prepare response
 	response := api.SingleArticleResponse{
-		Article: article,
+		Article: h.articleResponse(r, article),
 	}
 
 	// Write response
@@ -219,11 +243,35 @@ func (h *Handler) GetArticlesFeed(w http.ResponseWriter, r *http.Request, params
 	}
 
 	// Prepare response
+	response := multipleArticlesResponse(articles, count)
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// normalizeTags normalizes the tags of an article in a request, and writes an
+// error response if there are too many or too long tags
+func normalizeTags(w http.ResponseWriter, tags []string) ([]string, bool) {
+	normalized, err := util.NormalizeTags(tags)
+	switch err {
+	case nil:
+		return normalized, true
+	case util.ErrTooManyTags:
+		http.Error(w, fmt.Sprintf("Articles can have at most %d tags", util.MaxTags), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, fmt.Sprintf("Tags can have at most %d characters", util.MaxTagLength), http.StatusUnprocessableEntity)
+	}
+	return nil, false
+}
+
+// multipleArticlesResponse converts articles to the list response format,
+// which leaves out the article bodies
+func multipleArticlesResponse(articles []api.Article, count int) api.MultipleArticlesResponse {
 	response := api.MultipleArticlesResponse{
 		ArticlesCount: count,
 	}
 
-	// Convert articles to response format
 	for _, article := range articles {
 		// Create an anonymous struct that matches the expected type
 		responseArticle := struct {
@@ -232,7 +280,9 @@ func (h *Handler) GetArticlesFeed(w http.ResponseWriter, r *http.Request, params
 			Description    string      `json:"description"`
 			Favorited      bool        `json:"favorited"`
 			FavoritesCount int         `json:"favoritesCount"`
+			PublishAt      *time.Time  `json:"publishAt,omitempty"`
 			Slug           string      `json:"slug"`
+			Status         string      `json:"status"`
 			TagList        []string    `json:"tagList"`
 			Title          string      `json:"title"`
 			UpdatedAt      time.Time   `json:"updatedAt"`
@@ -242,7 +292,9 @@ func (h *Handler) GetArticlesFeed(w http.ResponseWriter, r *http.Request, params
 			Description:    article.Description,
 			Favorited:      article.Favorited,
 			FavoritesCount: article.FavoritesCount,
+			PublishAt:      article.PublishAt,
 			Slug:           article.Slug,
+			Status:         article.Status,
 			TagList:        article.TagList,
 			Title:          article.Title,
 			UpdatedAt:      article.UpdatedAt,
@@ -250,19 +302,43 @@ func (h *Handler) GetArticlesFeed(w http.ResponseWriter, r *http.Request, params
 		response.Articles = append(response.Articles, responseArticle)
 	}
 
-	// Write response
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	return response
 }
 
-// GetTags returns all tags
-func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+// GetTags returns the tags used by articles, most used or most recently
+// active first. The article counts are only included on request, so that the
+// default response stays compatible with RealWorld clients.
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request, params api.GetTagsParams) {
+	order := db.TagOrderCount
+	if params.Order != nil {
+		order = *params.Order
+	}
+	if !db.IsValidTagOrder(order) {
+		http.Error(w, "Order must be count or recent", http.StatusUnprocessableEntity)
+		return
+	}
+
+	limit := 0
+	if params.Limit != nil {
+		limit = *params.Limit
+		if limit < 1 {
+			http.Error(w, "Limit must be positive", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
 	// Get tags from database
-	tags := h.DB.GetTags()
+	counts := h.DB.TagCounts(order, limit)
 
 	// Prepare response
 	response := api.TagsResponse{
-		Tags: tags,
+		Tags: make([]string, len(counts)),
+	}
+	for i, count := range counts {
+		response.Tags[i] = count.Tag
+	}
+	if params.Counts != nil && *params.Counts {
+		response.TagCounts = &counts
 	}
 
 	// Write response
@@ -305,6 +381,18 @@ func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Ask the user to confirm their email address. Registration succeeds
+	// even if the mail can't be sent, it can be requested again later.
+	if err := h.sendVerificationEmail(user.Email); err != nil {
+		log.Printf("Error sending verification email: %v", err)
+	}
+
+	// Start a cookie session if enabled
+	if err := h.startSession(w, &user); err != nil {
+		http.Error(w, "Error starting session", http.StatusInternalServerError)
+		return
+	}
+
 	// Prepare response
 	response := api.UserResponse{
 		User: user,
@@ -325,27 +413,60 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get user from database
-	user, err := h.DB.GetUserByEmail(request.User.Email)
-	if err != nil {
-		if err == db.ErrNotFound {
-			http.Error(w, "User not found", http.StatusNotFound)
-		} else {
-			http.Error(w, "Error retrieving user", http.StatusInternalServerError)
-		}
+	// Reject the attempt while the account or client is backing off
+	ip := middleware.GetClientIP(r)
+	if wait, err := h.LoginThrottle.Check(request.User.Email, ip); err != nil {
+		writeRetryAfter(w, wait)
+		http.Error(w, "Too many login attempts", http.StatusTooManyRequests)
 		return
 	}
 
-	// Verify password
+	// Verify password. Unknown emails and wrong passwords get the same
+	// response so that the endpoint can't be used to enumerate accounts.
 	if err := h.DB.VerifyUserPassword(request.User.Email, request.User.Password); err != nil {
-		if err == db.ErrInvalidCredentials {
+		switch err {
+		case db.ErrNotFound:
+			h.LoginThrottle.UnknownAccountFailure(request.User.Email, ip)
 			http.Error(w, "Invalid credentials", http.StatusUnauthorized)
-		} else {
+		case db.ErrInvalidCredentials:
+			h.LoginThrottle.Failure(request.User.Email, ip, "invalid password")
+			http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		default:
 			http.Error(w, "Error verifying credentials", http.StatusInternalServerError)
 		}
 		return
 	}
 
+	// Accounts with two-factor authentication only get a challenge that has
+	// to be completed with a second factor
+	internalUser, err := h.DB.GetInternalUserByEmail(request.User.Email)
+	if err != nil {
+		http.Error(w, "Error retrieving user", http.StatusInternalServerError)
+		return
+	}
+	if internalUser.Suspended() {
+		http.Error(w, "Account suspended", http.StatusForbidden)
+		return
+	}
+	if internalUser.TwoFactorEnabled() {
+		h.writeTwoFactorChallenge(w, internalUser.Email)
+		return
+	}
+
+	h.LoginThrottle.Success(request.User.Email)
+	h.writeLogin(w, internalUser.Email)
+}
+
+// writeLogin issues a token for the user and writes the user response,
+// starting a cookie session if enabled
+func (h *Handler) writeLogin(w http.ResponseWriter, email string) {
+	// Get user from database
+	user, err := h.DB.GetUserByEmail(email)
+	if err != nil {
+		http.Error(w, "Error retrieving user", http.StatusInternalServerError)
+		return
+	}
+
 	// Generate token
 	authConfig := h.AuthConfig
 	token, err := auth.GenerateToken(user.Email, authConfig)
@@ -357,6 +478,12 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	// Update user with token
 	user.Token = token
 
+	// Start a cookie session if enabled
+	if err := h.startSession(w, user); err != nil {
+		http.Error(w, "Error starting session", http.StatusInternalServerError)
+		return
+	}
+
 	// Prepare response
 	response := api.UserResponse{
 		User: *user,
@@ -367,6 +494,17 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// Logout ends a cookie session
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	// Tokens sent in the Authorization header can't be revoked, the client
+	// simply discards them. Cookie sessions are ended by expiring the cookies.
+	if h.AuthConfig.Mode == auth.ModeCookie {
+		auth.ClearSessionCookies(w, h.AuthConfig)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 // GetCurrentUser returns the current user
 func (h *Handler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	// Get authenticated user from context
@@ -387,13 +525,23 @@ func (h *Handler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate a fresh token
-	token, err := auth.GenerateToken(email, h.AuthConfig)
-	if err != nil {
-		http.Error(w, "Error generating token", http.StatusInternalServerError)
-		return
+	// Generate a fresh token. Callers with a personal access token get none,
+	// a session token would escape the scopes of the personal access token.
+	user.Token = ""
+	if !middleware.IsPersonalToken(r) {
+		token, err := auth.GenerateToken(email, h.AuthConfig)
+		if err != nil {
+			http.Error(w, "Error generating token", http.StatusInternalServerError)
+			return
+		}
+		user.Token = token
+
+		// Start a cookie session if enabled
+		if err := h.startSession(w, user); err != nil {
+			http.Error(w, "Error starting session", http.StatusInternalServerError)
+			return
+		}
 	}
-	user.Token = token
 
 	// Prepare response
 	response := api.UserResponse{
@@ -434,13 +582,31 @@ func (h *Handler) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate a fresh token
-	token, err := auth.GenerateToken(user.Email, h.AuthConfig)
-	if err != nil {
-		http.Error(w, "Error generating token", http.StatusInternalServerError)
-		return
+	// A new address has to be confirmed like at registration. The update
+	// succeeds even if the mail can't be sent, it can be requested again.
+	if user.Email != email {
+		if err := h.sendVerificationEmail(user.Email); err != nil {
+			log.Printf("Error sending verification email: %v", err)
+		}
+	}
+
+	// Generate a fresh token. Callers with a personal access token get none,
+	// a session token would escape the scopes of the personal access token.
+	user.Token = ""
+	if !middleware.IsPersonalToken(r) {
+		token, err := auth.GenerateToken(user.Email, h.AuthConfig)
+		if err != nil {
+			http.Error(w, "Error generating token", http.StatusInternalServerError)
+			return
+		}
+		user.Token = token
+
+		// Start a cookie session if enabled
+		if err := h.startSession(w, user); err != nil {
+			http.Error(w, "Error starting session", http.StatusInternalServerError)
+			return
+		}
 	}
-	user.Token = token
 
 	// Prepare response
 	response := api.UserResponse{
@@ -452,42 +618,283 @@ func (h *Handler) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// Implement the remaining methods of the ServerInterface
-// These are just stubs for now, but they satisfy the interface
-
+// DeleteArticle deletes an article. Authors can delete their own articles,
+// admins any article.
 func (h *Handler) DeleteArticle(w http.ResponseWriter, r *http.Request, slug string) {
-	http.Error(w, "Not implemented", http.StatusNotImplemented)
+	// Get article from database
+	article, err := h.DB.GetArticle(slug)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Article not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving article", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if _, ok := h.authorize(w, r, policy.DeleteArticle, article.Author.Username); !ok {
+		return
+	}
+
+	if err := h.DB.DeleteArticle(slug); err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Article not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error deleting article", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
 }
 
+// DeleteArticleComment deletes a comment. Authors can delete their own
+// comments, moderators and admins any comment.
+func (h *Handler) DeleteArticleComment(w http.ResponseWriter, r *http.Request, slug string, id int) {
+	// Get comment from database
+	comment, err := h.DB.GetComment(slug, id)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Comment not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving comment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if _, ok := h.authorize(w, r, policy.DeleteComment, comment.Author.Username); !ok {
+		return
+	}
+
+	if err := h.DB.DeleteComment(slug, id); err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Comment not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error deleting comment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetArticle returns an article. Drafts and scheduled articles are only
+// returned to their author. The previous slugs of renamed articles
+// permanently redirect to the current one, for those who may see it.
 func (h *Handler) GetArticle(w http.ResponseWriter, r *http.Request, slug string) {
-	http.Error(w, "Not implemented", http.StatusNotImplemented)
+	// Previous slugs of renamed articles redirect to the current one. The
+	// slug of a hidden article would give its title away, so it isn't found.
+	if _, err := h.DB.GetArticle(slug); err == db.ErrNotFound {
+		if current, ok := h.DB.ResolveSlug(slug); ok {
+			if article, err := h.DB.GetArticle(current); err == nil && h.canView(r, article) {
+				http.Redirect(w, r, "/api/articles/"+url.PathEscape(current), http.StatusMovedPermanently)
+				return
+			}
+		}
+	}
+
+	article, ok := h.visibleArticle(w, r, slug)
+	if !ok {
+		return
+	}
+
+	// Prepare response
+	response := api.SingleArticleResponse{
+		Article: h.articleResponse(r, *article),
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
+// UpdateArticle updates an article of the authenticated user. A new title
+// gives the article a new slug, its previous slug redirects to the new one.
 func (h *Handler) UpdateArticle(w http.ResponseWriter, r *http.Request, slug string) {
-	http.Error(w, "Not implemented", http.StatusNotImplemented)
+	// Parse request body
+	var request api.UpdateArticleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Get article from database
+	article, err := h.DB.GetArticle(slug)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Article not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving article", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	user, ok := h.authorize(w, r, policy.UpdateArticle, article.Author.Username)
+	if !ok {
+		return
+	}
+
+	if request.Article.Title != nil && strings.TrimSpace(*request.Article.Title) == "" {
+		http.Error(w, "Title can't be empty", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if request.Article.TagList != nil {
+		tags, ok := normalizeTags(w, *request.Article.TagList)
+		if !ok {
+			return
+		}
+		request.Article.TagList = &tags
+	}
+
+	// Changing the publication replaces both the status and the publication time
+	if request.Article.Status != nil || request.Article.PublishAt != nil {
+		status, publishAt, problem := h.publication(request.Article.Status, request.Article.PublishAt)
+		if problem != "" {
+			http.Error(w, problem, http.StatusUnprocessableEntity)
+			return
+		}
+		request.Article.Status = &status
+		request.Article.PublishAt = publishAt
+	}
+
+	updated, err := h.DB.UpdateArticle(slug, request.Article, user.Username)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Article not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error updating article", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Prepare response
+	response := api.SingleArticleResponse{
+		Article: h.articleResponse(r, *updated),
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
-func (h *Handler) GetArticleComments(w http.ResponseWriter, r *http.Request, slug string) {
-	http.Error(w, "Not implemented", http.StatusNotImplemented)
+// articleResponse prepares an article for a response: it renders the body to
+// HTML and sets whether the authenticated user, if any, favorited the article
+// and follows its author
+func (h *Handler) articleResponse(r *http.Request, article api.Article) api.Article {
+	bodyHTML := h.Markdown.Render(article.Body)
+	article.BodyHtml = &bodyHTML
+
+	article.Favorited = false
+	article.Author.Following = false
+
+	viewer := h.viewer(r)
+	if viewer == "" {
+		return article
+	}
+
+	article.Favorited = h.DB.IsFavorite(article.Slug, viewer)
+	article.Author.Following = h.DB.IsFollowing(viewer, article.Author.Username)
+	return article
 }
 
-func (h *Handler) CreateArticleComment(w http.ResponseWriter, r *http.Request, slug string) {
-	http.Error(w, "Not implemented", http.StatusNotImplemented)
+// viewer returns the username of the authenticated user, or "" for
+// anonymous requests
+func (h *Handler) viewer(r *http.Request) string {
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		return ""
+	}
+	user, err := h.DB.GetUserByEmail(email)
+	if err != nil {
+		return ""
+	}
+	return user.Username
 }
 
-func (h *Handler) DeleteArticleComment(w http.ResponseWriter, r *http.Request, slug string, id int) {
+// visibleArticle returns an article the authenticated user may read, and
+// writes an error response for unknown articles, articles of suspended users
+// and the drafts of others
+func (h *Handler) visibleArticle(w http.ResponseWriter, r *http.Request, slug string) (*api.Article, bool) {
+	// Get article from database
+	article, err := h.DB.GetArticle(slug)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Article not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving article", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	if !h.canView(r, article) {
+		http.Error(w, "Article not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	return article, true
+}
+
+// canView reports whether the viewer of the request may see the article.
+// Articles of suspended users are hidden, and drafts from everyone but their
+// author.
+func (h *Handler) canView(r *http.Request, article *api.Article) bool {
+	return !h.DB.IsSuspended(article.Author.Username) && (db.IsPublic(*article) || h.viewer(r) == article.Author.Username)
+}
+
+// GetProfileByUsername returns a profile with the tags the user follows.
+// Profiles of suspended users are not found.
+func (h *Handler) GetProfileByUsername(w http.ResponseWriter, r *http.Request, username string) {
+	// Get user from database
+	user, err := h.DB.GetUserByUsername(username)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Profile not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving profile", http.StatusInternalServerError)
+		}
+		return
+	}
+	if h.DB.IsSuspended(user.Username) {
+		http.Error(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	// Prepare response
+	followedTags := h.DB.FollowedTags(user.Username)
+	response := api.ProfileResponse{
+		Profile: api.Profile{
+			Username:     user.Username,
+			Bio:          user.Bio,
+			Image:        user.Image,
+			FollowedTags: &followedTags,
+		},
+	}
+	if viewer := h.viewer(r); viewer != "" {
+		response.Profile.Following = h.DB.IsFollowing(viewer, user.Username)
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Implement the remaining methods of the ServerInterface
+// These are just stubs for now, but they satisfy the interface
+
+func (h *Handler) GetArticleComments(w http.ResponseWriter, r *http.Request, slug string) {
 	http.Error(w, "Not implemented", http.StatusNotImplemented)
 }
 
-func (h *Handler) DeleteArticleFavorite(w http.ResponseWriter, r *http.Request, slug string) {
+func (h *Handler) CreateArticleComment(w http.ResponseWriter, r *http.Request, slug string) {
 	http.Error(w, "Not implemented", http.StatusNotImplemented)
 }
 
-func (h *Handler) CreateArticleFavorite(w http.ResponseWriter, r *http.Request, slug string) {
+func (h *Handler) DeleteArticleFavorite(w http.ResponseWriter, r *http.Request, slug string) {
 	http.Error(w, "Not implemented", http.StatusNotImplemented)
 }
 
-func (h *Handler) GetProfileByUsername(w http.ResponseWriter, r *http.Request, username string) {
+func (h *Handler) CreateArticleFavorite(w http.ResponseWriter, r *http.Request, slug string) {
 	http.Error(w, "Not implemented", http.StatusNotImplemented)
 }
 
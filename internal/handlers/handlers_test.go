@@ -6,13 +6,20 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/denga/go-real-world-example/api"
 	"github.com/denga/go-real-world-example/internal/auth"
 	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/mail"
 	"github.com/denga/go-real-world-example/internal/middleware"
+	"github.com/denga/go-real-world-example/internal/policy"
+	"github.com/denga/go-real-world-example/internal/util"
 )
 
 // setupTestHandler creates a new Handler with a test database and auth config
@@ -28,6 +35,7 @@ func setupTestHandler() (*Handler, *db.InMemoryDB) {
 
 	// Create handler
 	handler := NewHandler(testDB, authConfig)
+	handler.Mailer = mail.NewOutbox()
 
 	return handler, testDB
 }
@@ -202,6 +210,172 @@ func TestLoginWithInvalidCredentials(t *testing.T) {
 	}
 }
 
+func TestLoginWithUnknownEmail(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	// Create request body for an email that is not registered
+	reqBody := api.LoginUserRequest{
+		User: api.LoginUser{
+			Email:    "unknown@example.com",
+			Password: "password123",
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+
+	// Create request
+	req := httptest.NewRequest("POST", "/api/users/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Create response recorder
+	rr := httptest.NewRecorder()
+
+	// Call handler
+	handler.Login(rr, req)
+
+	// Unknown emails get the same response as wrong passwords
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+	if rr.Body.String() != "Invalid credentials\n" {
+		t.Errorf("Expected uniform error message, got %q", rr.Body.String())
+	}
+}
+
+func TestLoginThrottling(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	handler.LoginThrottle = auth.NewLoginThrottle(auth.ThrottleConfig{
+		AccountFreeAttempts: 2,
+		BaseDelay:           time.Minute,
+	})
+
+	// Create a test user
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		reqBody := api.LoginUserRequest{
+			User: api.LoginUser{
+				Email:    user.Email,
+				Password: password,
+			},
+		}
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("POST", "/api/users/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		handler.Login(rr, req)
+		return rr
+	}
+
+	// Exhaust the free attempts
+	for i := 0; i < 2; i++ {
+		if rr := login("wrongpassword"); rr.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status code %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+	}
+
+	// Even the correct password is rejected during backoff
+	rr := login("password123")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status code %d, got %d", http.StatusTooManyRequests, rr.Code)
+	}
+	if rr.Header().Get("Retry-After") != "60" {
+		t.Errorf("Expected Retry-After 60, got %q", rr.Header().Get("Retry-After"))
+	}
+
+	// Failed attempts end up in the audit trail
+	attempts := handler.LoginThrottle.FailedAttempts()
+	if len(attempts) != 2 {
+		t.Fatalf("Expected 2 failed attempts, got %d", len(attempts))
+	}
+	if attempts[0].Reason != "invalid password" || attempts[0].IP != "192.0.2.1" {
+		t.Errorf("Unexpected audit entry: %+v", attempts[0])
+	}
+}
+
+func TestLoginWithCookieMode(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	handler.AuthConfig.Mode = auth.ModeCookie
+	handler.AuthConfig.Cookie = auth.DefaultCookieConfig()
+
+	// Create a test user
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	// Create request body
+	reqBody := api.LoginUserRequest{
+		User: api.LoginUser{
+			Email:    user.Email,
+			Password: "password123",
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+
+	// Create request
+	req := httptest.NewRequest("POST", "/api/users/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Create response recorder
+	rr := httptest.NewRecorder()
+
+	// Call handler
+	handler.Login(rr, req)
+
+	// Check response
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	// The token is only handed out as an HttpOnly cookie
+	var resp api.UserResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.User.Token != "" {
+		t.Error("Expected no token in the response body in cookie mode")
+	}
+
+	var session *http.Cookie
+	for _, cookie := range rr.Result().Cookies() {
+		if cookie.Name == handler.AuthConfig.Cookie.SessionName {
+			session = cookie
+		}
+	}
+	if session == nil || !session.HttpOnly {
+		t.Fatal("Expected an HttpOnly session cookie")
+	}
+	if email, err := auth.ValidateToken(session.Value, handler.AuthConfig); err != nil || email != user.Email {
+		t.Errorf("Expected session cookie to hold a valid token for %s, got %q (%v)", user.Email, email, err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	handler, _ := setupTestHandler()
+	handler.AuthConfig.Mode = auth.ModeCookie
+	handler.AuthConfig.Cookie = auth.DefaultCookieConfig()
+
+	// Create request
+	req := httptest.NewRequest("POST", "/api/users/logout", nil)
+
+	// Create response recorder
+	rr := httptest.NewRecorder()
+
+	// Call handler
+	handler.Logout(rr, req)
+
+	// Check response
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("Expected session and CSRF cookies to be cleared, got %d cookies", len(cookies))
+	}
+	for _, cookie := range cookies {
+		if cookie.MaxAge >= 0 {
+			t.Errorf("Expected cookie %s to be expired", cookie.Name)
+		}
+	}
+}
+
 func TestGetCurrentUser(t *testing.T) {
 	handler, testDB := setupTestHandler()
 
@@ -345,7 +519,7 @@ func TestGetTags(t *testing.T) {
 	rr := httptest.NewRecorder()
 
 	// Call handler
-	handler.GetTags(rr, req)
+	handler.GetTags(rr, req, api.GetTagsParams{})
 
 	// Check response
 	if rr.Code != http.StatusOK {
@@ -380,3 +554,317 @@ func TestGetTags(t *testing.T) {
 		t.Errorf("Missing tags: %v", expectedTags)
 	}
 }
+
+func TestGetTagsOrderAndCounts(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	author := api.Profile{Username: "author"}
+	now := time.Now()
+	for i, tags := range [][]string{{"go", "web"}, {"go", "rust"}, {"rust"}, {"go", "new"}} {
+		createdAt := now.Add(time.Duration(i) * time.Minute)
+		testDB.CreateArticle(api.Article{Title: "Article", Slug: "article-" + strconv.Itoa(i), Author: author, TagList: tags, CreatedAt: createdAt, UpdatedAt: createdAt})
+	}
+
+	getTags := func(params api.GetTagsParams) (*httptest.ResponseRecorder, api.TagsResponse) {
+		rr := httptest.NewRecorder()
+		handler.GetTags(rr, httptest.NewRequest("GET", "/api/tags", nil), params)
+		var resp api.TagsResponse
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+		}
+		return rr, resp
+	}
+
+	count, recent, unknown := db.TagOrderCount, db.TagOrderRecent, "random"
+	two, zero := 2, 0
+	withCounts := true
+	tests := []struct {
+		name     string
+		params   api.GetTagsParams
+		expected string
+	}{
+		{"Default", api.GetTagsParams{}, "go,rust,new,web"},
+		{"Count", api.GetTagsParams{Order: &count}, "go,rust,new,web"},
+		{"Recent", api.GetTagsParams{Order: &recent}, "go,new,rust,web"},
+		{"Limit", api.GetTagsParams{Order: &recent, Limit: &two, Counts: &withCounts}, "go,new"},
+	}
+
+	for _, tt := range tests {
+		rr, resp := getTags(tt.params)
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: expected status code %d, got %d", tt.name, http.StatusOK, rr.Code)
+			continue
+		}
+		if tags := strings.Join(resp.Tags, ","); tags != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, tags)
+		}
+		if (resp.TagCounts != nil) != (tt.params.Counts != nil) {
+			t.Errorf("%s: expected counts only when requested, got %v", tt.name, resp.TagCounts)
+		}
+	}
+
+	// Counts are in the order of the tags
+	_, resp := getTags(api.GetTagsParams{Counts: &withCounts})
+	expected := []api.TagCount{{Tag: "go", ArticlesCount: 3}, {Tag: "rust", ArticlesCount: 2}, {Tag: "new", ArticlesCount: 1}, {Tag: "web", ArticlesCount: 1}}
+	if resp.TagCounts == nil || !slices.Equal(*resp.TagCounts, expected) {
+		t.Errorf("Expected counts %v, got %v", expected, resp.TagCounts)
+	}
+
+	// Unknown orders and limits below 1 are rejected
+	for _, params := range []api.GetTagsParams{{Order: &unknown}, {Limit: &zero}} {
+		if rr, _ := getTags(params); rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	}
+}
+
+// createArticle calls CreateArticle for the given user and title and returns the response recorder
+func createArticle(handler *Handler, email, title string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.NewArticleRequest{Article: api.NewArticle{
+		Title:       title,
+		Description: "Description",
+		Body:        "Body",
+	}})
+	req := httptest.NewRequest("POST", "/api/articles", bytes.NewBuffer(body))
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.CreateArticle(rr, req)
+	return rr
+}
+
+func TestCreateArticle(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	// The same title gets increasing suffixes
+	expected := []string{"groesse-matters", "groesse-matters-1", "groesse-matters-2"}
+	for i := range expected {
+		rr := createArticle(handler, user.Email, "Größe matters")
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status code %d, got %d", http.StatusCreated, rr.Code)
+		}
+
+		var resp api.SingleArticleResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if resp.Article.Slug != expected[i] {
+			t.Errorf("Expected slug %s, got %s", expected[i], resp.Article.Slug)
+		}
+		if _, err := testDB.GetArticle(resp.Article.Slug); err != nil {
+			t.Errorf("Expected article %s to be stored, got %v", resp.Article.Slug, err)
+		}
+	}
+}
+
+func TestCreateArticleConcurrentSameTitle(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	// Many creates of the same title at once all succeed with distinct slugs
+	const creates = 50
+	var wg sync.WaitGroup
+	results := make(chan *httptest.ResponseRecorder, creates)
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- createArticle(handler, user.Email, "Same Title")
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	slugs := map[string]bool{}
+	for rr := range results {
+		if rr.Code != http.StatusCreated {
+			t.Errorf("Expected status code %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+			continue
+		}
+		var resp api.SingleArticleResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if slugs[resp.Article.Slug] {
+			t.Errorf("Slug %s was handed out twice", resp.Article.Slug)
+		}
+		slugs[resp.Article.Slug] = true
+	}
+
+	// The slugs are the base and the suffixes 1 to 49
+	if !slugs["same-title"] || !slugs["same-title-49"] || slugs["same-title-50"] {
+		t.Errorf("Expected same-title to same-title-49, got %d slugs", len(slugs))
+	}
+	if _, count, _ := testDB.ListArticles("", "", "", 100, 0); count != creates {
+		t.Errorf("Expected %d articles, got %d", creates, count)
+	}
+}
+
+// updateArticleTitle calls UpdateArticle for the given user, slug and title and returns the response recorder
+func updateArticleTitle(handler *Handler, email, slug, title string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.UpdateArticleJSONRequestBody{Article: api.UpdateArticle{Title: &title}})
+	req := httptest.NewRequest("PUT", "/api/articles/"+slug, bytes.NewBuffer(body))
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.UpdateArticle(rr, req, slug)
+	return rr
+}
+
+func TestUpdateArticle(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	other := setupRoleUser(t, testDB, "other", policy.RoleUser)
+	createArticle(handler, user.Email, "Old Title")
+
+	// Other users can't update the article
+	rr := updateArticleTitle(handler, other, "old-title", "Stolen Title")
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	// Empty titles are rejected
+	rr = updateArticleTitle(handler, user.Email, "old-title", " ")
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+
+	// A new title gives the article a new slug
+	rr = updateArticleTitle(handler, user.Email, "old-title", "New Title")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var resp api.SingleArticleResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Article.Slug != "new-title" || resp.Article.Title != "New Title" {
+		t.Errorf("Expected new-title with title New Title, got %s with %s", resp.Article.Slug, resp.Article.Title)
+	}
+
+	// The old slug is gone
+	rr = updateArticleTitle(handler, user.Email, "old-title", "Newer Title")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for the old slug, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+// updateArticleTags calls UpdateArticle for the given user, slug and tags and returns the response recorder
+func updateArticleTags(handler *Handler, email, slug string, tags []string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.UpdateArticleJSONRequestBody{Article: api.UpdateArticle{TagList: &tags}})
+	req := httptest.NewRequest("PUT", "/api/articles/"+slug, bytes.NewBuffer(body))
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.UpdateArticle(rr, req, slug)
+	return rr
+}
+
+func TestUpdateArticleTags(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	createArticle(handler, user.Email, "Tagged")
+
+	// Tags are normalized
+	rr := updateArticleTags(handler, user.Email, "tagged", []string{" Go ", "Web  Dev", "go"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var resp api.SingleArticleResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if strings.Join(resp.Article.TagList, ",") != "go,web dev" {
+		t.Errorf("Expected tags go and web dev, got %q", resp.Article.TagList)
+	}
+
+	// Too many or too long tags are rejected
+	tooMany := make([]string, util.MaxTags+1)
+	for i := range tooMany {
+		tooMany[i] = "tag" + strconv.Itoa(i)
+	}
+	for _, tags := range [][]string{tooMany, {strings.Repeat("a", util.MaxTagLength+1)}} {
+		if rr := updateArticleTags(handler, user.Email, "tagged", tags); rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	}
+
+	// Articles are found by their normalized tags
+	rr = httptest.NewRecorder()
+	tag := "Web Dev"
+	handler.GetArticles(rr, httptest.NewRequest("GET", "/api/articles", nil), api.GetArticlesParams{Tag: &tag})
+	var list api.MultipleArticlesResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if list.ArticlesCount != 1 {
+		t.Errorf("Expected 1 article tagged web dev, got %d", list.ArticlesCount)
+	}
+
+	// Removing all tags removes them from the tag list
+	if rr := updateArticleTags(handler, user.Email, "tagged", []string{}); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if tags := testDB.GetTags(); len(tags) != 0 {
+		t.Errorf("Expected no tags, got %v", tags)
+	}
+}
+
+func TestGetArticle(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	reader := setupRoleUser(t, testDB, "reader", policy.RoleUser)
+	createArticle(handler, user.Email, "Old Title")
+	if err := testDB.FavoriteArticle("old-title", "reader"); err != nil {
+		t.Fatalf("Failed to favorite article: %v", err)
+	}
+	updateArticleTitle(handler, user.Email, "old-title", "New Title")
+
+	get := func(slug string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/articles/"+slug, nil)
+		req = addUserToContext(req, reader)
+		rr := httptest.NewRecorder()
+		handler.GetArticle(rr, req, slug)
+		return rr
+	}
+
+	// The current slug returns the article for the viewer
+	rr := get("new-title")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp api.SingleArticleResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Article.Slug != "new-title" {
+		t.Errorf("Expected slug new-title, got %s", resp.Article.Slug)
+	}
+	if resp.Article.BodyHtml == nil || *resp.Article.BodyHtml != "<p>Body</p>\n" {
+		t.Errorf("Expected the body rendered to HTML, got %v", resp.Article.BodyHtml)
+	}
+	if !resp.Article.Favorited || resp.Article.FavoritesCount != 1 {
+		t.Errorf("Expected article favorited once by the reader, got %v and %d", resp.Article.Favorited, resp.Article.FavoritesCount)
+	}
+
+	// The old slug redirects permanently
+	rr = get("old-title")
+	if rr.Code != http.StatusMovedPermanently {
+		t.Errorf("Expected status code %d, got %d", http.StatusMovedPermanently, rr.Code)
+	}
+	if location := rr.Header().Get("Location"); location != "/api/articles/new-title" {
+		t.Errorf("Expected Location /api/articles/new-title, got %s", location)
+	}
+
+	// Unknown slugs are not found
+	if rr = get("unknown"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// Articles of suspended users are hidden
+	if err := testDB.SuspendUser(user.Email, "Spam", time.Now()); err != nil {
+		t.Fatalf("Failed to suspend user: %v", err)
+	}
+	if rr = get("new-title"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a suspended author, got %d", http.StatusNotFound, rr.Code)
+	}
+}
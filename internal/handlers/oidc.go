@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/oidc"
+	"github.com/denga/go-real-world-example/internal/util"
+)
+
+// oidcStateCookie binds a login at an external provider to the browser that
+// started it, so that a callback URL can't be used to log in someone else
+const oidcStateCookie = "conduit_oidc_state"
+
+// ListAuthProviders returns the external identity providers users can sign in with
+func (h *Handler) ListAuthProviders(w http.ResponseWriter, r *http.Request) {
+	// Prepare response
+	response := api.AuthProvidersResponse{
+		Providers: []api.AuthProvider{},
+	}
+	for _, provider := range h.Providers {
+		response.Providers = append(response.Providers, api.AuthProvider{
+			Name:        provider.Name(),
+			DisplayName: provider.DisplayName(),
+		})
+	}
+	sort.Slice(response.Providers, func(i, j int) bool {
+		return response.Providers[i].Name < response.Providers[j].Name
+	})
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// OidcLogin redirects the browser to an external identity provider
+func (h *Handler) OidcLogin(w http.ResponseWriter, r *http.Request, provider string) {
+	p, exists := h.Providers[provider]
+	if !exists {
+		http.Error(w, "Unknown identity provider", http.StatusNotFound)
+		return
+	}
+
+	authURL, state, err := p.Begin(r.Context())
+	if errors.Is(err, oidc.ErrTooManyLogins) {
+		http.Error(w, "Too many logins in progress, try again later", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		log.Printf("Error starting login with %s: %v", provider, err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	// SameSite=Lax cookies are still sent on the top-level redirect back
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/auth/",
+		MaxAge:   600,
+		Secure:   h.AuthConfig.Cookie.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OidcCallback completes a login at an external identity provider. The user
+// is sent back to the frontend login page, which picks up the result from
+// the URL fragment.
+func (h *Handler) OidcCallback(w http.ResponseWriter, r *http.Request, provider string, params api.OidcCallbackParams) {
+	p, exists := h.Providers[provider]
+	if !exists {
+		http.Error(w, "Unknown identity provider", http.StatusNotFound)
+		return
+	}
+
+	// The state cookie has done its job
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    "",
+		Path:     "/api/auth/",
+		MaxAge:   -1,
+		Secure:   h.AuthConfig.Cookie.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if params.Error != nil {
+		h.redirectToLogin(w, r, url.Values{"error": {"Sign in was cancelled or denied"}})
+		return
+	}
+	if params.Code == nil || params.State == nil {
+		h.redirectToLogin(w, r, url.Values{"error": {"Invalid sign in response"}})
+		return
+	}
+
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(*params.State)) != 1 {
+		h.redirectToLogin(w, r, url.Values{"error": {"Sign in expired, please try again"}})
+		return
+	}
+
+	identity, err := p.Finish(r.Context(), *params.State, *params.Code)
+	if err != nil {
+		log.Printf("Error completing login with %s: %v", provider, err)
+		h.redirectToLogin(w, r, url.Values{"error": {"Sign in failed, please try again"}})
+		return
+	}
+
+	email, err := h.resolveIdentity(provider, identity)
+	if err != nil {
+		h.redirectToLogin(w, r, url.Values{"error": {err.Error()}})
+		return
+	}
+
+	// Local two-factor authentication still applies
+	user, err := h.DB.GetInternalUserByEmail(email)
+	if err != nil {
+		h.redirectToLogin(w, r, url.Values{"error": {"Sign in failed, please try again"}})
+		return
+	}
+	if user.Suspended() {
+		h.redirectToLogin(w, r, url.Values{"error": {"Your account is suspended"}})
+		return
+	}
+	if user.TwoFactorEnabled() {
+		challenge, _, err := auth.GenerateChallengeToken(email, h.AuthConfig)
+		if err != nil {
+			h.redirectToLogin(w, r, url.Values{"error": {"Sign in failed, please try again"}})
+			return
+		}
+		h.redirectToLogin(w, r, url.Values{"challenge": {challenge}})
+		return
+	}
+
+	token, err := auth.GenerateToken(email, h.AuthConfig)
+	if err != nil {
+		h.redirectToLogin(w, r, url.Values{"error": {"Sign in failed, please try again"}})
+		return
+	}
+
+	// In cookie mode the token never reaches the browser's scripts
+	if h.AuthConfig.Mode == auth.ModeCookie {
+		if err := auth.SetSessionCookies(w, token, h.AuthConfig); err != nil {
+			h.redirectToLogin(w, r, url.Values{"error": {"Sign in failed, please try again"}})
+			return
+		}
+		h.redirectToLogin(w, r, url.Values{"session": {"1"}})
+		return
+	}
+
+	h.redirectToLogin(w, r, url.Values{"token": {token}})
+}
+
+// redirectToLogin sends the browser to the frontend login page. Values are
+// passed in the fragment, which browsers don't send to servers or in Referer
+// headers.
+func (h *Handler) redirectToLogin(w http.ResponseWriter, r *http.Request, values url.Values) {
+	target := strings.TrimRight(h.AppURL, "/") + "/login#" + values.Encode()
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// identityError is an error that can be shown to the user
+type identityError string
+
+func (e identityError) Error() string {
+	return string(e)
+}
+
+// resolveIdentity finds the user for an external identity. Unknown identities
+// are linked to the user with the same email address if both the provider and
+// the user verified it, or a new user is created.
+func (h *Handler) resolveIdentity(provider string, identity *oidc.Identity) (string, error) {
+	// Identity already linked
+	if email, err := h.DB.GetEmailByIdentity(provider, identity.Subject); err == nil {
+		return email, nil
+	}
+
+	// Only addresses the provider vouches for may take over an existing account
+	if identity.Email == "" || !identity.EmailVerified {
+		return "", identityError("Your account at the identity provider has no verified email address")
+	}
+
+	user, err := h.DB.GetInternalUserByEmail(identity.Email)
+	switch {
+	case err == db.ErrNotFound:
+		if err := h.createIdentityUser(identity); err != nil {
+			log.Printf("Error creating user for %s identity: %v", provider, err)
+			return "", identityError("Could not create your account, please try again")
+		}
+	case err != nil:
+		return "", identityError("Could not link your account, please try again")
+	case !user.EmailVerified:
+		// Anyone could have registered the address without owning it, and
+		// would keep access through the password if it was linked
+		return "", identityError("An account with this email address exists, please sign in with your password and verify your email address first")
+	}
+
+	if err := h.DB.LinkIdentity(provider, identity.Subject, identity.Email); err != nil {
+		log.Printf("Error linking %s identity: %v", provider, err)
+		return "", identityError("Could not link your account, please try again")
+	}
+
+	return identity.Email, nil
+}
+
+// createIdentityUser creates a user for an external identity. The user gets a
+// random password, so they can only sign in through the provider until they
+// reset it.
+func (h *Handler) createIdentityUser(identity *oidc.Identity) error {
+	password, err := auth.GenerateOneTimeToken()
+	if err != nil {
+		return err
+	}
+
+	user := api.User{
+		Username: h.uniqueUsername(identity.PreferredUsername, identity.Name, strings.Split(identity.Email, "@")[0]),
+		Email:    identity.Email,
+	}
+	if err := h.DB.CreateUser(user, password); err != nil {
+		return err
+	}
+
+	return h.markEmailVerified(user.Email)
+}
+
+// uniqueUsername derives an unused username from the first usable candidate
+func (h *Handler) uniqueUsername(candidates ...string) string {
+	base := "user"
+	for _, candidate := range candidates {
+		if slug := util.Slugify(candidate); slug != "" {
+			base = slug
+			break
+		}
+	}
+
+	username := base
+	for i := 2; ; i++ {
+		if _, err := h.DB.GetUserByUsername(username); err == db.ErrNotFound {
+			return username
+		}
+		username = base + "-" + strconv.Itoa(i)
+	}
+}
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/oidc"
+	"github.com/denga/go-real-world-example/internal/oidc/oidctest"
+)
+
+// setupOIDCHandler creates a handler with a stand-in identity provider named "company"
+func setupOIDCHandler(t *testing.T) (*Handler, *oidctest.Server) {
+	t.Helper()
+
+	handler, _ := setupTestHandler()
+
+	server := oidctest.NewServer("conduit", "client-secret")
+	t.Cleanup(server.Close)
+
+	handler.Providers["company"] = oidc.NewProvider(oidc.Config{
+		Name:         "company",
+		DisplayName:  "Company SSO",
+		Issuer:       server.Issuer(),
+		ClientID:     "conduit",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:8080/api/auth/company/callback",
+	}, server.Client())
+
+	return handler, server
+}
+
+// oidcLogin runs a complete sign in through the stand-in provider and
+// returns the response of the callback and the values in the fragment of
+// the redirect to the frontend
+func oidcLogin(t *testing.T, handler *Handler, server *oidctest.Server) (*httptest.ResponseRecorder, url.Values) {
+	t.Helper()
+
+	// Start the login
+	req := httptest.NewRequest("GET", "/api/auth/company/login", nil)
+	rr := httptest.NewRecorder()
+	handler.OidcLogin(rr, req, "company")
+	if rr.Code != http.StatusFound {
+		t.Fatalf("Expected status code %d, got %d", http.StatusFound, rr.Code)
+	}
+
+	// Sign in at the provider
+	callback, err := server.Authorize(rr.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("Failed to authorize: %v", err)
+	}
+
+	// Come back with the state cookie
+	req = httptest.NewRequest("GET", callback.String(), nil)
+	for _, cookie := range rr.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	return oidcCallback(t, handler, req)
+}
+
+// oidcCallback calls OidcCallback with the query parameters of the request
+func oidcCallback(t *testing.T, handler *Handler, req *http.Request) (*httptest.ResponseRecorder, url.Values) {
+	t.Helper()
+
+	var params api.OidcCallbackParams
+	query := req.URL.Query()
+	if query.Has("code") {
+		code := query.Get("code")
+		params.Code = &code
+	}
+	if query.Has("state") {
+		state := query.Get("state")
+		params.State = &state
+	}
+
+	rr := httptest.NewRecorder()
+	handler.OidcCallback(rr, req, "company", params)
+	if rr.Code != http.StatusFound {
+		t.Fatalf("Expected status code %d, got %d", http.StatusFound, rr.Code)
+	}
+
+	location, err := url.Parse(rr.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("Failed to parse redirect: %v", err)
+	}
+	if location.Path != "/login" {
+		t.Fatalf("Expected redirect to /login, got %s", location.Path)
+	}
+	values, _ := url.ParseQuery(location.Fragment)
+
+	return rr, values
+}
+
+// setupVerifiedUser creates the test user with a verified email address
+func setupVerifiedUser(t *testing.T, handler *Handler) api.User {
+	t.Helper()
+
+	user, _ := setupTestUser(handler.DB, handler.AuthConfig)
+	if err := handler.DB.SetEmailVerified(user.Email); err != nil {
+		t.Fatalf("Failed to verify email: %v", err)
+	}
+	return user
+}
+
+func TestListAuthProviders(t *testing.T) {
+	handler, _ := setupOIDCHandler(t)
+
+	req := httptest.NewRequest("GET", "/api/auth/providers", nil)
+	rr := httptest.NewRecorder()
+	handler.ListAuthProviders(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp api.AuthProvidersResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Providers) != 1 || resp.Providers[0].Name != "company" || resp.Providers[0].DisplayName != "Company SSO" {
+		t.Errorf("Unexpected providers: %+v", resp.Providers)
+	}
+}
+
+func TestOidcLoginUnknownProvider(t *testing.T) {
+	handler, _ := setupOIDCHandler(t)
+
+	req := httptest.NewRequest("GET", "/api/auth/other/login", nil)
+	rr := httptest.NewRecorder()
+	handler.OidcLogin(rr, req, "other")
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestOidcLoginCreatesUser(t *testing.T) {
+	handler, server := setupOIDCHandler(t)
+	server.SetUser(oidctest.User{
+		Subject:           "sub-1",
+		Email:             "jane@example.com",
+		EmailVerified:     true,
+		PreferredUsername: "Jane",
+	})
+
+	_, values := oidcLogin(t, handler, server)
+
+	// The frontend receives a normal Conduit token
+	email, err := auth.ValidateToken(values.Get("token"), handler.AuthConfig)
+	if err != nil || email != "jane@example.com" {
+		t.Fatalf("Expected token for jane@example.com, got %s, %v", email, err)
+	}
+
+	// The user was created with a verified email
+	user, err := handler.DB.GetInternalUserByEmail("jane@example.com")
+	if err != nil {
+		t.Fatalf("Expected user to be created: %v", err)
+	}
+	if user.Username != "jane" {
+		t.Errorf("Expected username jane, got %s", user.Username)
+	}
+	if !user.EmailVerified {
+		t.Error("Expected email to be verified")
+	}
+
+	// The identity is linked, so a changed email at the provider still finds the user
+	server.SetUser(oidctest.User{Subject: "sub-1", Email: "jane.doe@example.com", EmailVerified: true})
+	_, values = oidcLogin(t, handler, server)
+	if email, _ := auth.ValidateToken(values.Get("token"), handler.AuthConfig); email != "jane@example.com" {
+		t.Errorf("Expected linked user jane@example.com, got %s", email)
+	}
+}
+
+func TestOidcLoginLinksExistingUser(t *testing.T) {
+	handler, server := setupOIDCHandler(t)
+	user := setupVerifiedUser(t, handler)
+	server.SetUser(oidctest.User{Subject: "sub-1", Email: user.Email, EmailVerified: true, PreferredUsername: "other"})
+
+	_, values := oidcLogin(t, handler, server)
+
+	if email, _ := auth.ValidateToken(values.Get("token"), handler.AuthConfig); email != user.Email {
+		t.Errorf("Expected token for %s, got %s", user.Email, email)
+	}
+	if linked, _ := handler.DB.GetEmailByIdentity("company", "sub-1"); linked != user.Email {
+		t.Errorf("Expected identity to be linked to %s, got %s", user.Email, linked)
+	}
+}
+
+func TestOidcLoginRequiresVerifiedLocalEmail(t *testing.T) {
+	handler, server := setupOIDCHandler(t)
+	user, _ := setupTestUser(handler.DB, handler.AuthConfig)
+
+	// Whoever registered the unverified address keeps their password, so
+	// the owner of the address must not be signed into their account
+	server.SetUser(oidctest.User{Subject: "sub-1", Email: user.Email, EmailVerified: true})
+
+	_, values := oidcLogin(t, handler, server)
+
+	if values.Get("token") != "" || values.Get("error") == "" {
+		t.Errorf("Expected an error, got %v", values)
+	}
+	if _, err := handler.DB.GetEmailByIdentity("company", "sub-1"); err == nil {
+		t.Error("Expected identity not to be linked")
+	}
+
+	// Once the address is verified the identity is linked
+	handler.DB.SetEmailVerified(user.Email)
+	_, values = oidcLogin(t, handler, server)
+	if email, _ := auth.ValidateToken(values.Get("token"), handler.AuthConfig); email != user.Email {
+		t.Errorf("Expected token for %s, got %s", user.Email, email)
+	}
+}
+
+func TestOidcLoginSuspendedUser(t *testing.T) {
+	handler, server := setupOIDCHandler(t)
+	user := setupVerifiedUser(t, handler)
+	handler.DB.SuspendUser(user.Email, "", time.Now())
+	server.SetUser(oidctest.User{Subject: "sub-1", Email: user.Email, EmailVerified: true})
+
+	_, values := oidcLogin(t, handler, server)
+
+	if values.Get("token") != "" || values.Get("error") != "Your account is suspended" {
+		t.Errorf("Expected suspension error, got %v", values)
+	}
+}
+
+func TestOidcLoginRequiresVerifiedEmail(t *testing.T) {
+	handler, server := setupOIDCHandler(t)
+	user, _ := setupTestUser(handler.DB, handler.AuthConfig)
+
+	// An unverified address must not take over the existing account
+	server.SetUser(oidctest.User{Subject: "sub-1", Email: user.Email, EmailVerified: false})
+
+	_, values := oidcLogin(t, handler, server)
+
+	if values.Get("token") != "" || values.Get("error") == "" {
+		t.Errorf("Expected an error, got %v", values)
+	}
+	if _, err := handler.DB.GetEmailByIdentity("company", "sub-1"); err == nil {
+		t.Error("Expected identity not to be linked")
+	}
+}
+
+func TestOidcCallbackRequiresStateCookie(t *testing.T) {
+	handler, server := setupOIDCHandler(t)
+
+	// Start a login, but come back without the cookie, like a victim lured
+	// to an attacker's callback URL
+	req := httptest.NewRequest("GET", "/api/auth/company/login", nil)
+	rr := httptest.NewRecorder()
+	handler.OidcLogin(rr, req, "company")
+	callback, err := server.Authorize(rr.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("Failed to authorize: %v", err)
+	}
+
+	_, values := oidcCallback(t, handler, httptest.NewRequest("GET", callback.String(), nil))
+
+	if values.Get("token") != "" || values.Get("error") == "" {
+		t.Errorf("Expected an error, got %v", values)
+	}
+}
+
+func TestOidcLoginWithTwoFactor(t *testing.T) {
+	handler, server := setupOIDCHandler(t)
+	user := setupVerifiedUser(t, handler)
+	setupTwoFactorUser(t, handler, user.Email)
+	server.SetUser(oidctest.User{Subject: "sub-1", Email: user.Email, EmailVerified: true})
+
+	_, values := oidcLogin(t, handler, server)
+
+	// Only a challenge is handed out
+	if values.Get("token") != "" {
+		t.Error("Expected no token for 2FA account")
+	}
+	if email, err := auth.ValidateChallengeToken(values.Get("challenge"), handler.AuthConfig); err != nil || email != user.Email {
+		t.Errorf("Expected challenge for %s, got %s, %v", user.Email, email, err)
+	}
+}
+
+func TestOidcLoginWithCookieMode(t *testing.T) {
+	handler, server := setupOIDCHandler(t)
+	handler.AuthConfig.Mode = auth.ModeCookie
+	handler.AuthConfig.Cookie = auth.DefaultCookieConfig()
+
+	rr, values := oidcLogin(t, handler, server)
+
+	if values.Get("token") != "" || values.Get("session") != "1" {
+		t.Errorf("Expected session without token in the URL, got %v", values)
+	}
+
+	found := false
+	for _, cookie := range rr.Result().Cookies() {
+		if cookie.Name == handler.AuthConfig.Cookie.SessionName && cookie.Value != "" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a session cookie")
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/diff"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// ListArticleRevisions lists the revisions of an article, oldest first
+func (h *Handler) ListArticleRevisions(w http.ResponseWriter, r *http.Request, slug string) {
+	if _, ok := h.visibleArticle(w, r, slug); !ok {
+		return
+	}
+
+	revisions, err := h.DB.ListRevisions(slug)
+	if err != nil {
+		http.Error(w, "Error retrieving revisions", http.StatusInternalServerError)
+		return
+	}
+
+	// Prepare response
+	response := api.ArticleRevisionsResponse{
+		Revisions:      make([]api.ArticleRevisionSummary, len(revisions)),
+		RevisionsCount: len(revisions),
+	}
+	for i, revision := range revisions {
+		response.Revisions[i] = api.ArticleRevisionSummary{
+			Number:    revision.Number,
+			Title:     revision.Title,
+			Author:    revision.Author,
+			CreatedAt: revision.CreatedAt,
+		}
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetArticleRevision returns a revision of an article
+func (h *Handler) GetArticleRevision(w http.ResponseWriter, r *http.Request, slug string, number int) {
+	if _, ok := h.visibleArticle(w, r, slug); !ok {
+		return
+	}
+
+	revision, err := h.DB.GetRevision(slug, number)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Revision not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving revision", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Prepare response
+	response := api.SingleArticleRevisionResponse{
+		Revision: *revision,
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetArticleRevisionDiff compares a revision of an article with an earlier
+// one, by default the previous revision. Revision 0 is an empty article, so
+// the first revision is compared with nothing.
+func (h *Handler) GetArticleRevisionDiff(w http.ResponseWriter, r *http.Request, slug string, number int, params api.GetArticleRevisionDiffParams) {
+	if _, ok := h.visibleArticle(w, r, slug); !ok {
+		return
+	}
+
+	from := number - 1
+	if params.From != nil {
+		from = *params.From
+	}
+	if from < 0 || from >= number {
+		http.Error(w, "Can only compare with an earlier revision", http.StatusUnprocessableEntity)
+		return
+	}
+
+	to, err := h.DB.GetRevision(slug, number)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Revision not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving revision", http.StatusInternalServerError)
+		}
+		return
+	}
+	base := &api.ArticleRevision{}
+	if from > 0 {
+		// Earlier revisions exist when a later one does
+		if base, err = h.DB.GetRevision(slug, from); err != nil {
+			http.Error(w, "Error retrieving revision", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Compare the fields, refusing texts too long to compare quickly
+	var fields [3][]api.DiffLine
+	for i, texts := range [3][2]string{{base.Title, to.Title}, {base.Description, to.Description}, {base.Body, to.Body}} {
+		lines, err := diff.Lines(texts[0], texts[1])
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Can only compare revisions of up to %d lines", diff.MaxLines), http.StatusUnprocessableEntity)
+			return
+		}
+		fields[i] = toAPIDiff(lines)
+	}
+
+	// Prepare response
+	response := api.RevisionDiffResponse{
+		Diff: api.RevisionDiff{
+			From:        from,
+			To:          number,
+			Title:       fields[0],
+			Description: fields[1],
+			Body:        fields[2],
+		},
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// toAPIDiff converts diff lines for responses
+func toAPIDiff(lines []diff.Line) []api.DiffLine {
+	converted := make([]api.DiffLine, len(lines))
+	for i, line := range lines {
+		converted[i] = api.DiffLine{Op: string(line.Op), Text: line.Text}
+	}
+	return converted
+}
+
+// RestoreArticleRevision restores the content of an earlier revision of an
+// article as a new revision. Only the author can restore revisions.
+func (h *Handler) RestoreArticleRevision(w http.ResponseWriter, r *http.Request, slug string, number int) {
+	// Get article from database
+	article, err := h.DB.GetArticle(slug)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Article not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving article", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	user, ok := h.authorize(w, r, policy.UpdateArticle, article.Author.Username)
+	if !ok {
+		return
+	}
+
+	restored, err := h.DB.RestoreRevision(slug, number, user.Username)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Revision not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error restoring revision", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Prepare response
+	response := api.SingleArticleResponse{
+		Article: h.articleResponse(r, *restored),
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/diff"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// updateArticleBody calls UpdateArticle for the given user, slug and body and returns the response recorder
+func updateArticleBody(handler *Handler, email, slug, body string) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(api.UpdateArticleJSONRequestBody{Article: api.UpdateArticle{Body: &body}})
+	req := httptest.NewRequest("PUT", "/api/articles/"+slug, bytes.NewBuffer(payload))
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.UpdateArticle(rr, req, slug)
+	return rr
+}
+
+func TestListArticleRevisions(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	createArticle(handler, user.Email, "Revised")
+	updateArticleBody(handler, user.Email, "revised", "Second body")
+
+	req := httptest.NewRequest("GET", "/api/articles/revised/revisions", nil)
+	req = addUserToContext(req, user.Email)
+	rr := httptest.NewRecorder()
+	handler.ListArticleRevisions(rr, req, "revised")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp api.ArticleRevisionsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.RevisionsCount != 2 || resp.Revisions[0].Number != 1 || resp.Revisions[1].Number != 2 {
+		t.Errorf("Expected revisions 1 and 2, got %+v", resp)
+	}
+	if resp.Revisions[1].Author.Username != user.Username {
+		t.Errorf("Expected revision by %s, got %s", user.Username, resp.Revisions[1].Author.Username)
+	}
+
+	// Unknown articles have no revisions
+	rr = httptest.NewRecorder()
+	handler.ListArticleRevisions(rr, req, "unknown")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestGetArticleRevision(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	createArticle(handler, user.Email, "Revised")
+	updateArticleBody(handler, user.Email, "revised", "Second body")
+
+	tests := []struct {
+		number       int
+		expected     int
+		expectedBody string
+	}{
+		{1, http.StatusOK, "Body"},
+		{2, http.StatusOK, "Second body"},
+		{3, http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/articles/revised/revisions", nil)
+		req = addUserToContext(req, user.Email)
+		rr := httptest.NewRecorder()
+		handler.GetArticleRevision(rr, req, "revised", tt.number)
+
+		if rr.Code != tt.expected {
+			t.Errorf("Revision %d: expected status code %d, got %d", tt.number, tt.expected, rr.Code)
+			continue
+		}
+		if tt.expected != http.StatusOK {
+			continue
+		}
+		var resp api.SingleArticleRevisionResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if resp.Revision.Body != tt.expectedBody {
+			t.Errorf("Revision %d: expected body %q, got %q", tt.number, tt.expectedBody, resp.Revision.Body)
+		}
+	}
+}
+
+func TestGetArticleRevisionDiff(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	createArticle(handler, user.Email, "Revised")
+	updateArticleBody(handler, user.Email, "revised", "Body\nMore")
+	updateArticleBody(handler, user.Email, "revised", "More")
+
+	diffRevisions := func(number int, from *int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/articles/revised/revisions/diff", nil)
+		req = addUserToContext(req, user.Email)
+		rr := httptest.NewRecorder()
+		handler.GetArticleRevisionDiff(rr, req, "revised", number, api.GetArticleRevisionDiffParams{From: from})
+		return rr
+	}
+
+	first, third := 1, 3
+	tests := []struct {
+		name     string
+		number   int
+		from     *int
+		expected []api.DiffLine
+	}{
+		{"Previous revision", 3, nil, []api.DiffLine{{Op: "delete", Text: "Body"}, {Op: "equal", Text: "More"}}},
+		{"Earlier revision", 3, &first, []api.DiffLine{{Op: "delete", Text: "Body"}, {Op: "insert", Text: "More"}}},
+		{"First revision", 1, nil, []api.DiffLine{{Op: "insert", Text: "Body"}}},
+	}
+
+	for _, tt := range tests {
+		rr := diffRevisions(tt.number, tt.from)
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: expected status code %d, got %d", tt.name, http.StatusOK, rr.Code)
+			continue
+		}
+		var resp api.RevisionDiffResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if len(resp.Diff.Body) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, resp.Diff.Body)
+			continue
+		}
+		for i, line := range resp.Diff.Body {
+			if line != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, resp.Diff.Body)
+				break
+			}
+		}
+		if resp.Diff.From != tt.number-1 && tt.from == nil {
+			t.Errorf("%s: expected comparison with revision %d, got %d", tt.name, tt.number-1, resp.Diff.From)
+		}
+	}
+
+	// Only earlier revisions can be compared
+	if rr := diffRevisions(2, &third); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+	if rr := diffRevisions(4, nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// Texts too long to compare are refused
+	updateArticleBody(handler, user.Email, "revised", strings.Repeat("line\n", diff.MaxLines+1))
+	if rr := diffRevisions(4, nil); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+}
+
+func TestRestoreArticleRevision(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	admin := setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+	createArticle(handler, user.Email, "Old Title")
+	updateArticleTitle(handler, user.Email, "old-title", "New Title")
+
+	restore := func(email, slug string, number int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/articles/"+slug+"/revisions/restore", nil)
+		req = addUserToContext(req, email)
+		rr := httptest.NewRecorder()
+		handler.RestoreArticleRevision(rr, req, slug, number)
+		return rr
+	}
+
+	// Only the author can restore revisions
+	if rr := restore(admin, "new-title", 1); rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	if rr := restore(user.Email, "new-title", 5); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// Restoring the first revision brings back its title and slug
+	rr := restore(user.Email, "new-title", 1)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var resp api.SingleArticleResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Article.Title != "Old Title" || resp.Article.Slug != "old-title" {
+		t.Errorf("Expected Old Title under old-title, got %s under %s", resp.Article.Title, resp.Article.Slug)
+	}
+	if revisions, _ := testDB.ListRevisions("old-title"); len(revisions) != 3 {
+		t.Errorf("Expected 3 revisions, got %d", len(revisions))
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/middleware"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// authorize checks that the authenticated user may perform action on a
+// resource owned by the user with the given username, and writes an error
+// response if not. It returns the authenticated user on success.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request, action policy.Action, owner string) (*db.InternalUser, bool) {
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	// Get user from database
+	user, err := h.DB.GetInternalUserByEmail(email)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving user", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	actor := policy.Actor{Username: user.Username, Role: user.Role}
+	if !policy.Can(actor, action, owner) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// markEmailVerified marks the email address of a user as verified. Accounts
+// configured as bootstrap admins get the admin role once they proved that
+// they own the address.
+func (h *Handler) markEmailVerified(email string) error {
+	if err := h.DB.SetEmailVerified(email); err != nil {
+		return err
+	}
+
+	for _, admin := range h.AuthConfig.AdminEmails {
+		if strings.EqualFold(admin, email) {
+			if err := h.DB.SetUserRole(email, policy.RoleAdmin); err != nil {
+				return err
+			}
+			log.Printf("Granted admin role to bootstrap admin %s", email)
+			break
+		}
+	}
+
+	return nil
+}
+
+// SetUserRole changes the role of a user
+func (h *Handler) SetUserRole(w http.ResponseWriter, r *http.Request, username string) {
+	if _, ok := h.authorize(w, r, policy.ManageRoles, ""); !ok {
+		return
+	}
+
+	// Parse request body
+	var request api.SetUserRoleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	role, ok := policy.ParseRole(request.User.Role)
+	if !ok || request.User.Role == "" {
+		http.Error(w, "Unknown role", http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Get user from database
+	user, err := h.DB.GetUserByUsername(username)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving user", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := h.DB.SetUserRole(user.Email, role); err != nil {
+		switch err {
+		case db.ErrNotFound:
+			http.Error(w, "User not found", http.StatusNotFound)
+		case db.ErrConflict:
+			http.Error(w, "The last admin can't be demoted", http.StatusConflict)
+		default:
+			http.Error(w, "Error setting role", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// setupRoleUser creates a user with the given role and returns their email
+func setupRoleUser(t *testing.T, testDB *db.InMemoryDB, username string, role policy.Role) string {
+	t.Helper()
+
+	email := username + "@example.com"
+	if err := testDB.CreateUser(api.User{Username: username, Email: email}, "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := testDB.SetUserRole(email, role); err != nil {
+		t.Fatalf("Failed to set role: %v", err)
+	}
+	return email
+}
+
+// setupArticleWithComment creates an article and a comment on it by the given users
+func setupArticleWithComment(t *testing.T, testDB *db.InMemoryDB, slug, articleAuthor, commentAuthor string) int {
+	t.Helper()
+
+	article := api.Article{
+		Slug:      slug,
+		Title:     "Test Article",
+		Author:    api.Profile{Username: articleAuthor},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := testDB.CreateArticle(article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	id, err := testDB.AddComment(slug, api.Comment{Body: "Test comment", Author: api.Profile{Username: commentAuthor}})
+	if err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	return id
+}
+
+func TestDeleteArticlePermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    string
+		role     policy.Role
+		expected int
+	}{
+		{name: "Author", actor: "author", role: policy.RoleUser, expected: http.StatusOK},
+		{name: "Other user", actor: "other", role: policy.RoleUser, expected: http.StatusForbidden},
+		{name: "Moderator", actor: "other", role: policy.RoleModerator, expected: http.StatusForbidden},
+		{name: "Admin", actor: "other", role: policy.RoleAdmin, expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, testDB := setupTestHandler()
+			setupRoleUser(t, testDB, "author", policy.RoleUser)
+			if tt.actor != "author" {
+				setupRoleUser(t, testDB, tt.actor, tt.role)
+			}
+			setupArticleWithComment(t, testDB, "test-article", "author", "author")
+
+			req := httptest.NewRequest("DELETE", "/api/articles/test-article", nil)
+			req = addUserToContext(req, tt.actor+"@example.com")
+			rr := httptest.NewRecorder()
+			handler.DeleteArticle(rr, req, "test-article")
+
+			if rr.Code != tt.expected {
+				t.Errorf("Expected status code %d, got %d", tt.expected, rr.Code)
+			}
+
+			// Check whether the article is gone
+			_, err := testDB.GetArticle("test-article")
+			if deleted := err == db.ErrNotFound; deleted != (tt.expected == http.StatusOK) {
+				t.Errorf("Expected article deleted to be %v", tt.expected == http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestDeleteArticleCommentPermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    string
+		role     policy.Role
+		expected int
+	}{
+		{name: "Comment author", actor: "commenter", role: policy.RoleUser, expected: http.StatusOK},
+		{name: "Article author", actor: "author", role: policy.RoleUser, expected: http.StatusForbidden},
+		{name: "Other user", actor: "other", role: policy.RoleUser, expected: http.StatusForbidden},
+		{name: "Moderator", actor: "other", role: policy.RoleModerator, expected: http.StatusOK},
+		{name: "Admin", actor: "other", role: policy.RoleAdmin, expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, testDB := setupTestHandler()
+			setupRoleUser(t, testDB, "author", policy.RoleUser)
+			setupRoleUser(t, testDB, "commenter", policy.RoleUser)
+			if tt.actor == "other" {
+				setupRoleUser(t, testDB, tt.actor, tt.role)
+			}
+			id := setupArticleWithComment(t, testDB, "test-article", "author", "commenter")
+
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/articles/test-article/comments/%d", id), nil)
+			req = addUserToContext(req, tt.actor+"@example.com")
+			rr := httptest.NewRecorder()
+			handler.DeleteArticleComment(rr, req, "test-article", id)
+
+			if rr.Code != tt.expected {
+				t.Errorf("Expected status code %d, got %d", tt.expected, rr.Code)
+			}
+
+			// Check whether the comment is gone
+			_, err := testDB.GetComment("test-article", id)
+			if deleted := err == db.ErrNotFound; deleted != (tt.expected == http.StatusOK) {
+				t.Errorf("Expected comment deleted to be %v", tt.expected == http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestDeleteUnknownArticle(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	email := setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+
+	req := httptest.NewRequest("DELETE", "/api/articles/unknown", nil)
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.DeleteArticle(rr, req, "unknown")
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+// setUserRole calls SetUserRole as the given user
+func setUserRole(handler *Handler, actor, username, role string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.RoleAssignmentRequest{User: api.RoleAssignment{Role: role}})
+	req := httptest.NewRequest("PUT", "/api/admin/users/"+username+"/role", bytes.NewBuffer(body))
+	req = addUserToContext(req, actor)
+	rr := httptest.NewRecorder()
+	handler.SetUserRole(rr, req, username)
+	return rr
+}
+
+func TestSetUserRole(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	admin := setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+	moderator := setupRoleUser(t, testDB, "moderator", policy.RoleModerator)
+	user := setupRoleUser(t, testDB, "user", policy.RoleUser)
+
+	// Only admins can change roles
+	if rr := setUserRole(handler, moderator, "user", "admin"); rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d for moderator, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	// Promote a user
+	if rr := setUserRole(handler, admin, "user", "moderator"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	internalUser, _ := testDB.GetInternalUserByEmail(user)
+	if internalUser.Role != policy.RoleModerator {
+		t.Errorf("Expected role %q, got %q", policy.RoleModerator, internalUser.Role)
+	}
+
+	// Invalid roles and unknown users
+	if rr := setUserRole(handler, admin, "user", "root"); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d for unknown role, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+	if rr := setUserRole(handler, admin, "nobody", "user"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for unknown user, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// The last admin can't demote themselves
+	if rr := setUserRole(handler, admin, "admin", "user"); rr.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d for last admin, got %d", http.StatusConflict, rr.Code)
+	}
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	handler, _ := setupTestHandler()
+	handler.AuthConfig.AdminEmails = []string{"Boss@example.com"}
+
+	// Register the bootstrap admin
+	body, _ := json.Marshal(api.NewUserRequest{User: api.NewUser{Username: "boss", Email: "boss@example.com", Password: "password123"}})
+	req := httptest.NewRequest("POST", "/api/users", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.CreateUser(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", http.StatusCreated, rr.Code)
+	}
+
+	// The role is only granted once the address is verified
+	user, _ := handler.DB.GetInternalUserByEmail("boss@example.com")
+	if user.Role != policy.RoleUser {
+		t.Errorf("Expected role %q before verification, got %q", policy.RoleUser, user.Role)
+	}
+
+	_, token := lastMailLink(t, handler, "boss@example.com")
+	if rr := verifyEmail(handler, token); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	user, _ = handler.DB.GetInternalUserByEmail("boss@example.com")
+	if user.Role != policy.RoleAdmin {
+		t.Errorf("Expected role %q after verification, got %q", policy.RoleAdmin, user.Role)
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/util"
+)
+
+// FollowTag makes the current user follow a tag, so that its articles show
+// up in their feed
+func (h *Handler) FollowTag(w http.ResponseWriter, r *http.Request, tag string) {
+	h.setTagFollow(w, r, tag, true)
+}
+
+// UnfollowTag makes the current user unfollow a tag
+func (h *Handler) UnfollowTag(w http.ResponseWriter, r *http.Request, tag string) {
+	h.setTagFollow(w, r, tag, false)
+}
+
+// setTagFollow follows or unfollows a tag, normalized like the tags of
+// articles, for the current user
+func (h *Handler) setTagFollow(w http.ResponseWriter, r *http.Request, tag string, follow bool) {
+	username := h.viewer(r)
+	if username == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tags, ok := normalizeTags(w, []string{tag})
+	if !ok {
+		return
+	}
+	if len(tags) == 0 {
+		http.Error(w, "Tag can't be empty", http.StatusUnprocessableEntity)
+		return
+	}
+	tag = tags[0]
+
+	var err error
+	if follow {
+		err = h.DB.FollowTag(username, tag)
+	} else {
+		err = h.DB.UnfollowTag(username, tag)
+	}
+	if err == db.ErrTooManyFollowedTags {
+		http.Error(w, fmt.Sprintf("Users can follow at most %d tags", util.MaxFollowedTags), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating followed tags", http.StatusInternalServerError)
+		return
+	}
+
+	// Prepare response
+	response := api.TagFollowResponse{
+		Tag: api.TagFollow{
+			Tag:       tag,
+			Following: follow,
+		},
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
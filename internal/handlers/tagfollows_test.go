@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/policy"
+	"github.com/denga/go-real-world-example/internal/util"
+)
+
+// followTag calls FollowTag or UnfollowTag for the given user and tag and returns the response recorder
+func followTag(handler *Handler, email, tag string, follow bool) *httptest.ResponseRecorder {
+	method := "POST"
+	if !follow {
+		method = "DELETE"
+	}
+	req := httptest.NewRequest(method, "/api/tags/"+url.PathEscape(tag)+"/follow", nil)
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	if follow {
+		handler.FollowTag(rr, req, tag)
+	} else {
+		handler.UnfollowTag(rr, req, tag)
+	}
+	return rr
+}
+
+func TestFollowTag(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	// Tags are normalized like the tags of articles
+	rr := followTag(handler, user.Email, " Web  Dev", true)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var resp api.TagFollowResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Tag.Tag != "web dev" || !resp.Tag.Following {
+		t.Errorf("Expected to follow web dev, got %+v", resp.Tag)
+	}
+	if !testDB.IsFollowingTag(user.Username, "web dev") {
+		t.Errorf("Expected the user to follow web dev")
+	}
+
+	// Unfollowing works the same way
+	rr = followTag(handler, user.Email, "WEB DEV", false)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if testDB.IsFollowingTag(user.Username, "web dev") {
+		t.Errorf("Expected the user to no longer follow web dev")
+	}
+
+	// Empty tags are rejected
+	if rr := followTag(handler, user.Email, " ", true); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+
+	// Users can only follow so many tags
+	for i := 0; i < util.MaxFollowedTags; i++ {
+		testDB.FollowTag(user.Username, "tag"+strconv.Itoa(i))
+	}
+	if rr := followTag(handler, user.Email, "go", true); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+
+	// Following tags needs a user
+	rr = httptest.NewRecorder()
+	handler.FollowTag(rr, httptest.NewRequest("POST", "/api/tags/go/follow", nil), "go")
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
+
+func TestGetArticlesFeedWithFollowedTags(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	author := setupRoleUser(t, testDB, "author", policy.RoleUser)
+	createArticle(handler, author, "Untagged")
+	createArticle(handler, author, "Tagged")
+	updateArticleTags(handler, author, "tagged", []string{"go"})
+	testDB.FollowUser(user.Username, "author")
+	followTag(handler, user.Email, "go", true)
+
+	req := httptest.NewRequest("GET", "/api/articles/feed", nil)
+	req = addUserToContext(req, user.Email)
+	rr := httptest.NewRecorder()
+	handler.GetArticlesFeed(rr, req, api.GetArticlesFeedParams{})
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp api.MultipleArticlesResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	// The tagged article of the followed author is in the feed once
+	if resp.ArticlesCount != 2 || len(resp.Articles) != 2 {
+		t.Errorf("Expected 2 articles, got %d", resp.ArticlesCount)
+	}
+}
+
+func TestGetProfileByUsername(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	reader := setupRoleUser(t, testDB, "reader", policy.RoleUser)
+	testDB.FollowUser("reader", user.Username)
+	followTag(handler, user.Email, "rust", true)
+	followTag(handler, user.Email, "go", true)
+
+	getProfile := func(username string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/profiles/"+username, nil)
+		req = addUserToContext(req, reader)
+		rr := httptest.NewRecorder()
+		handler.GetProfileByUsername(rr, req, username)
+		return rr
+	}
+
+	rr := getProfile(user.Username)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp api.ProfileResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Profile.Username != user.Username || !resp.Profile.Following {
+		t.Errorf("Expected %s followed by the reader, got %+v", user.Username, resp.Profile)
+	}
+	if resp.Profile.FollowedTags == nil || len(*resp.Profile.FollowedTags) != 2 || (*resp.Profile.FollowedTags)[0] != "go" {
+		t.Errorf("Expected followed tags [go rust], got %v", resp.Profile.FollowedTags)
+	}
+
+	if rr := getProfile("unknown"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/middleware"
+)
+
+// maxPersonalTokens limits the number of personal access tokens per user
+const maxPersonalTokens = 50
+
+// toAPIPersonalToken converts a stored personal token for responses, leaving out its hash
+func toAPIPersonalToken(token db.PersonalToken) api.PersonalToken {
+	return api.PersonalToken{
+		Id:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+}
+
+// ListPersonalTokens lists the personal access tokens of the current user
+func (h *Handler) ListPersonalTokens(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Prepare response
+	tokens := h.DB.ListPersonalTokens(email)
+	response := api.PersonalTokensResponse{
+		Tokens: make([]api.PersonalToken, len(tokens)),
+	}
+	for i, token := range tokens {
+		response.Tokens[i] = toAPIPersonalToken(token)
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreatePersonalToken creates a personal access token for the current user.
+// The token is returned once and only its hash is stored.
+func (h *Handler) CreatePersonalToken(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse request body
+	var req api.CreatePersonalTokenJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	name := strings.TrimSpace(req.Token.Name)
+	if name == "" || len(name) > 100 {
+		http.Error(w, "Name is required and must be at most 100 characters", http.StatusUnprocessableEntity)
+		return
+	}
+	if len(req.Token.Scopes) == 0 {
+		http.Error(w, "At least one scope is required", http.StatusUnprocessableEntity)
+		return
+	}
+	var scopes []string
+	for _, scope := range req.Token.Scopes {
+		if !auth.ValidScope(scope) {
+			http.Error(w, "Unknown scope: "+scope, http.StatusUnprocessableEntity)
+			return
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	now := h.now()
+	if req.Token.ExpiresAt != nil && !req.Token.ExpiresAt.After(now) {
+		http.Error(w, "Expiry must be in the future", http.StatusUnprocessableEntity)
+		return
+	}
+	if len(h.DB.ListPersonalTokens(email)) >= maxPersonalTokens {
+		http.Error(w, "Too many personal access tokens", http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Generate token
+	secret, err := auth.GeneratePersonalToken()
+	if err != nil {
+		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		return
+	}
+
+	// Store its hash
+	token, err := h.DB.CreatePersonalToken(db.PersonalToken{
+		Hash:      auth.HashOneTimeToken(secret),
+		Email:     email,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: req.Token.ExpiresAt,
+	})
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error creating token", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Prepare response
+	response := api.NewPersonalTokenResponse{
+		Token:  toAPIPersonalToken(*token),
+		Secret: secret,
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeletePersonalToken revokes a personal access token of the current user
+func (h *Handler) DeletePersonalToken(w http.ResponseWriter, r *http.Request, id string) {
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.DB.DeletePersonalToken(email, id); err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "Token not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error revoking token", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/middleware"
+)
+
+// createPersonalToken calls CreatePersonalToken for the given user
+func createPersonalToken(handler *Handler, email string, token api.NewPersonalToken) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.NewPersonalTokenRequest{Token: token})
+	req := httptest.NewRequest("POST", "/api/user/tokens", bytes.NewBuffer(body))
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.CreatePersonalToken(rr, req)
+	return rr
+}
+
+func TestPersonalTokens(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	// Create a token
+	rr := createPersonalToken(handler, user.Email, api.NewPersonalToken{
+		Name:   "CI",
+		Scopes: []string{auth.ScopeArticlesWrite, auth.ScopeArticlesWrite},
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", http.StatusCreated, rr.Code)
+	}
+	var created api.NewPersonalTokenResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, auth.PersonalTokenPrefix) {
+		t.Errorf("Expected secret with prefix %s, got %s", auth.PersonalTokenPrefix, created.Secret)
+	}
+	if created.Token.Name != "CI" || len(created.Token.Scopes) != 1 {
+		t.Errorf("Unexpected token: %+v", created.Token)
+	}
+
+	// Only the hash is stored
+	stored, err := testDB.AuthenticatePersonalToken(auth.HashOneTimeToken(created.Secret), time.Now())
+	if err != nil || stored.Email != user.Email {
+		t.Fatalf("Expected token to authenticate %s, got %v", user.Email, err)
+	}
+
+	// List tokens, the secret is not included
+	req := httptest.NewRequest("GET", "/api/user/tokens", nil)
+	req = addUserToContext(req, user.Email)
+	rr = httptest.NewRecorder()
+	handler.ListPersonalTokens(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), created.Secret) {
+		t.Error("Expected secret not to be listed")
+	}
+	var list api.PersonalTokensResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(list.Tokens) != 1 || list.Tokens[0].Id != created.Token.Id || list.Tokens[0].LastUsedAt == nil {
+		t.Errorf("Unexpected tokens: %+v", list.Tokens)
+	}
+
+	// Revoke the token
+	req = httptest.NewRequest("DELETE", "/api/user/tokens/"+created.Token.Id, nil)
+	req = addUserToContext(req, user.Email)
+	rr = httptest.NewRecorder()
+	handler.DeletePersonalToken(rr, req, created.Token.Id)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if _, err := testDB.AuthenticatePersonalToken(auth.HashOneTimeToken(created.Secret), time.Now()); err == nil {
+		t.Error("Expected revoked token to be rejected")
+	}
+
+	// Revoking again fails
+	rr = httptest.NewRecorder()
+	handler.DeletePersonalToken(rr, req, created.Token.Id)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestCreatePersonalTokenValidation(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		token api.NewPersonalToken
+	}{
+		{name: "Missing name", token: api.NewPersonalToken{Name: " ", Scopes: []string{auth.ScopeUserRead}}},
+		{name: "Missing scopes", token: api.NewPersonalToken{Name: "CI"}},
+		{name: "Unknown scope", token: api.NewPersonalToken{Name: "CI", Scopes: []string{"admin"}}},
+		{name: "Expiry in the past", token: api.NewPersonalToken{Name: "CI", Scopes: []string{auth.ScopeUserRead}, ExpiresAt: &past}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := createPersonalToken(handler, user.Email, tt.token)
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+			}
+		})
+	}
+
+	if tokens := testDB.ListPersonalTokens(user.Email); len(tokens) != 0 {
+		t.Errorf("Expected no tokens to be created, got %d", len(tokens))
+	}
+}
+
+func TestPersonalTokenGetsNoSessionToken(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	protected := middleware.Auth(handler.AuthConfig, testDB)(http.HandlerFunc(handler.GetCurrentUser))
+
+	rr := createPersonalToken(handler, user.Email, api.NewPersonalToken{Name: "CI", Scopes: []string{auth.ScopeUserRead}})
+	var created api.NewPersonalTokenResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	// The user is returned without a token that could escape the scopes
+	for _, mode := range []auth.Mode{auth.ModeToken, auth.ModeCookie} {
+		handler.AuthConfig.Mode = mode
+		req := httptest.NewRequest("GET", "/api/user", nil)
+		addAuthHeader(req, created.Secret)
+		rr := httptest.NewRecorder()
+		protected.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s mode: expected status code %d, got %d", mode, http.StatusOK, rr.Code)
+		}
+		var response api.UserResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if response.User.Email != user.Email || response.User.Token != "" {
+			t.Errorf("%s mode: expected %s without token, got %+v", mode, user.Email, response.User)
+		}
+		if cookies := rr.Result().Cookies(); len(cookies) != 0 {
+			t.Errorf("%s mode: expected no session cookies, got %v", mode, cookies)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/middleware"
+)
+
+// recoveryCodeCount is the number of recovery codes issued when enabling 2FA
+const recoveryCodeCount = 10
+
+// writeTwoFactorChallenge responds to a correct password of a 2FA account
+// with a short-lived challenge instead of a token
+func (h *Handler) writeTwoFactorChallenge(w http.ResponseWriter, email string) {
+	token, expiresAt, err := auth.GenerateChallengeToken(email, h.AuthConfig)
+	if err != nil {
+		http.Error(w, "Error generating challenge", http.StatusInternalServerError)
+		return
+	}
+
+	// Prepare response
+	response := api.TwoFactorChallengeResponse{
+		TwoFactor: api.TwoFactorChallenge{
+			Token:     token,
+			ExpiresAt: expiresAt,
+		},
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// EnrollTwoFactor generates a pending TOTP secret for the current user
+func (h *Handler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Generate secret
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		http.Error(w, "Error generating secret", http.StatusInternalServerError)
+		return
+	}
+
+	// Store it until the user proves their authenticator app has it
+	if err := h.DB.SetPendingTOTPSecret(email, secret); err != nil {
+		switch err {
+		case db.ErrNotFound:
+			http.Error(w, "User not found", http.StatusNotFound)
+		case db.ErrConflict:
+			http.Error(w, "Two-factor authentication is already enabled", http.StatusConflict)
+		default:
+			http.Error(w, "Error starting enrollment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	issuer := h.AuthConfig.TOTPIssuer
+	if issuer == "" {
+		issuer = "Conduit"
+	}
+
+	// Prepare response
+	response := api.TwoFactorEnrollmentResponse{
+		Secret:     secret,
+		OtpauthUri: auth.TOTPURI(issuer, email, secret),
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ConfirmTwoFactor enables 2FA once a code for the pending secret is verified
+func (h *Handler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var request api.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get user from database
+	user, err := h.DB.GetInternalUserByEmail(email)
+	if err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error retrieving user", http.StatusInternalServerError)
+		}
+		return
+	}
+	if user.TOTPPendingSecret == "" {
+		http.Error(w, "No two-factor enrollment in progress", http.StatusNotFound)
+		return
+	}
+
+	// Verify the code against the pending secret
+	step, ok := auth.ValidateTOTP(user.TOTPPendingSecret, request.TwoFactor.Code, h.now())
+	if !ok {
+		http.Error(w, "Invalid code", http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Generate recovery codes, only their hashes are stored
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		http.Error(w, "Error generating recovery codes", http.StatusInternalServerError)
+		return
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashRecoveryCode(code)
+	}
+
+	if err := h.DB.EnableTOTP(email, step, hashes); err != nil {
+		http.Error(w, "Error enabling two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	// Prepare response
+	response := api.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// DisableTwoFactor disables 2FA after confirming the user's password
+func (h *Handler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var request api.PasswordConfirmationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Get authenticated user from context
+	email, ok := middleware.GetUserEmail(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify password
+	if !h.confirmPassword(w, r, email, request.User.Password) {
+		return
+	}
+
+	if err := h.DB.DisableTOTP(email); err != nil {
+		http.Error(w, "Error disabling two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// LoginTwoFactor completes a login by checking the second factor of a challenge
+func (h *Handler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var request api.TwoFactorLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// The challenge proves that the password was correct
+	email, err := auth.ValidateChallengeToken(request.TwoFactor.Challenge, h.AuthConfig)
+	if err != nil {
+		http.Error(w, "Invalid or expired challenge", http.StatusUnauthorized)
+		return
+	}
+
+	// Second factor attempts count towards the login throttle
+	ip := middleware.GetClientIP(r)
+	if wait, err := h.LoginThrottle.Check(email, ip); err != nil {
+		writeRetryAfter(w, wait)
+		http.Error(w, "Too many login attempts", http.StatusTooManyRequests)
+		return
+	}
+
+	// Get user from database
+	user, err := h.DB.GetInternalUserByEmail(email)
+	if err != nil || !user.TwoFactorEnabled() {
+		http.Error(w, "Invalid or expired challenge", http.StatusUnauthorized)
+		return
+	}
+	if user.Suspended() {
+		http.Error(w, "Account suspended", http.StatusForbidden)
+		return
+	}
+
+	if reason := h.verifySecondFactor(user, request.TwoFactor.Code); reason != "" {
+		h.LoginThrottle.Failure(email, ip, reason)
+		http.Error(w, "Invalid code", http.StatusUnauthorized)
+		return
+	}
+
+	h.LoginThrottle.Success(email)
+	h.writeLogin(w, email)
+}
+
+// verifySecondFactor checks a TOTP or recovery code and consumes it. It
+// returns the reason for the audit trail if the code is rejected.
+func (h *Handler) verifySecondFactor(user *db.InternalUser, code string) string {
+	if step, ok := auth.ValidateTOTP(user.TOTPSecret, code, h.now()); ok {
+		// Each code is only accepted once, even within its time window
+		if err := h.DB.UseTOTPStep(user.Email, step); err != nil {
+			return "reused two-factor code"
+		}
+		return ""
+	}
+
+	if err := h.DB.UseRecoveryCode(user.Email, auth.HashRecoveryCode(code)); err != nil {
+		return "invalid two-factor code"
+	}
+	return ""
+}
+
+// confirmPassword checks the password of the current user before sensitive
+// changes and writes an error response if it is wrong. Confirmations are
+// throttled like logins.
+func (h *Handler) confirmPassword(w http.ResponseWriter, r *http.Request, email, password string) bool {
+	ip := middleware.GetClientIP(r)
+	if wait, err := h.LoginThrottle.Check(email, ip); err != nil {
+		writeRetryAfter(w, wait)
+		http.Error(w, "Too many attempts", http.StatusTooManyRequests)
+		return false
+	}
+
+	if err := h.DB.VerifyUserPassword(email, password); err != nil {
+		switch err {
+		case db.ErrNotFound:
+			http.Error(w, "User not found", http.StatusNotFound)
+		case db.ErrInvalidCredentials:
+			h.LoginThrottle.Failure(email, ip, "invalid password confirmation")
+			http.Error(w, "Invalid password", http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, "Error verifying credentials", http.StatusInternalServerError)
+		}
+		return false
+	}
+	return true
+}
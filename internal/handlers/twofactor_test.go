@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/auth"
+)
+
+// setupTwoFactorUser creates a test user with 2FA enabled and returns the
+// TOTP secret and recovery codes. The handler clock is fixed at *now.
+func setupTwoFactorUser(t *testing.T, handler *Handler, email string) (string, []string, *time.Time) {
+	t.Helper()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return now }
+
+	// Start enrollment
+	req := httptest.NewRequest("POST", "/api/user/two-factor", nil)
+	req = addUserToContext(req, email)
+	rr := httptest.NewRecorder()
+	handler.EnrollTwoFactor(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var enrollment api.TwoFactorEnrollmentResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &enrollment); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	// Confirm with a code from the "authenticator app"
+	code, _ := auth.TOTPCode(enrollment.Secret, now)
+	body, _ := json.Marshal(api.TwoFactorCodeRequest{TwoFactor: api.TwoFactorCode{Code: code}})
+	req = httptest.NewRequest("POST", "/api/user/two-factor/verify", bytes.NewBuffer(body))
+	req = addUserToContext(req, email)
+	rr = httptest.NewRecorder()
+	handler.ConfirmTwoFactor(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var recovery api.RecoveryCodesResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &recovery); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	return enrollment.Secret, recovery.RecoveryCodes, &now
+}
+
+// loginForChallenge logs in with the password and returns the 2FA challenge token
+func loginForChallenge(t *testing.T, handler *Handler, email string) string {
+	t.Helper()
+
+	body, _ := json.Marshal(api.LoginUserRequest{User: api.LoginUser{Email: email, Password: "password123"}})
+	req := httptest.NewRequest("POST", "/api/users/login", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.Login(rr, req)
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("Expected status code %d, got %d", http.StatusAccepted, rr.Code)
+	}
+	var resp api.TwoFactorChallengeResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.TwoFactor.Token == "" {
+		t.Fatal("Expected a challenge token")
+	}
+
+	return resp.TwoFactor.Token
+}
+
+// loginTwoFactor completes a 2FA login and returns the response recorder
+func loginTwoFactor(handler *Handler, challenge, code string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.TwoFactorLoginRequest{TwoFactor: api.TwoFactorLogin{Challenge: challenge, Code: code}})
+	req := httptest.NewRequest("POST", "/api/users/login/two-factor", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.LoginTwoFactor(rr, req)
+	return rr
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	// Create request
+	req := httptest.NewRequest("POST", "/api/user/two-factor", nil)
+	req = addUserToContext(req, user.Email)
+	rr := httptest.NewRecorder()
+
+	// Call handler
+	handler.EnrollTwoFactor(rr, req)
+
+	// Check response
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp api.TwoFactorEnrollmentResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Secret == "" {
+		t.Error("Expected a secret")
+	}
+	if resp.OtpauthUri != auth.TOTPURI("Conduit", user.Email, resp.Secret) {
+		t.Errorf("Unexpected otpauth URI: %s", resp.OtpauthUri)
+	}
+
+	// 2FA is not enabled until the secret is confirmed
+	internalUser, _ := testDB.GetInternalUserByEmail(user.Email)
+	if internalUser.TwoFactorEnabled() {
+		t.Error("Expected 2FA to stay disabled until confirmed")
+	}
+}
+
+func TestConfirmTwoFactorWithInvalidCode(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+
+	// Start enrollment
+	req := httptest.NewRequest("POST", "/api/user/two-factor", nil)
+	req = addUserToContext(req, user.Email)
+	handler.EnrollTwoFactor(httptest.NewRecorder(), req)
+
+	// Confirm with a wrong code
+	body, _ := json.Marshal(api.TwoFactorCodeRequest{TwoFactor: api.TwoFactorCode{Code: "000000"}})
+	req = httptest.NewRequest("POST", "/api/user/two-factor/verify", bytes.NewBuffer(body))
+	req = addUserToContext(req, user.Email)
+	rr := httptest.NewRecorder()
+	handler.ConfirmTwoFactor(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+}
+
+func TestLoginWithTwoFactor(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	secret, recoveryCodes, now := setupTwoFactorUser(t, handler, user.Email)
+
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("Expected %d recovery codes, got %d", recoveryCodeCount, len(recoveryCodes))
+	}
+
+	// A second enrollment is rejected
+	req := httptest.NewRequest("POST", "/api/user/two-factor", nil)
+	req = addUserToContext(req, user.Email)
+	rr := httptest.NewRecorder()
+	handler.EnrollTwoFactor(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d, got %d", http.StatusConflict, rr.Code)
+	}
+
+	// The password alone only yields a challenge
+	challenge := loginForChallenge(t, handler, user.Email)
+	if _, err := auth.ValidateToken(challenge, handler.AuthConfig); err == nil {
+		t.Error("Expected challenge token not to be accepted as session token")
+	}
+
+	// The code used for enrollment can't be replayed
+	code, _ := auth.TOTPCode(secret, *now)
+	if rr := loginTwoFactor(handler, challenge, code); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d for replayed code, got %d", http.StatusUnauthorized, rr.Code)
+	}
+
+	// A fresh code completes the login
+	*now = now.Add(auth.TOTPPeriod * time.Second)
+	code, _ = auth.TOTPCode(secret, *now)
+	rr = loginTwoFactor(handler, challenge, code)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp api.UserResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if email, err := auth.ValidateToken(resp.User.Token, handler.AuthConfig); err != nil || email != user.Email {
+		t.Errorf("Expected a valid token for %s, got %v", user.Email, err)
+	}
+
+	// Wrong codes are rejected and audited
+	if rr := loginTwoFactor(handler, challenge, "000000"); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+	attempts := handler.LoginThrottle.FailedAttempts()
+	if len(attempts) == 0 || attempts[0].Reason != "invalid two-factor code" {
+		t.Errorf("Expected audited failure, got %+v", attempts)
+	}
+
+	// Invalid challenges are rejected
+	if rr := loginTwoFactor(handler, "not-a-token", code); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
+
+func TestLoginWithRecoveryCode(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	_, recoveryCodes, _ := setupTwoFactorUser(t, handler, user.Email)
+
+	challenge := loginForChallenge(t, handler, user.Email)
+
+	// A recovery code works once
+	if rr := loginTwoFactor(handler, challenge, recoveryCodes[0]); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr := loginTwoFactor(handler, challenge, recoveryCodes[0]); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d for used recovery code, got %d", http.StatusUnauthorized, rr.Code)
+	}
+
+	// Other codes are still valid
+	if rr := loginTwoFactor(handler, challenge, recoveryCodes[1]); rr.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	user, _ := setupTestUser(testDB, handler.AuthConfig)
+	setupTwoFactorUser(t, handler, user.Email)
+
+	// A wrong password is rejected
+	body, _ := json.Marshal(api.PasswordConfirmationRequest{User: api.PasswordConfirmation{Password: "wrong"}})
+	req := httptest.NewRequest("DELETE", "/api/user/two-factor", bytes.NewBuffer(body))
+	req = addUserToContext(req, user.Email)
+	rr := httptest.NewRecorder()
+	handler.DisableTwoFactor(rr, req)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+
+	// The correct password disables 2FA
+	body, _ = json.Marshal(api.PasswordConfirmationRequest{User: api.PasswordConfirmation{Password: "password123"}})
+	req = httptest.NewRequest("DELETE", "/api/user/two-factor", bytes.NewBuffer(body))
+	req = addUserToContext(req, user.Email)
+	rr = httptest.NewRecorder()
+	handler.DisableTwoFactor(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	// Logging in no longer needs a second factor
+	body, _ = json.Marshal(api.LoginUserRequest{User: api.LoginUser{Email: user.Email, Password: "password123"}})
+	req = httptest.NewRequest("POST", "/api/users/login", bytes.NewBuffer(body))
+	rr = httptest.NewRecorder()
+	handler.Login(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+}
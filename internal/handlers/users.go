@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// ListUsers returns a page of users for admins
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request, params api.ListUsersParams) {
+	if _, ok := h.authorize(w, r, policy.ManageUsers, ""); !ok {
+		return
+	}
+
+	// Set default values for limit and offset
+	limit := 20
+	if params.Limit != nil {
+		limit = int(*params.Limit)
+	}
+
+	offset := 0
+	if params.Offset != nil {
+		offset = int(*params.Offset)
+	}
+
+	// Extract filter parameters
+	var filter db.UserFilter
+	if params.Search != nil {
+		filter.Search = *params.Search
+	}
+	filter.Suspended = params.Suspended
+
+	users, totalCount := h.DB.ListUsers(filter, limit, offset)
+
+	// Prepare response
+	response := api.AdminUsersResponse{
+		Users:      []api.AdminUser{},
+		UsersCount: totalCount,
+	}
+	for _, user := range users {
+		adminUser := api.AdminUser{
+			Username:         user.Username,
+			Email:            user.Email,
+			Role:             string(user.Role),
+			EmailVerified:    user.EmailVerified,
+			TwoFactorEnabled: user.TwoFactorEnabled(),
+			SuspendedAt:      user.SuspendedAt,
+		}
+		if user.Suspended() && user.SuspensionReason != "" {
+			reason := user.SuspensionReason
+			adminUser.SuspensionReason = &reason
+		}
+		response.Users = append(response.Users, adminUser)
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// SuspendUser blocks a user from signing in and hides their content
+func (h *Handler) SuspendUser(w http.ResponseWriter, r *http.Request, username string) {
+	if _, ok := h.authorize(w, r, policy.SuspendUser, ""); !ok {
+		return
+	}
+
+	// Parse request body
+	var request api.SuspendUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reason := ""
+	if request.Suspension.Reason != nil {
+		reason = *request.Suspension.Reason
+	}
+	if len(reason) > 500 {
+		http.Error(w, "Reason must be at most 500 characters", http.StatusUnprocessableEntity)
+		return
+	}
+
+	user, ok := h.managedUser(w, username)
+	if !ok {
+		return
+	}
+	if user.Role == policy.RoleAdmin {
+		http.Error(w, "Admins can't be suspended, change their role first", http.StatusConflict)
+		return
+	}
+
+	if err := h.DB.SuspendUser(user.Email, reason, h.now()); err != nil {
+		http.Error(w, "Error suspending user", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// UnsuspendUser allows a suspended user to sign in again
+func (h *Handler) UnsuspendUser(w http.ResponseWriter, r *http.Request, username string) {
+	if _, ok := h.authorize(w, r, policy.SuspendUser, ""); !ok {
+		return
+	}
+
+	user, ok := h.managedUser(w, username)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.UnsuspendUser(user.Email); err != nil {
+		http.Error(w, "Error lifting suspension", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteUser deletes a user with all of their content
+func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request, username string) {
+	if _, ok := h.authorize(w, r, policy.ManageUsers, ""); !ok {
+		return
+	}
+
+	user, ok := h.managedUser(w, username)
+	if !ok {
+		return
+	}
+	if user.Role == policy.RoleAdmin {
+		http.Error(w, "Admins can't be deleted, change their role first", http.StatusConflict)
+		return
+	}
+
+	if err := h.DB.DeleteUser(user.Email); err != nil {
+		if err == db.ErrNotFound {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error deleting user", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Forget failed logins, so that a new account with the same email starts clean
+	h.LoginThrottle.Unlock(user.Email)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ForcePasswordReset revokes the credentials of a user and emails them a
+// password reset link
+func (h *Handler) ForcePasswordReset(w http.ResponseWriter, r *http.Request, username string) {
+	if _, ok := h.authorize(w, r, policy.ManageUsers, ""); !ok {
+		return
+	}
+
+	user, ok := h.managedUser(w, username)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.RevokeCredentials(user.Email, h.now()); err != nil {
+		http.Error(w, "Error revoking credentials", http.StatusInternalServerError)
+		return
+	}
+
+	// The credentials are revoked either way, so a failed email can be retried
+	err := h.sendActionEmail(user.Email, db.PurposePasswordReset, passwordResetExpiry,
+		"/reset-password", "Reset your password",
+		"An administrator reset the password of your account. Choose a new password here:")
+	if err != nil {
+		log.Printf("Error sending password reset email: %v", err)
+		http.Error(w, "Error sending password reset email", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// managedUser looks up the user an admin acts on, and writes an error
+// response if it doesn't exist
+func (h *Handler) managedUser(w http.ResponseWriter, username string) (*db.InternalUser, bool) {
+	user, err := h.DB.GetUserByUsername(username)
+	if err == nil {
+		var internalUser *db.InternalUser
+		if internalUser, err = h.DB.GetInternalUserByEmail(user.Email); err == nil {
+			return internalUser, true
+		}
+	}
+
+	if err == db.ErrNotFound {
+		http.Error(w, "User not found", http.StatusNotFound)
+	} else {
+		http.Error(w, "Error retrieving user", http.StatusInternalServerError)
+	}
+	return nil, false
+}
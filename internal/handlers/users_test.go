@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// suspendUser calls SuspendUser as the given admin and returns the response recorder
+func suspendUser(handler *Handler, admin, username, reason string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.SuspendUserJSONRequestBody{Suspension: api.Suspension{Reason: &reason}})
+	req := httptest.NewRequest("POST", "/api/admin/users/"+username+"/suspension", bytes.NewBuffer(body))
+	req = addUserToContext(req, admin)
+	rr := httptest.NewRecorder()
+	handler.SuspendUser(rr, req, username)
+	return rr
+}
+
+// login calls Login and returns the response recorder
+func login(handler *Handler, email, password string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.LoginUserRequest{User: api.LoginUser{Email: email, Password: password}})
+	req := httptest.NewRequest("POST", "/api/users/login", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.Login(rr, req)
+	return rr
+}
+
+func TestListUsers(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	admin := setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+	setupRoleUser(t, testDB, "alice", policy.RoleUser)
+	setupRoleUser(t, testDB, "bob", policy.RoleUser)
+	if rr := suspendUser(handler, admin, "bob", "Spam"); rr.Code != http.StatusOK {
+		t.Fatalf("Failed to suspend user: %d", rr.Code)
+	}
+
+	search, suspended, active, one := "ALI", true, false, 1
+	tests := []struct {
+		name     string
+		params   api.ListUsersParams
+		expected []string
+		count    int
+	}{
+		{name: "All", expected: []string{"admin", "alice", "bob"}, count: 3},
+		{name: "Search", params: api.ListUsersParams{Search: &search}, expected: []string{"alice"}, count: 1},
+		{name: "Suspended", params: api.ListUsersParams{Suspended: &suspended}, expected: []string{"bob"}, count: 1},
+		{name: "Active", params: api.ListUsersParams{Suspended: &active}, expected: []string{"admin", "alice"}, count: 2},
+		{name: "Paginated", params: api.ListUsersParams{Offset: &one, Limit: &one}, expected: []string{"alice"}, count: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/admin/users", nil)
+			req = addUserToContext(req, admin)
+			rr := httptest.NewRecorder()
+			handler.ListUsers(rr, req, tt.params)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+
+			var response api.AdminUsersResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.UsersCount != tt.count {
+				t.Errorf("Expected count %d, got %d", tt.count, response.UsersCount)
+			}
+			var usernames []string
+			for _, user := range response.Users {
+				usernames = append(usernames, user.Username)
+			}
+			if len(usernames) != len(tt.expected) {
+				t.Fatalf("Expected users %v, got %v", tt.expected, usernames)
+			}
+			for i := range usernames {
+				if usernames[i] != tt.expected[i] {
+					t.Errorf("Expected users %v, got %v", tt.expected, usernames)
+				}
+			}
+		})
+	}
+
+	// Suspended users carry the reason
+	req := httptest.NewRequest("GET", "/api/admin/users", nil)
+	req = addUserToContext(req, admin)
+	rr := httptest.NewRecorder()
+	bob := "bob"
+	handler.ListUsers(rr, req, api.ListUsersParams{Search: &bob})
+	var response api.AdminUsersResponse
+	json.NewDecoder(rr.Body).Decode(&response)
+	if len(response.Users) != 1 || response.Users[0].SuspendedAt == nil || response.Users[0].SuspensionReason == nil || *response.Users[0].SuspensionReason != "Spam" {
+		t.Errorf("Expected suspended user with reason, got %+v", response.Users)
+	}
+}
+
+func TestAdminUserEndpointsRequireAdmin(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+	moderator := setupRoleUser(t, testDB, "moderator", policy.RoleModerator)
+	setupRoleUser(t, testDB, "alice", policy.RoleUser)
+
+	calls := map[string]func(w http.ResponseWriter, r *http.Request){
+		"ListUsers": func(w http.ResponseWriter, r *http.Request) {
+			handler.ListUsers(w, r, api.ListUsersParams{})
+		},
+		"SuspendUser": func(w http.ResponseWriter, r *http.Request) {
+			handler.SuspendUser(w, r, "alice")
+		},
+		"UnsuspendUser": func(w http.ResponseWriter, r *http.Request) {
+			handler.UnsuspendUser(w, r, "alice")
+		},
+		"DeleteUser": func(w http.ResponseWriter, r *http.Request) {
+			handler.DeleteUser(w, r, "alice")
+		},
+		"ForcePasswordReset": func(w http.ResponseWriter, r *http.Request) {
+			handler.ForcePasswordReset(w, r, "alice")
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/admin/users", bytes.NewBufferString(`{"suspension":{}}`))
+			req = addUserToContext(req, moderator)
+			rr := httptest.NewRecorder()
+			call(rr, req)
+
+			if rr.Code != http.StatusForbidden {
+				t.Errorf("Expected status %d, got %d", http.StatusForbidden, rr.Code)
+			}
+		})
+	}
+
+	// Nothing happened to the user
+	user, err := testDB.GetInternalUserByEmail("alice@example.com")
+	if err != nil || user.Suspended() {
+		t.Errorf("Expected alice to be untouched")
+	}
+}
+
+func TestSuspendUser(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	admin := setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+	alice := setupRoleUser(t, testDB, "alice", policy.RoleUser)
+
+	if rr := suspendUser(handler, admin, "alice", "Spam"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	// Suspended users can't sign in
+	if rr := login(handler, alice, "password123"); rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d for login, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	// Wrong passwords still get the generic answer
+	if rr := login(handler, alice, "wrong"); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d for wrong password, got %d", http.StatusUnauthorized, rr.Code)
+	}
+
+	// Lifting the suspension allows signing in again
+	req := httptest.NewRequest("DELETE", "/api/admin/users/alice/suspension", nil)
+	req = addUserToContext(req, admin)
+	rr := httptest.NewRecorder()
+	handler.UnsuspendUser(rr, req, "alice")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr := login(handler, alice, "password123"); rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d for login, got %d", http.StatusOK, rr.Code)
+	}
+}
+
+func TestSuspendUserErrors(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	admin := setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+	setupRoleUser(t, testDB, "other-admin", policy.RoleAdmin)
+
+	tests := []struct {
+		name     string
+		username string
+		reason   string
+		expected int
+	}{
+		{name: "Unknown user", username: "nobody", expected: http.StatusNotFound},
+		{name: "Admin", username: "other-admin", expected: http.StatusConflict},
+		{name: "Self", username: "admin", expected: http.StatusConflict},
+		{name: "Reason too long", username: "other-admin", reason: string(bytes.Repeat([]byte("a"), 501)), expected: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := suspendUser(handler, admin, tt.username, tt.reason); rr.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	admin := setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+	alice := setupRoleUser(t, testDB, "alice", policy.RoleUser)
+	setupArticleWithComment(t, testDB, "alices-article", "alice", "alice")
+
+	req := httptest.NewRequest("DELETE", "/api/admin/users/alice", nil)
+	req = addUserToContext(req, admin)
+	rr := httptest.NewRecorder()
+	handler.DeleteUser(rr, req, "alice")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if _, err := testDB.GetUserByEmail(alice); err != db.ErrNotFound {
+		t.Errorf("Expected user to be deleted, got %v", err)
+	}
+	if _, err := testDB.GetArticle("alices-article"); err != db.ErrNotFound {
+		t.Errorf("Expected article to be deleted, got %v", err)
+	}
+
+	// Deleting again finds nothing
+	rr = httptest.NewRecorder()
+	handler.DeleteUser(rr, req, "alice")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// Admins have to be demoted first
+	rr = httptest.NewRecorder()
+	handler.DeleteUser(rr, req, "admin")
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+}
+
+func TestForcePasswordReset(t *testing.T) {
+	handler, testDB := setupTestHandler()
+	admin := setupRoleUser(t, testDB, "admin", policy.RoleAdmin)
+	alice := setupRoleUser(t, testDB, "alice", policy.RoleUser)
+
+	req := httptest.NewRequest("POST", "/api/admin/users/alice/password-reset", nil)
+	req = addUserToContext(req, admin)
+	rr := httptest.NewRecorder()
+	handler.ForcePasswordReset(rr, req, "alice")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	// The old password stopped working
+	if rr := login(handler, alice, "password123"); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d for old password, got %d", http.StatusUnauthorized, rr.Code)
+	}
+
+	// The emailed link sets a new one
+	path, token := lastMailLink(t, handler, alice)
+	if path != "/reset-password" {
+		t.Errorf("Expected link to /reset-password, got %s", path)
+	}
+	if rr := confirmPasswordReset(handler, token, "new-password"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d for reset, got %d", http.StatusOK, rr.Code)
+	}
+	if rr := login(handler, alice, "new-password"); rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d for new password, got %d", http.StatusOK, rr.Code)
+	}
+
+	// Unknown users
+	rr = httptest.NewRecorder()
+	handler.ForcePasswordReset(rr, req, "nobody")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email messages
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// Config holds the configuration for sending email
+type Config struct {
+	// From is the sender address of all messages
+	From string
+	// SMTPAddr is the host:port of the SMTP server. Messages are only sent
+	// over SMTP when it is set.
+	SMTPAddr string
+	// SMTPUsername and SMTPPassword are used for PLAIN authentication if set
+	SMTPUsername string
+	SMTPPassword string
+	// OutboxDir is a directory messages are written to instead of being sent,
+	// used for local development when no SMTP server is configured
+	OutboxDir string
+}
+
+// DefaultConfig returns a default configuration that doesn't deliver messages
+func DefaultConfig() Config {
+	return Config{
+		From: "Conduit <no-reply@localhost>",
+	}
+}
+
+// New creates the Mailer selected by the configuration: SMTP if a server is
+// configured, otherwise a file outbox if a directory is set, otherwise a
+// mailer that only logs that messages were dropped
+func New(config Config) Mailer {
+	switch {
+	case config.SMTPAddr != "":
+		return NewSMTPMailer(config)
+	case config.OutboxDir != "":
+		return NewFileOutbox(config.OutboxDir, config.From)
+	default:
+		return LogMailer{}
+	}
+}
+
+// format renders the message in RFC 5322 format
+func (m Message) format(from string, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sanitizeHeader(from))
+	fmt.Fprintf(&buf, "To: %s\r\n", sanitizeHeader(m.To))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", sanitizeHeader(m.Subject)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+
+	// SMTP requires CRLF line endings
+	body := strings.ReplaceAll(m.Body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+// sanitizeHeader removes line breaks so that values can't inject headers
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSelectsMailer(t *testing.T) {
+	if _, ok := New(DefaultConfig()).(LogMailer); !ok {
+		t.Error("Expected messages to be dropped by default")
+	}
+
+	if _, ok := New(Config{OutboxDir: t.TempDir()}).(*FileOutbox); !ok {
+		t.Error("Expected file outbox when a directory is configured")
+	}
+
+	if _, ok := New(Config{SMTPAddr: "localhost:25", OutboxDir: t.TempDir()}).(*SMTPMailer); !ok {
+		t.Error("Expected SMTP mailer when a server is configured")
+	}
+}
+
+func TestMessageFormat(t *testing.T) {
+	msg := Message{
+		To:      "test@example.com",
+		Subject: "Hello\r\nBcc: attacker@example.com",
+		Body:    "Line 1\nLine 2",
+	}
+	date := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	formatted := string(msg.format("Conduit <no-reply@example.com>", date))
+
+	if !strings.Contains(formatted, "From: Conduit <no-reply@example.com>\r\n") {
+		t.Errorf("Expected From header, got %q", formatted)
+	}
+	if !strings.Contains(formatted, "To: test@example.com\r\n") {
+		t.Errorf("Expected To header, got %q", formatted)
+	}
+	if !strings.Contains(formatted, "Date: Mon, 01 Jan 2024 12:00:00 +0000\r\n") {
+		t.Errorf("Expected Date header, got %q", formatted)
+	}
+
+	// Line breaks in headers must not start new headers
+	if strings.Contains(formatted, "\r\nBcc:") {
+		t.Errorf("Expected header injection to be prevented, got %q", formatted)
+	}
+
+	// The body uses CRLF line endings after an empty line
+	if !strings.HasSuffix(formatted, "\r\n\r\nLine 1\r\nLine 2") {
+		t.Errorf("Expected CRLF body, got %q", formatted)
+	}
+}
@@ -0,0 +1,97 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Outbox is a Mailer that keeps messages in memory for tests. It never
+// forgets messages, so it must not be used to serve requests.
+type Outbox struct {
+	messages []Message
+	mutex    sync.Mutex
+}
+
+// NewOutbox creates an empty in-memory outbox
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+// Send stores the message in the outbox
+func (o *Outbox) Send(msg Message) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.messages = append(o.messages, msg)
+	return nil
+}
+
+// Messages returns the sent messages, oldest first
+func (o *Outbox) Messages() []Message {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return append([]Message(nil), o.messages...)
+}
+
+// Last returns the most recently sent message
+func (o *Outbox) Last() (Message, bool) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if len(o.messages) == 0 {
+		return Message{}, false
+	}
+	return o.messages[len(o.messages)-1], true
+}
+
+// LogMailer is a Mailer that drops messages when no mail delivery is
+// configured. It logs the recipient and subject, but not the body, which
+// carries the single-use links.
+type LogMailer struct{}
+
+// Send logs that the message was not delivered
+func (LogMailer) Send(msg Message) error {
+	log.Printf("Not delivering email %q to %s, no mail delivery is configured", msg.Subject, msg.To)
+	return nil
+}
+
+// FileOutbox is a Mailer that writes each message to a .eml file, so that
+// mails can be inspected during local development
+type FileOutbox struct {
+	dir  string
+	from string
+	seq  int
+	now  func() time.Time
+
+	mutex sync.Mutex
+}
+
+// NewFileOutbox creates an outbox writing to dir
+func NewFileOutbox(dir, from string) *FileOutbox {
+	return &FileOutbox{
+		dir:  dir,
+		from: from,
+		now:  time.Now,
+	}
+}
+
+// Send writes the message to a new file in the outbox directory
+func (o *FileOutbox) Send(msg Message) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if err := os.MkdirAll(o.dir, 0o755); err != nil {
+		return err
+	}
+
+	now := o.now()
+	o.seq++
+	name := fmt.Sprintf("%s-%03d.eml", now.UTC().Format("20060102T150405.000000000"), o.seq)
+
+	return os.WriteFile(filepath.Join(o.dir, name), msg.format(o.from, now), 0o600)
+}
@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutbox(t *testing.T) {
+	outbox := NewOutbox()
+
+	// An empty outbox has no last message
+	if _, ok := outbox.Last(); ok {
+		t.Error("Expected no message in empty outbox")
+	}
+
+	outbox.Send(Message{To: "a@example.com", Subject: "First"})
+	outbox.Send(Message{To: "b@example.com", Subject: "Second"})
+
+	messages := outbox.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+	if messages[0].Subject != "First" {
+		t.Errorf("Expected oldest message first, got %s", messages[0].Subject)
+	}
+
+	last, ok := outbox.Last()
+	if !ok || last.To != "b@example.com" {
+		t.Errorf("Expected last message to b@example.com, got %+v", last)
+	}
+}
+
+func TestFileOutbox(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	outbox := NewFileOutbox(dir, "no-reply@example.com")
+
+	// Send two messages
+	if err := outbox.Send(Message{To: "a@example.com", Subject: "First", Body: "Hello"}); err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	if err := outbox.Send(Message{To: "b@example.com", Subject: "Second", Body: "World"}); err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+
+	// Each message gets its own file
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		t.Fatalf("Failed to list outbox: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(files))
+	}
+
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("Failed to read message: %v", err)
+	}
+	if !strings.Contains(string(content), "To: a@example.com") || !strings.HasSuffix(string(content), "Hello") {
+		t.Errorf("Unexpected message content: %q", content)
+	}
+}
@@ -0,0 +1,51 @@
+package mail
+
+import (
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer is a Mailer that delivers messages through an SMTP server. The
+// connection is upgraded with STARTTLS when the server supports it.
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+	now  func() time.Time
+}
+
+// NewSMTPMailer creates a Mailer for the SMTP server in the configuration
+func NewSMTPMailer(config Config) *SMTPMailer {
+	mailer := &SMTPMailer{
+		addr: config.SMTPAddr,
+		from: config.From,
+		now:  time.Now,
+	}
+
+	if config.SMTPUsername != "" {
+		host, _, err := net.SplitHostPort(config.SMTPAddr)
+		if err != nil {
+			host = config.SMTPAddr
+		}
+		mailer.auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, host)
+	}
+
+	return mailer
+}
+
+// Send delivers the message
+func (m *SMTPMailer) Send(msg Message) error {
+	// The envelope needs bare addresses, the headers may contain display names
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, from.Address, []string{to.Address}, msg.format(m.from, m.now()))
+}
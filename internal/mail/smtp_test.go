@@ -0,0 +1,131 @@
+package mail
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSMTPServer accepts a single SMTP session and records the envelope and data
+type fakeSMTPServer struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     string
+	done     chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &fakeSMTPServer{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return server
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.to = append(s.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	mailer := NewSMTPMailer(Config{
+		From:     "Conduit <no-reply@example.com>",
+		SMTPAddr: server.listener.Addr().String(),
+	})
+
+	err := mailer.Send(Message{
+		To:      "Test User <test@example.com>",
+		Subject: "Welcome",
+		Body:    "Hello there",
+	})
+	if err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	<-server.done
+
+	// The envelope uses the bare addresses
+	if server.from != "no-reply@example.com" {
+		t.Errorf("Expected envelope sender no-reply@example.com, got %s", server.from)
+	}
+	if len(server.to) != 1 || server.to[0] != "test@example.com" {
+		t.Errorf("Expected envelope recipient test@example.com, got %v", server.to)
+	}
+
+	// The data contains headers and body
+	if !strings.Contains(server.data, "Subject: Welcome\r\n") {
+		t.Errorf("Expected subject header, got %q", server.data)
+	}
+	if !strings.Contains(server.data, "Hello there") {
+		t.Errorf("Expected body, got %q", server.data)
+	}
+}
+
+func TestSMTPMailerInvalidAddress(t *testing.T) {
+	mailer := NewSMTPMailer(Config{
+		From:     "no-reply@example.com",
+		SMTPAddr: "127.0.0.1:1",
+	})
+
+	if err := mailer.Send(Message{To: "not an address"}); err == nil {
+		t.Error("Expected an error for an invalid recipient, got nil")
+	}
+}
@@ -0,0 +1,181 @@
+// Package markdown renders the Markdown of articles to sanitized HTML.
+package markdown
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"html"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/denga/go-real-world-example/internal/util"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// DefaultCacheSize is the number of rendered documents a Renderer keeps
+const DefaultCacheSize = 1024
+
+// fallbackHeadingID is the ID of headings without usable characters
+const fallbackHeadingID = "heading"
+
+// Renderer renders CommonMark with the GitHub Flavored Markdown extensions
+// (tables, strikethrough, autolinks and task lists) to HTML. Raw HTML in the
+// Markdown is allowed, the output is sanitized with an allowlist policy.
+// Headings get IDs derived from their text, so they can be linked to.
+//
+// Rendered documents are cached by the hash of their Markdown, so every
+// revision of an article is rendered once. A Renderer is safe for
+// concurrent use.
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+
+	cacheSize int
+	entries   map[[sha256.Size]byte]*list.Element
+	recent    *list.List // of *cacheEntry, most recently used first
+	mutex     sync.Mutex
+}
+
+// cacheEntry is a rendered document in the cache
+type cacheEntry struct {
+	key  [sha256.Size]byte
+	html string
+}
+
+// NewRenderer creates a Renderer caching up to cacheSize documents.
+// Nothing is cached if cacheSize is 0.
+func NewRenderer(cacheSize int) *Renderer {
+	return &Renderer{
+		markdown: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			// Raw HTML is passed through and sanitized afterwards
+			goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+		),
+		policy:    newPolicy(),
+		cacheSize: cacheSize,
+		entries:   make(map[[sha256.Size]byte]*list.Element),
+		recent:    list.New(),
+	}
+}
+
+// newPolicy returns the sanitization policy: the elements and attributes of
+// user generated content, plus what the Markdown extensions render
+func newPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+
+	// Task list items
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+
+	// Column alignment of tables
+	policy.AllowStyles("text-align").MatchingEnum("left", "center", "right").OnElements("th", "td")
+
+	// Languages of fenced code blocks, for syntax highlighting
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+
+	return policy
+}
+
+// Render returns the sanitized HTML of a Markdown document
+func (r *Renderer) Render(source string) string {
+	key := cacheKey(source)
+	if rendered, ok := r.cached(key); ok {
+		return rendered
+	}
+
+	rendered := r.render(source)
+	r.store(key, rendered)
+	return rendered
+}
+
+// cacheKey returns the key of a document in the cache
+func cacheKey(source string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(source))
+}
+
+// render converts a document without the cache
+func (r *Renderer) render(source string) string {
+	var buf bytes.Buffer
+	context := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	if err := r.markdown.Convert([]byte(source), &buf, parser.WithContext(context)); err != nil {
+		// Writing to a buffer doesn't fail, but never lose the text
+		return "<pre>" + html.EscapeString(source) + "</pre>"
+	}
+	return r.policy.Sanitize(buf.String())
+}
+
+// cached returns a rendered document from the cache and marks it as recently used
+func (r *Renderer) cached(key [sha256.Size]byte) (string, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	element, ok := r.entries[key]
+	if !ok {
+		return "", false
+	}
+	r.recent.MoveToFront(element)
+	return element.Value.(*cacheEntry).html, true
+}
+
+// store adds a rendered document to the cache, evicting the least recently
+// used one if the cache is full
+func (r *Renderer) store(key [sha256.Size]byte, rendered string) {
+	if r.cacheSize <= 0 {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.entries[key]; ok {
+		// Rendered concurrently
+		return
+	}
+	r.entries[key] = r.recent.PushFront(&cacheEntry{key: key, html: rendered})
+
+	if r.recent.Len() > r.cacheSize {
+		oldest := r.recent.Back()
+		r.recent.Remove(oldest)
+		delete(r.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// headingIDs generates the IDs of the headings in one document the way
+// article slugs are generated, numbering repeated headings like
+// "setup", "setup-1"
+type headingIDs struct {
+	used map[string]bool
+}
+
+// newHeadingIDs creates the heading IDs of a new document
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]bool)}
+}
+
+// Generate implements parser.IDs
+func (ids *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := util.Slugify(string(value))
+	if base == "" {
+		base = fallbackHeadingID
+	}
+
+	id := base
+	for counter := 1; ids.used[id]; counter++ {
+		id = base + "-" + strconv.Itoa(counter)
+	}
+	ids.used[id] = true
+	return []byte(id)
+}
+
+// Put implements parser.IDs for headings with explicit IDs
+func (ids *headingIDs) Put(value []byte) {
+	ids.used[string(value)] = true
+}
@@ -0,0 +1,118 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	renderer := NewRenderer(DefaultCacheSize)
+
+	tests := []struct {
+		name     string
+		source   string
+		expected []string
+	}{
+		{
+			name:     "CommonMark",
+			source:   "Some *emphasis*, **strong** and `code`\n\n> Quote",
+			expected: []string{"<p>Some <em>emphasis</em>, <strong>strong</strong> and <code>code</code></p>", "<blockquote>\n<p>Quote</p>\n</blockquote>"},
+		},
+		{
+			name:     "Heading anchors",
+			source:   "# Getting Started\n\n## Größe\n\n## Größe\n\n## 中文",
+			expected: []string{`<h1 id="getting-started">`, `<h2 id="groesse">`, `<h2 id="groesse-1">`, `<h2 id="heading">`},
+		},
+		{
+			name:     "Tables",
+			source:   "| Left | Right |\n|:-----|------:|\n| a    | b     |",
+			expected: []string{`<th style="text-align: left">Left</th>`, `<td style="text-align: right">b</td>`},
+		},
+		{
+			name:     "Strikethrough and autolinks",
+			source:   "~~old~~ https://example.com",
+			expected: []string{"<del>old</del>", `<a href="https://example.com" rel="nofollow">https://example.com</a>`},
+		},
+		{
+			name:     "Task lists",
+			source:   "- [x] Done\n- [ ] Todo",
+			expected: []string{`<input checked="" disabled="" type="checkbox"> Done`, `<input disabled="" type="checkbox"> Todo`},
+		},
+		{
+			name:     "Fenced code",
+			source:   "```go\nfmt.Println(\"<b>\")\n```",
+			expected: []string{`<pre><code class="language-go">fmt.Println(&#34;&lt;b&gt;&#34;)`},
+		},
+		{
+			name:     "Safe raw HTML",
+			source:   "Text with <sup>superscript</sup>",
+			expected: []string{"<sup>superscript</sup>"},
+		},
+	}
+
+	for _, tt := range tests {
+		rendered := renderer.Render(tt.source)
+		for _, expected := range tt.expected {
+			if !strings.Contains(rendered, expected) {
+				t.Errorf("%s: expected %s in:\n%s", tt.name, expected, rendered)
+			}
+		}
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	renderer := NewRenderer(DefaultCacheSize)
+
+	tests := []string{
+		"<script>alert(1)</script>",
+		`<img src="x" onerror="alert(1)">`,
+		"[link](javascript:alert(1))",
+		`<a href="javascript:alert(1)">link</a>`,
+		`<iframe src="https://example.com"></iframe>`,
+		`<p style="position: fixed">overlay</p>`,
+		`<input type="text" value="phishing">`,
+		"<code class=\"evil\">x</code>",
+	}
+
+	for _, source := range tests {
+		rendered := strings.ToLower(renderer.Render(source))
+		for _, forbidden := range []string{"<script", "onerror", "javascript:", "<iframe", "position", `type="text"`, "evil"} {
+			if strings.Contains(rendered, forbidden) {
+				t.Errorf("%s: expected no %s, got %s", source, forbidden, rendered)
+			}
+		}
+	}
+}
+
+func TestRenderCache(t *testing.T) {
+	renderer := NewRenderer(2)
+
+	// Rendered documents are reused
+	first := renderer.Render("# One")
+	if renderer.Render("# One") != first {
+		t.Errorf("Expected the same output for the same document")
+	}
+	if renderer.recent.Len() != 1 {
+		t.Errorf("Expected 1 cached document, got %d", renderer.recent.Len())
+	}
+
+	// The least recently used document is evicted
+	renderer.Render("# Two")
+	renderer.Render("# One")
+	renderer.Render("# Three")
+	if renderer.recent.Len() != 2 {
+		t.Errorf("Expected 2 cached documents, got %d", renderer.recent.Len())
+	}
+	for source, expected := range map[string]bool{"# One": true, "# Two": false, "# Three": true} {
+		if _, ok := renderer.cached(cacheKey(source)); ok != expected {
+			t.Errorf("%s: expected cached to be %v", source, expected)
+		}
+	}
+
+	// Without a cache, nothing is kept
+	uncached := NewRenderer(0)
+	uncached.Render("# One")
+	if uncached.recent.Len() != 0 {
+		t.Errorf("Expected no cached documents, got %d", uncached.recent.Len())
+	}
+}
@@ -3,8 +3,13 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -13,8 +18,19 @@ type contextKey string
 // UserEmailKey is the key used to store the user email in the request context
 const UserEmailKey contextKey = "userEmail"
 
-// Auth is middleware that validates JWT tokens and adds the user email to the request context
-func Auth(config auth.Config) func(http.Handler) http.Handler {
+// PersonalTokenKey marks requests authenticated with a personal access token
+const PersonalTokenKey contextKey = "personalToken"
+
+// UserStore looks up users and personal access tokens
+type UserStore interface {
+	AuthenticatePersonalToken(hash string, now time.Time) (*db.PersonalToken, error)
+	GetInternalUserByEmail(email string) (*db.InternalUser, error)
+}
+
+// Auth is middleware that validates JWT tokens and adds the user email to the request context.
+// Personal access tokens and the state of accounts are looked up in users; if users is nil
+// personal access tokens are rejected and accounts are not checked.
+func Auth(config auth.Config, users UserStore) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Skip authentication for certain endpoints
@@ -28,6 +44,31 @@ func Auth(config auth.Config) func(http.Handler) http.Handler {
 				next.ServeHTTP(w, r)
 				return
 			}
+			if r.URL.Path == "/api/users/login/two-factor" && r.Method == http.MethodPost {
+				// Second step of a two-factor login, authenticated by the challenge token
+				next.ServeHTTP(w, r)
+				return
+			}
+			if (r.URL.Path == "/api/users/password-reset" || r.URL.Path == "/api/users/password-reset/confirm") && r.Method == http.MethodPost {
+				// Password reset, authenticated by the emailed token
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.URL.Path == "/api/users/verify-email" && r.Method == http.MethodPost {
+				// Email verification, authenticated by the emailed token
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.URL.Path == "/api/users/logout" && r.Method == http.MethodPost {
+				// Logout endpoint
+				next.ServeHTTP(w, r)
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, "/api/auth/") && r.Method == http.MethodGet {
+				// Sign in with external identity providers
+				next.ServeHTTP(w, r)
+				return
+			}
 			if r.URL.Path == "/api/tags" && r.Method == http.MethodGet {
 				// Tags endpoint (public)
 				next.ServeHTTP(w, r)
@@ -44,15 +85,33 @@ func Auth(config auth.Config) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Extract token from request
+			// Extract token from request, falling back to the session cookie in cookie mode
 			tokenString, err := auth.ExtractTokenFromRequest(r)
+			fromCookie := false
+			if err != nil && config.Mode == auth.ModeCookie {
+				tokenString, err = auth.ExtractTokenFromCookie(r, config)
+				fromCookie = err == nil
+			}
 			if err != nil {
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
 			}
 
+			// Personal access tokens are only accepted in the Authorization header
+			if !fromCookie && auth.IsPersonalToken(tokenString) {
+				authenticatePersonalToken(w, r, next, users, tokenString)
+				return
+			}
+
+			// Cookies are sent by the browser automatically, so state-changing
+			// requests have to prove they can read the CSRF cookie
+			if fromCookie && !auth.VerifyCSRF(r, config) {
+				http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+				return
+			}
+
 			// Validate token
-			email, err := auth.ValidateToken(tokenString, config)
+			claims, err := auth.ValidateTokenClaims(tokenString, config)
 			if err != nil {
 				if err == auth.ErrExpiredToken {
 					http.Error(w, "Token expired", http.StatusUnauthorized)
@@ -62,13 +121,81 @@ func Auth(config auth.Config) func(http.Handler) http.Handler {
 				return
 			}
 
+			// Check that the account may still use the API
+			if users != nil && !checkAccount(w, users, claims.Email, claims.IssuedAt) {
+				return
+			}
+
 			// Add user email to context
-			ctx := context.WithValue(r.Context(), UserEmailKey, email)
+			ctx := context.WithValue(r.Context(), UserEmailKey, claims.Email)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// checkAccount rejects requests of deleted and suspended accounts, and
+// session tokens that were revoked. issuedAt is nil for personal access
+// tokens, which are deleted when revoked.
+func checkAccount(w http.ResponseWriter, users UserStore, email string, issuedAt *jwt.NumericDate) bool {
+	user, err := users.GetInternalUserByEmail(email)
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return false
+	}
+
+	// JWTs only carry whole seconds
+	if issuedAt != nil && issuedAt.Before(user.SessionsValidAt.Truncate(time.Second)) {
+		http.Error(w, "Token revoked", http.StatusUnauthorized)
+		return false
+	}
+
+	if user.Suspended() {
+		http.Error(w, "Account suspended", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// authenticatePersonalToken checks a personal access token and its scopes
+// before passing the request on
+func authenticatePersonalToken(w http.ResponseWriter, r *http.Request, next http.Handler, users UserStore, tokenString string) {
+	if users == nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	token, err := users.AuthenticatePersonalToken(auth.HashOneTimeToken(tokenString), time.Now())
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	// Check that the token was granted access to the endpoint
+	scope, ok := RequiredScope(r.Method, r.URL.Path)
+	if !ok || (scope != "" && !slices.Contains(token.Scopes, scope)) {
+		http.Error(w, "Insufficient token scope", http.StatusForbidden)
+		return
+	}
+
+	if !checkAccount(w, users, token.Email, nil) {
+		return
+	}
+
+	// Add user email to context, and that it's only a personal access token
+	ctx := context.WithValue(r.Context(), UserEmailKey, token.Email)
+	ctx = context.WithValue(ctx, PersonalTokenKey, true)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// IsPersonalToken reports whether the request was authenticated with a
+// personal access token. Such requests must not be handed session tokens,
+// which aren't limited to the scopes of the personal access token.
+func IsPersonalToken(r *http.Request) bool {
+	personal, _ := r.Context().Value(PersonalTokenKey).(bool)
+	return personal
+}
+
 // GetUserEmail extracts the user email from the request context
 func GetUserEmail(r *http.Request) (string, bool) {
 	email, ok := r.Context().Value(UserEmailKey).(string)
@@ -7,7 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/denga/go-real-world-example/api"
 	"github.com/denga/go-real-world-example/internal/auth"
+	"github.com/denga/go-real-world-example/internal/db"
 )
 
 func TestAuth(t *testing.T) {
@@ -30,7 +32,7 @@ func TestAuth(t *testing.T) {
 	})
 
 	// Create the middleware
-	middleware := Auth(config)
+	middleware := Auth(config, nil)
 
 	// Create a test server with the middleware
 	ts := httptest.NewServer(middleware(testHandler))
@@ -77,7 +79,7 @@ func TestAuthWithInvalidToken(t *testing.T) {
 	})
 
 	// Create the middleware
-	middleware := Auth(config)
+	middleware := Auth(config, nil)
 
 	// Create a test server with the middleware
 	ts := httptest.NewServer(middleware(testHandler))
@@ -118,7 +120,7 @@ func TestAuthWithMissingToken(t *testing.T) {
 	})
 
 	// Create the middleware
-	middleware := Auth(config)
+	middleware := Auth(config, nil)
 
 	// Create a test server with the middleware
 	ts := httptest.NewServer(middleware(testHandler))
@@ -157,7 +159,7 @@ func TestAuthWithPublicEndpoints(t *testing.T) {
 	})
 
 	// Create the middleware
-	middleware := Auth(config)
+	middleware := Auth(config, nil)
 
 	// Test public endpoints
 	publicEndpoints := []struct {
@@ -166,6 +168,14 @@ func TestAuthWithPublicEndpoints(t *testing.T) {
 	}{
 		{"POST", "/api/users"},
 		{"POST", "/api/users/login"},
+		{"POST", "/api/users/login/two-factor"},
+		{"POST", "/api/users/logout"},
+		{"POST", "/api/users/password-reset"},
+		{"POST", "/api/users/password-reset/confirm"},
+		{"POST", "/api/users/verify-email"},
+		{"GET", "/api/auth/providers"},
+		{"GET", "/api/auth/company/login"},
+		{"GET", "/api/auth/company/callback"},
 		{"GET", "/api/tags"},
 		{"GET", "/api/articles"},
 		{"GET", "/openapi.yml"},
@@ -200,6 +210,103 @@ func TestAuthWithPublicEndpoints(t *testing.T) {
 	}
 }
 
+func TestAuthWithSessionCookie(t *testing.T) {
+	// Create auth config with cookie mode enabled
+	config := auth.Config{
+		Secret:      "test-secret-key",
+		TokenExpiry: 1 * time.Hour,
+		Mode:        auth.ModeCookie,
+		Cookie:      auth.DefaultCookieConfig(),
+	}
+
+	// Create a test handler that checks if the user email is in the context
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		email, ok := GetUserEmail(r)
+		if !ok || email != "test@example.com" {
+			t.Errorf("Expected email 'test@example.com' in context, got '%s'", email)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Create a test server with the middleware
+	ts := httptest.NewServer(Auth(config, nil)(testHandler))
+	defer ts.Close()
+
+	// Generate a valid token
+	token, err := auth.GenerateToken("test@example.com", config)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		csrf     string
+		expected int
+	}{
+		{name: "Safe request without CSRF token", method: "GET", expected: http.StatusOK},
+		{name: "Unsafe request without CSRF token", method: "POST", expected: http.StatusForbidden},
+		{name: "Unsafe request with wrong CSRF token", method: "PUT", csrf: "wrong", expected: http.StatusForbidden},
+		{name: "Unsafe request with CSRF token", method: "DELETE", csrf: "csrf-token", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create a request carrying the session and CSRF cookies
+			req, err := http.NewRequest(tt.method, ts.URL, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			req.AddCookie(&http.Cookie{Name: config.Cookie.SessionName, Value: token})
+			req.AddCookie(&http.Cookie{Name: config.Cookie.CSRFName, Value: "csrf-token"})
+			if tt.csrf != "" {
+				req.Header.Set(auth.CSRFHeader, tt.csrf)
+			}
+
+			// Send the request
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			// Check the response
+			if resp.StatusCode != tt.expected {
+				t.Errorf("Expected status code %d, got %d", tt.expected, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAuthIgnoresCookieInTokenMode(t *testing.T) {
+	// Create auth config in the default token mode
+	config := auth.Config{
+		Secret:      "test-secret-key",
+		TokenExpiry: 1 * time.Hour,
+		Mode:        auth.ModeToken,
+		Cookie:      auth.DefaultCookieConfig(),
+	}
+
+	// Create a test handler that should not be called
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not be called with a cookie in token mode")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Create a request carrying only a session cookie
+	token, _ := auth.GenerateToken("test@example.com", config)
+	req := httptest.NewRequest("GET", "/api/user", nil)
+	req.AddCookie(&http.Cookie{Name: config.Cookie.SessionName, Value: token})
+
+	rr := httptest.NewRecorder()
+	Auth(config, nil)(testHandler).ServeHTTP(rr, req)
+
+	// Check the response
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
+
 func TestGetUserEmail(t *testing.T) {
 	// Create a request with a context containing a user email
 	req, _ := http.NewRequest("GET", "/", nil)
@@ -225,3 +332,141 @@ func TestGetUserEmail(t *testing.T) {
 		t.Errorf("Expected empty email for missing email, got '%s'", email)
 	}
 }
+
+func TestAuthWithPersonalToken(t *testing.T) {
+	// Create auth config
+	config := auth.Config{
+		Secret:      "test-secret-key",
+		TokenExpiry: 1 * time.Hour,
+	}
+
+	// Create a database with a token that may write articles
+	store := db.NewInMemoryDB()
+	store.CreateUser(api.User{Username: "ci", Email: "ci@example.com"}, "password123")
+	token, _ := auth.GeneratePersonalToken()
+	store.CreatePersonalToken(db.PersonalToken{
+		Hash:   auth.HashOneTimeToken(token),
+		Email:  "ci@example.com",
+		Name:   "CI",
+		Scopes: []string{auth.ScopeArticlesWrite},
+	})
+
+	// Create a test handler that checks if the user email is in the context
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		email, ok := GetUserEmail(r)
+		if !ok || email != "ci@example.com" {
+			t.Errorf("Expected email 'ci@example.com' in context, got '%s'", email)
+		}
+		if !IsPersonalToken(r) {
+			t.Error("Expected the request to be marked as authenticated with a personal token")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		expected int
+	}{
+		{name: "Granted scope", method: "POST", path: "/api/articles", token: token, expected: http.StatusOK},
+		{name: "Read without scope", method: "GET", path: "/api/articles/feed", token: token, expected: http.StatusOK},
+		{name: "Missing scope", method: "POST", path: "/api/articles/my-article/comments", token: token, expected: http.StatusForbidden},
+		{name: "Endpoint not available to tokens", method: "POST", path: "/api/user/tokens", token: token, expected: http.StatusForbidden},
+		{name: "Unknown token", method: "POST", path: "/api/articles", token: auth.PersonalTokenPrefix + "unknown", expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Token "+tt.token)
+
+			rr := httptest.NewRecorder()
+			Auth(config, store)(testHandler).ServeHTTP(rr, req)
+
+			// Check the response
+			if rr.Code != tt.expected {
+				t.Errorf("Expected status code %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+
+	// The use is recorded
+	tokens := store.ListPersonalTokens("ci@example.com")
+	if tokens[0].LastUsedAt == nil {
+		t.Error("Expected last use to be recorded")
+	}
+
+	// Without a token store personal tokens are rejected
+	req := httptest.NewRequest("POST", "/api/articles", nil)
+	req.Header.Set("Authorization", "Token "+token)
+	rr := httptest.NewRecorder()
+	Auth(config, nil)(testHandler).ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
+
+func TestAuthChecksAccount(t *testing.T) {
+	// Create auth config
+	config := auth.Config{
+		Secret:      "test-secret-key",
+		TokenExpiry: 1 * time.Hour,
+	}
+
+	// Create a database with an active and a suspended user
+	store := db.NewInMemoryDB()
+	store.CreateUser(api.User{Username: "active", Email: "active@example.com"}, "password123")
+	store.CreateUser(api.User{Username: "suspended", Email: "suspended@example.com"}, "password123")
+	store.SuspendUser("suspended@example.com", "spam", time.Now())
+	store.CreateUser(api.User{Username: "revoked", Email: "revoked@example.com"}, "password123")
+
+	// A personal token of the suspended user
+	personalToken, _ := auth.GeneratePersonalToken()
+	store.CreatePersonalToken(db.PersonalToken{
+		Hash:   auth.HashOneTimeToken(personalToken),
+		Email:  "suspended@example.com",
+		Scopes: []string{auth.ScopeArticlesWrite},
+	})
+
+	// Tokens issued before the sessions were revoked
+	revokedToken, _ := auth.GenerateToken("revoked@example.com", config)
+	store.RevokeCredentials("revoked@example.com", time.Now().Add(time.Second))
+
+	activeToken, _ := auth.GenerateToken("active@example.com", config)
+	suspendedToken, _ := auth.GenerateToken("suspended@example.com", config)
+	deletedToken, _ := auth.GenerateToken("deleted@example.com", config)
+
+	// Create a test handler
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		token    string
+		expected int
+	}{
+		{name: "Active user", token: activeToken, expected: http.StatusOK},
+		{name: "Suspended user", token: suspendedToken, expected: http.StatusForbidden},
+		{name: "Suspended user with personal token", token: personalToken, expected: http.StatusForbidden},
+		{name: "Deleted user", token: deletedToken, expected: http.StatusUnauthorized},
+		{name: "Revoked session", token: revokedToken, expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/articles", nil)
+			req.Header.Set("Authorization", "Token "+tt.token)
+
+			rr := httptest.NewRecorder()
+			Auth(config, store)(testHandler).ServeHTTP(rr, req)
+
+			// Check the response
+			if rr.Code != tt.expected {
+				t.Errorf("Expected status code %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIPKey is the key used to store the client IP in the request context
+const ClientIPKey contextKey = "clientIP"
+
+// ClientIP is middleware that determines the IP address of the client and
+// adds it to the request context. Requests from trusted proxies are
+// attributed to the address they forwarded for: X-Forwarded-For is read from
+// right to left, skipping trusted proxies, so that clients can't spoof their
+// address by sending the header themselves.
+func ClientIP(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trustedProxies)
+			ctx := context.WithValue(r.Context(), ClientIPKey, ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetClientIP returns the client IP determined by the ClientIP middleware,
+// falling back to the remote address of the connection
+func GetClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(ClientIPKey).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+// resolveClientIP walks the chain of proxies back to the first untrusted address
+func resolveClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	ip := remoteIP(r)
+	if !isTrusted(ip, trustedProxies) {
+		return ip
+	}
+
+	var forwarded []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			// Garbage can't be attributed, stop at the last proxy we trust
+			break
+		}
+		ip = addr.Unmap().String()
+		if !isTrusted(ip, trustedProxies) {
+			break
+		}
+	}
+
+	return ip
+}
+
+// isTrusted reports whether ip belongs to a trusted proxy
+func isTrusted(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns the IP address of the connection
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
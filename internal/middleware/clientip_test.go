@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("::1/128"),
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		expected   string
+	}{
+		{name: "Direct client", remoteAddr: "203.0.113.7:1234", expected: "203.0.113.7"},
+		{name: "Untrusted client spoofing the header", remoteAddr: "203.0.113.7:1234", forwarded: []string{"198.51.100.1"}, expected: "203.0.113.7"},
+		{name: "Trusted proxy", remoteAddr: "10.0.0.1:1234", forwarded: []string{"198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "Chain of trusted proxies", remoteAddr: "10.0.0.1:1234", forwarded: []string{"198.51.100.1, 10.0.0.2"}, expected: "198.51.100.1"},
+		{name: "Spoofed entry before the real client", remoteAddr: "10.0.0.1:1234", forwarded: []string{"192.0.2.9, 198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "Several headers", remoteAddr: "10.0.0.1:1234", forwarded: []string{"198.51.100.1", "10.0.0.2"}, expected: "198.51.100.1"},
+		{name: "Only proxies", remoteAddr: "10.0.0.1:1234", forwarded: []string{"10.0.0.2"}, expected: "10.0.0.2"},
+		{name: "Garbage", remoteAddr: "10.0.0.1:1234", forwarded: []string{"unknown"}, expected: "10.0.0.1"},
+		{name: "Trusted proxy without header", remoteAddr: "10.0.0.1:1234", expected: "10.0.0.1"},
+		{name: "IPv6 proxy", remoteAddr: "[::1]:1234", forwarded: []string{"2001:db8::1"}, expected: "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := ClientIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = GetClientIP(r)
+			}))
+
+			req := httptest.NewRequest("GET", "/api/articles", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.expected {
+				t.Errorf("Expected client IP %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetClientIPWithoutMiddleware(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/articles", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	// Without the middleware the connection address is used
+	if ip := GetClientIP(req); ip != "203.0.113.7" {
+		t.Errorf("Expected client IP 203.0.113.7, got %s", ip)
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/cors"
+)
+
+// CORSConfig holds the cross-origin resource sharing configuration
+type CORSConfig struct {
+	// AllowedOrigins lists the origins that may call the API from a browser,
+	// like "https://app.example.com". A "*" can stand for one subdomain level,
+	// or for any origin if credentials are not allowed. Empty means
+	// same-origin only, which is all the embedded frontend needs.
+	AllowedOrigins []string
+	// AllowedMethods lists the methods allowed in cross-origin requests
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers allowed in cross-origin requests
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers scripts of other origins may read
+	ExposedHeaders []string
+	// AllowCredentials allows cross-origin requests with cookies
+	AllowCredentials bool
+	// MaxAge is how many seconds browsers may cache preflight responses
+	MaxAge int
+}
+
+// DefaultCORSConfig returns a configuration that only allows same-origin requests
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		ExposedHeaders:   []string{"Link", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	}
+}
+
+// Validate checks that the configuration is safe and that browsers will accept it
+func (c CORSConfig) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				return errors.New("the origin * can't be combined with credentials")
+			}
+			continue
+		}
+
+		u, err := url.Parse(strings.Replace(origin, "*.", "wildcard.", 1))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			return fmt.Errorf("invalid origin %q: must look like https://app.example.com", origin)
+		}
+	}
+	if c.MaxAge < 0 {
+		return errors.New("max age must not be negative")
+	}
+	return nil
+}
+
+// CORS is middleware that answers preflight requests and sets the CORS
+// headers for the configured origins. Without allowed origins it does
+// nothing, so browsers keep blocking cross-origin requests.
+func CORS(config CORSConfig) func(http.Handler) http.Handler {
+	if len(config.AllowedOrigins) == 0 {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+
+	origins := make([]string, len(config.AllowedOrigins))
+	for i, origin := range config.AllowedOrigins {
+		origins[i] = strings.TrimSuffix(origin, "/")
+	}
+
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   origins,
+		AllowedMethods:   config.AllowedMethods,
+		AllowedHeaders:   config.AllowedHeaders,
+		ExposedHeaders:   config.ExposedHeaders,
+		AllowCredentials: config.AllowCredentials,
+		MaxAge:           config.MaxAge,
+	})
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// preflight sends a CORS preflight request from origin through handler
+func preflight(handler http.Handler, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("OPTIONS", "/api/articles", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCORSSameOriginByDefault(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := CORS(DefaultCORSConfig())(testHandler)
+
+	// No origin is allowed, browsers block the response
+	rr := preflight(handler, "https://evil.example.com")
+	if origin := rr.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+		t.Errorf("Expected no Access-Control-Allow-Origin, got %q", origin)
+	}
+}
+
+func TestCORSAllowedOrigins(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"https://app.example.com/", "https://*.preview.example.com"}
+	config.MaxAge = 600
+	handler := CORS(config)(testHandler)
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://pr-1.preview.example.com", true},
+		{"http://app.example.com", false},
+		{"https://evil.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			rr := preflight(handler, tt.origin)
+
+			allowOrigin := rr.Header().Get("Access-Control-Allow-Origin")
+			if tt.allowed {
+				// The origin is echoed, never "*", so that credentials work
+				if allowOrigin != tt.origin {
+					t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.origin, allowOrigin)
+				}
+				if rr.Header().Get("Access-Control-Allow-Credentials") != "true" {
+					t.Error("Expected credentials to be allowed")
+				}
+				if rr.Header().Get("Access-Control-Max-Age") != "600" {
+					t.Errorf("Expected max age 600, got %q", rr.Header().Get("Access-Control-Max-Age"))
+				}
+			} else if allowOrigin != "" {
+				t.Errorf("Expected no Access-Control-Allow-Origin, got %q", allowOrigin)
+			}
+		})
+	}
+}
+
+func TestCORSConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		creds   bool
+		valid   bool
+	}{
+		{name: "Same origin", valid: true, creds: true},
+		{name: "Origins", origins: []string{"https://app.example.com", "http://localhost:3000"}, creds: true, valid: true},
+		{name: "Subdomain wildcard", origins: []string{"https://*.example.com"}, creds: true, valid: true},
+		{name: "Any origin without credentials", origins: []string{"*"}, valid: true},
+		{name: "Any origin with credentials", origins: []string{"*"}, creds: true},
+		{name: "Missing scheme", origins: []string{"app.example.com"}},
+		{name: "Path", origins: []string{"https://app.example.com/app"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultCORSConfig()
+			config.AllowedOrigins = tt.origins
+			config.AllowCredentials = tt.creds
+
+			err := config.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid config, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denga/go-real-world-example/internal/ratelimit"
+)
+
+// RateLimit is middleware that limits requests with token buckets. Every rule
+// matching a request takes a token from the bucket of the request's principal;
+// if any bucket is empty the request is rejected with 429. The RateLimit-*
+// headers describe the most constrained bucket. It has to run after Auth to
+// count requests per user.
+func RateLimit(rules []ratelimit.Rule, store ratelimit.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+
+			var tightest *ratelimit.Result
+			var rejected *ratelimit.Result
+			for _, rule := range rules {
+				if !rule.Matches(r.Method, r.URL.Path) {
+					continue
+				}
+
+				result, err := store.Take(rule.String()+"|"+principal(r, rule.By), rule.Limit, now)
+				if err != nil {
+					// A broken store must not take the API down
+					log.Printf("Error checking rate limit %s: %v", rule, err)
+					continue
+				}
+
+				if !result.Allowed && (rejected == nil || result.RetryAfter > rejected.RetryAfter) {
+					rejected = &result
+				}
+				if tightest == nil || result.Remaining < tightest.Remaining {
+					tightest = &result
+				}
+			}
+
+			if rejected != nil {
+				writeRateLimitHeaders(w, *rejected)
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(rejected.RetryAfter)))
+				http.Error(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+			if tightest != nil {
+				writeRateLimitHeaders(w, *tightest)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// principal returns whose bucket a request takes a token from
+func principal(r *http.Request, by ratelimit.Principal) string {
+	if by == ratelimit.ByUser {
+		if email, ok := GetUserEmail(r); ok {
+			return "user:" + email
+		}
+	}
+	return "ip:" + GetClientIP(r)
+}
+
+// writeRateLimitHeaders sets the RateLimit-* headers for a bucket
+func writeRateLimitHeaders(w http.ResponseWriter, result ratelimit.Result) {
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/internal/ratelimit"
+)
+
+// failingStore is a rate limit store whose backend is down
+type failingStore struct{}
+
+func (failingStore) Take(key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+// rateLimitedRequest sends a request through handler and returns the response recorder
+func rateLimitedRequest(handler http.Handler, method, path, remoteAddr, email string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = remoteAddr
+	if email != "" {
+		req = req.WithContext(context.WithValue(req.Context(), UserEmailKey, email))
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimit(t *testing.T) {
+	rules := []ratelimit.Rule{
+		{Method: "POST", Pattern: "/api/users/login", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 2, Period: time.Minute}},
+		{Method: "POST", Pattern: "/api/articles", By: ratelimit.ByUser, Limit: ratelimit.Limit{Requests: 1, Period: time.Hour}},
+	}
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RateLimit(rules, ratelimit.NewMemoryStore())(testHandler)
+
+	// Logins are limited per IP
+	rr := rateLimitedRequest(handler, "POST", "/api/users/login", "203.0.113.7:1234", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr.Header().Get("RateLimit-Limit") != "2" || rr.Header().Get("RateLimit-Remaining") != "1" || rr.Header().Get("RateLimit-Reset") != "30" {
+		t.Errorf("Unexpected rate limit headers: %v", rr.Header())
+	}
+	rateLimitedRequest(handler, "POST", "/api/users/login", "203.0.113.7:1234", "")
+
+	rr = rateLimitedRequest(handler, "POST", "/api/users/login", "203.0.113.7:1234", "")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status %d, got %d", http.StatusTooManyRequests, rr.Code)
+	}
+	if rr.Header().Get("Retry-After") != "30" || rr.Header().Get("RateLimit-Remaining") != "0" {
+		t.Errorf("Unexpected headers: %v", rr.Header())
+	}
+
+	// Other clients have their own bucket
+	if rr := rateLimitedRequest(handler, "POST", "/api/users/login", "198.51.100.1:1234", ""); rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d for another IP, got %d", http.StatusOK, rr.Code)
+	}
+
+	// Articles are limited per user, wherever they come from
+	if rr := rateLimitedRequest(handler, "POST", "/api/articles", "203.0.113.7:1234", "jane@example.com"); rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr := rateLimitedRequest(handler, "POST", "/api/articles", "198.51.100.1:1234", "jane@example.com"); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, rr.Code)
+	}
+	if rr := rateLimitedRequest(handler, "POST", "/api/articles", "198.51.100.1:1234", "john@example.com"); rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d for another user, got %d", http.StatusOK, rr.Code)
+	}
+
+	// Requests without a matching rule pass without headers
+	rr = rateLimitedRequest(handler, "GET", "/api/articles", "203.0.113.7:1234", "")
+	if rr.Code != http.StatusOK || rr.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("Expected unlimited request, got %d with %v", rr.Code, rr.Header())
+	}
+}
+
+func TestRateLimitTightestBucket(t *testing.T) {
+	rules := []ratelimit.Rule{
+		{Pattern: "/api/**", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 100, Period: time.Minute}},
+		{Method: "POST", Pattern: "/api/articles", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 5, Period: time.Minute}},
+	}
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RateLimit(rules, ratelimit.NewMemoryStore())(testHandler)
+
+	// The headers describe the rule closest to its limit
+	rr := rateLimitedRequest(handler, "POST", "/api/articles", "203.0.113.7:1234", "")
+	if rr.Header().Get("RateLimit-Limit") != "5" || rr.Header().Get("RateLimit-Remaining") != "4" {
+		t.Errorf("Unexpected rate limit headers: %v", rr.Header())
+	}
+}
+
+func TestRateLimitStoreFailure(t *testing.T) {
+	rules := []ratelimit.Rule{
+		{Pattern: "/api/**", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 1, Period: time.Minute}},
+	}
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RateLimit(rules, failingStore{})(testHandler)
+
+	// Requests are let through when the store is unavailable
+	if rr := rateLimitedRequest(handler, "GET", "/api/tags", "203.0.113.7:1234", ""); rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+// RequireRole is middleware that restricts the paths starting with prefix to
+// users with the given role. It has to run after Auth. Handlers still check
+// the policy for each action; this keeps route groups like the admin API
+// closed even if a handler forgets to.
+func RequireRole(users UserStore, prefix string, role policy.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Get authenticated user from context
+			email, ok := GetUserEmail(r)
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			user, err := users.GetInternalUserByEmail(email)
+			if err != nil || user.Role != role {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
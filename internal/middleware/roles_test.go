@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/policy"
+)
+
+func TestRequireRole(t *testing.T) {
+	// Create a database with an admin and a moderator
+	store := db.NewInMemoryDB()
+	store.CreateUser(api.User{Username: "admin", Email: "admin@example.com"}, "password123")
+	store.SetUserRole("admin@example.com", policy.RoleAdmin)
+	store.CreateUser(api.User{Username: "moderator", Email: "moderator@example.com"}, "password123")
+	store.SetUserRole("moderator@example.com", policy.RoleModerator)
+
+	// Create a test handler
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RequireRole(store, "/api/admin/", policy.RoleAdmin)(testHandler)
+
+	tests := []struct {
+		name     string
+		path     string
+		email    string
+		expected int
+	}{
+		{name: "Admin", path: "/api/admin/users", email: "admin@example.com", expected: http.StatusOK},
+		{name: "Moderator", path: "/api/admin/users", email: "moderator@example.com", expected: http.StatusForbidden},
+		{name: "Unknown user", path: "/api/admin/users", email: "unknown@example.com", expected: http.StatusForbidden},
+		{name: "Unauthenticated", path: "/api/admin/users", expected: http.StatusUnauthorized},
+		{name: "Other path", path: "/api/articles", email: "moderator@example.com", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.email != "" {
+				req = req.WithContext(context.WithValue(req.Context(), UserEmailKey, tt.email))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("Expected status code %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/denga/go-real-world-example/internal/auth"
+)
+
+// RequiredScope returns the scope a personal access token needs for a request.
+// Reading public content needs no scope. ok is false for endpoints that
+// personal access tokens can't be used for at all, like account settings,
+// token management and the admin endpoints.
+func RequiredScope(method, path string) (scope string, ok bool) {
+	rest, found := strings.CutPrefix(path, "/api/")
+	if !found {
+		return "", false
+	}
+	segments := strings.Split(strings.TrimSuffix(rest, "/"), "/")
+	read := method == http.MethodGet || method == http.MethodHead
+
+	switch segments[0] {
+	case "tags":
+		switch {
+		case len(segments) == 1:
+			return "", read
+		case len(segments) == 3 && segments[2] == "follow":
+			return auth.ScopeProfilesWrite, method == http.MethodPost || method == http.MethodDelete
+		}
+	case "user":
+		return auth.ScopeUserRead, read && (len(segments) == 1 || len(segments) == 2 && segments[1] == "drafts")
+	case "profiles":
+		switch {
+		case len(segments) == 2:
+			return "", read
+		case len(segments) == 3 && segments[2] == "follow":
+			return auth.ScopeProfilesWrite, method == http.MethodPost || method == http.MethodDelete
+		}
+	case "articles":
+		if read {
+			return "", true
+		}
+		switch {
+		case len(segments) == 1:
+			return auth.ScopeArticlesWrite, method == http.MethodPost
+		case len(segments) == 2:
+			return auth.ScopeArticlesWrite, method == http.MethodPut || method == http.MethodDelete
+		case len(segments) == 3 && segments[2] == "comments":
+			return auth.ScopeCommentsWrite, method == http.MethodPost
+		case len(segments) == 4 && segments[2] == "comments":
+			return auth.ScopeCommentsWrite, method == http.MethodDelete
+		case len(segments) == 3 && segments[2] == "favorite":
+			return auth.ScopeFavoritesWrite, method == http.MethodPost || method == http.MethodDelete
+		case len(segments) == 5 && segments[2] == "revisions" && segments[4] == "restore":
+			return auth.ScopeArticlesWrite, method == http.MethodPost
+		}
+	}
+
+	return "", false
+}
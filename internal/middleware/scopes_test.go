@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/denga/go-real-world-example/internal/auth"
+)
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		scope  string
+		ok     bool
+	}{
+		// Reading public content needs no scope
+		{"GET", "/api/articles", "", true},
+		{"GET", "/api/articles/feed", "", true},
+		{"GET", "/api/articles/my-article", "", true},
+		{"GET", "/api/articles/my-article/comments", "", true},
+		{"GET", "/api/articles/my-article/revisions/1/diff", "", true},
+		{"GET", "/api/profiles/jane", "", true},
+		{"GET", "/api/tags", "", true},
+
+		// Writes need the matching scope
+		{"GET", "/api/user", auth.ScopeUserRead, true},
+		{"GET", "/api/user/drafts", auth.ScopeUserRead, true},
+		{"POST", "/api/articles", auth.ScopeArticlesWrite, true},
+		{"PUT", "/api/articles/my-article", auth.ScopeArticlesWrite, true},
+		{"DELETE", "/api/articles/my-article", auth.ScopeArticlesWrite, true},
+		{"POST", "/api/articles/my-article/comments", auth.ScopeCommentsWrite, true},
+		{"DELETE", "/api/articles/my-article/comments/1", auth.ScopeCommentsWrite, true},
+		{"POST", "/api/articles/my-article/favorite", auth.ScopeFavoritesWrite, true},
+		{"DELETE", "/api/articles/my-article/favorite", auth.ScopeFavoritesWrite, true},
+		{"POST", "/api/articles/my-article/revisions/1/restore", auth.ScopeArticlesWrite, true},
+		{"POST", "/api/profiles/jane/follow", auth.ScopeProfilesWrite, true},
+		{"DELETE", "/api/profiles/jane/follow", auth.ScopeProfilesWrite, true},
+		{"POST", "/api/tags/go/follow", auth.ScopeProfilesWrite, true},
+		{"DELETE", "/api/tags/go/follow", auth.ScopeProfilesWrite, true},
+
+		// Account settings, token management and admin endpoints are off limits
+		{"PUT", "/api/user", "", false},
+		{"DELETE", "/api/user", "", false},
+		{"GET", "/api/user/export", "", false},
+		{"GET", "/api/user/tokens", "", false},
+		{"POST", "/api/user/tokens", "", false},
+		{"DELETE", "/api/user/two-factor", "", false},
+		{"GET", "/api/admin/lockouts", "", false},
+		{"POST", "/api/tags", "", false},
+		{"GET", "/api/tags/go/follow", "", false},
+		{"GET", "/openapi.yml", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			scope, ok := RequiredScope(tt.method, tt.path)
+			if ok != tt.ok {
+				t.Errorf("Expected ok %v, got %v", tt.ok, ok)
+			}
+			if ok && scope != tt.scope {
+				t.Errorf("Expected scope %q, got %q", tt.scope, scope)
+			}
+		})
+	}
+}
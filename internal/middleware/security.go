@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ScriptHashesPlaceholder is replaced by the hashes of the frontend's inline
+// scripts in a Content-Security-Policy
+const ScriptHashesPlaceholder = "{script-hashes}"
+
+// DefaultContentSecurityPolicy is the policy for the embedded frontend. The
+// Next.js static export bootstraps with inline scripts, which are allowed by
+// hash; styles are inlined into attributes, which can't be hashed. Images
+// may come from any HTTPS URL because profile images are external links.
+const DefaultContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' " + ScriptHashesPlaceholder + "; " +
+	"style-src 'self' 'unsafe-inline'; " +
+	"img-src 'self' data: https:; " +
+	"font-src 'self' data:; " +
+	"connect-src 'self'; " +
+	"object-src 'none'; " +
+	"base-uri 'self'; " +
+	"form-action 'self'; " +
+	"frame-ancestors 'none'"
+
+// apiContentSecurityPolicy is the policy for API responses, which are data
+// and must never run anything if a browser renders them
+const apiContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// SecurityHeadersConfig holds the configuration of the security headers
+type SecurityHeadersConfig struct {
+	// ContentSecurityPolicy is the policy of the frontend pages. The
+	// placeholder {script-hashes} is replaced by the hashes of the inline
+	// scripts. Empty disables the header.
+	ContentSecurityPolicy string
+	// ReportOnly sends the policy as Content-Security-Policy-Report-Only, so
+	// that violations are reported but not blocked
+	ReportOnly bool
+	// FrameOptions is the X-Frame-Options value, DENY or SAMEORIGIN. Empty
+	// omits the header.
+	FrameOptions string
+	// ReferrerPolicy is the Referrer-Policy value, empty omits the header
+	ReferrerPolicy string
+	// HSTSMaxAge is how many seconds browsers should only use HTTPS, zero
+	// omits Strict-Transport-Security. Only enable it when served over HTTPS.
+	HSTSMaxAge int
+	// HSTSIncludeSubdomains applies Strict-Transport-Security to subdomains
+	HSTSIncludeSubdomains bool
+}
+
+// DefaultHSTSMaxAge is the Strict-Transport-Security max age used when the
+// server serves HTTPS itself, one year
+const DefaultHSTSMaxAge = 365 * 24 * 60 * 60
+
+// DefaultSecurityHeadersConfig returns the default security headers, without
+// HSTS since the server speaks plain HTTP by default
+func DefaultSecurityHeadersConfig() SecurityHeadersConfig {
+	return SecurityHeadersConfig{
+		ContentSecurityPolicy: DefaultContentSecurityPolicy,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+	}
+}
+
+// Validate checks that the headers are well-formed
+func (c SecurityHeadersConfig) Validate() error {
+	if strings.ContainsAny(c.ContentSecurityPolicy, "\r\n") {
+		return errors.New("content security policy must be a single line")
+	}
+	switch c.FrameOptions {
+	case "", "DENY", "SAMEORIGIN":
+	default:
+		return fmt.Errorf("invalid frame options %q: must be DENY or SAMEORIGIN", c.FrameOptions)
+	}
+	if c.HSTSMaxAge < 0 {
+		return errors.New("HSTS max age must not be negative")
+	}
+	return nil
+}
+
+// SecurityHeaders is middleware that sets the security headers on every
+// response. Frontend pages get the configured Content-Security-Policy with
+// scriptHashes filled in, the API and the OpenAPI spec get a policy that
+// allows nothing.
+func SecurityHeaders(config SecurityHeadersConfig, scriptHashes []string) func(http.Handler) http.Handler {
+	policy := strings.TrimSpace(strings.ReplaceAll(config.ContentSecurityPolicy, ScriptHashesPlaceholder, strings.Join(scriptHashes, " ")))
+	policyHeader := "Content-Security-Policy"
+	if config.ReportOnly {
+		policyHeader = "Content-Security-Policy-Report-Only"
+	}
+
+	hsts := ""
+	if config.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(config.HSTSMaxAge)
+		if config.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("X-Content-Type-Options", "nosniff")
+			if config.FrameOptions != "" {
+				header.Set("X-Frame-Options", config.FrameOptions)
+			}
+			if config.ReferrerPolicy != "" {
+				header.Set("Referrer-Policy", config.ReferrerPolicy)
+			}
+			if hsts != "" {
+				header.Set("Strict-Transport-Security", hsts)
+			}
+
+			if isAPIPath(r.URL.Path) {
+				header.Set("Content-Security-Policy", apiContentSecurityPolicy)
+			} else if policy != "" {
+				header.Set(policyHeader, policy)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAPIPath reports whether a path belongs to the API rather than the frontend
+func isAPIPath(path string) bool {
+	return path == "/api" || strings.HasPrefix(path, "/api/") || path == "/openapi.yml"
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// secureResponse sends a GET request for path through the SecurityHeaders middleware
+func secureResponse(config SecurityHeadersConfig, scriptHashes []string, path string) *httptest.ResponseRecorder {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := SecurityHeaders(config, scriptHashes)(testHandler)
+
+	req := httptest.NewRequest("GET", path, nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSecurityHeadersDefaults(t *testing.T) {
+	rr := secureResponse(DefaultSecurityHeadersConfig(), []string{"'sha256-abc='", "'sha256-def='"}, "/")
+
+	expected := map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        "DENY",
+		"Referrer-Policy":        "strict-origin-when-cross-origin",
+	}
+	for name, value := range expected {
+		if got := rr.Header().Get(name); got != value {
+			t.Errorf("Expected %s %q, got %q", name, value, got)
+		}
+	}
+
+	// HSTS is off by default
+	if hsts := rr.Header().Get("Strict-Transport-Security"); hsts != "" {
+		t.Errorf("Expected no Strict-Transport-Security, got %q", hsts)
+	}
+
+	// The script hashes replace the placeholder
+	policy := rr.Header().Get("Content-Security-Policy")
+	if !strings.Contains(policy, "script-src 'self' 'sha256-abc=' 'sha256-def=';") {
+		t.Errorf("Expected script hashes in policy, got %q", policy)
+	}
+	if strings.Contains(policy, ScriptHashesPlaceholder) {
+		t.Errorf("Expected placeholder to be replaced, got %q", policy)
+	}
+}
+
+func TestSecurityHeadersAPIPolicy(t *testing.T) {
+	for _, path := range []string{"/api/articles", "/openapi.yml"} {
+		rr := secureResponse(DefaultSecurityHeadersConfig(), nil, path)
+
+		if policy := rr.Header().Get("Content-Security-Policy"); policy != apiContentSecurityPolicy {
+			t.Errorf("%s: expected policy %q, got %q", path, apiContentSecurityPolicy, policy)
+		}
+		if options := rr.Header().Get("X-Content-Type-Options"); options != "nosniff" {
+			t.Errorf("%s: expected nosniff, got %q", path, options)
+		}
+	}
+
+	// Paths that merely start with "api" belong to the frontend
+	rr := secureResponse(DefaultSecurityHeadersConfig(), nil, "/api-docs")
+	if policy := rr.Header().Get("Content-Security-Policy"); policy == apiContentSecurityPolicy {
+		t.Errorf("Expected frontend policy for /api-docs, got %q", policy)
+	}
+}
+
+func TestSecurityHeadersConfigured(t *testing.T) {
+	config := SecurityHeadersConfig{
+		ContentSecurityPolicy: "default-src 'self'; script-src 'self' {script-hashes} https://cdn.example.com",
+		ReportOnly:            true,
+		FrameOptions:          "SAMEORIGIN",
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: true,
+	}
+	rr := secureResponse(config, []string{"'sha256-abc='"}, "/articles/hello")
+
+	if policy := rr.Header().Get("Content-Security-Policy"); policy != "" {
+		t.Errorf("Expected no enforced policy in report-only mode, got %q", policy)
+	}
+	expected := "default-src 'self'; script-src 'self' 'sha256-abc=' https://cdn.example.com"
+	if policy := rr.Header().Get("Content-Security-Policy-Report-Only"); policy != expected {
+		t.Errorf("Expected report-only policy %q, got %q", expected, policy)
+	}
+	if options := rr.Header().Get("X-Frame-Options"); options != "SAMEORIGIN" {
+		t.Errorf("Expected X-Frame-Options SAMEORIGIN, got %q", options)
+	}
+	if referrer := rr.Header().Get("Referrer-Policy"); referrer != "" {
+		t.Errorf("Expected no Referrer-Policy, got %q", referrer)
+	}
+	if hsts := rr.Header().Get("Strict-Transport-Security"); hsts != "max-age=31536000; includeSubDomains" {
+		t.Errorf("Expected HSTS with subdomains, got %q", hsts)
+	}
+}
+
+func TestSecurityHeadersConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SecurityHeadersConfig
+		valid  bool
+	}{
+		{"default", DefaultSecurityHeadersConfig(), true},
+		{"empty", SecurityHeadersConfig{}, true},
+		{"invalid frame options", SecurityHeadersConfig{FrameOptions: "ALLOW-FROM https://example.com"}, false},
+		{"negative HSTS max age", SecurityHeadersConfig{HSTSMaxAge: -1}, false},
+		{"multi-line policy", SecurityHeadersConfig{ContentSecurityPolicy: "default-src 'self'\nscript-src 'self'"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid config, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJSONWebKeyPublicKey(t *testing.T) {
+	// A small RSA key: n = 3233, e = 17
+	key := jsonWebKey{Kty: "RSA", Kid: "1", N: "DKE", E: "EQ"}
+
+	publicKey, err := key.publicKey()
+	if err != nil {
+		t.Fatalf("Failed to convert key: %v", err)
+	}
+	if publicKey.N.Int64() != 3233 || publicKey.E != 17 {
+		t.Errorf("Expected n=3233 e=17, got n=%d e=%d", publicKey.N.Int64(), publicKey.E)
+	}
+
+	// Other key types are not supported
+	if _, err := (jsonWebKey{Kty: "EC"}).publicKey(); err == nil {
+		t.Error("Expected an error for EC key, got nil")
+	}
+}
+
+func TestFetchKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys": [
+			{"kty": "RSA", "kid": "sig", "use": "sig", "n": "DKE", "e": "EQ"},
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": "DKE", "e": "EQ"},
+			{"kty": "EC", "kid": "ec", "use": "sig"}
+		]}`))
+	}))
+	defer server.Close()
+
+	keys, err := fetchKeys(context.Background(), server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("Failed to fetch keys: %v", err)
+	}
+
+	// Only the RSA signing key is kept
+	if len(keys) != 1 || keys["sig"] == nil {
+		t.Errorf("Expected only the signing key, got %v", keys)
+	}
+}
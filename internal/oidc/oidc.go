@@ -0,0 +1,371 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrInvalidState is returned when a callback doesn't belong to a pending login
+	ErrInvalidState = errors.New("invalid or expired login state")
+	// ErrInvalidIDToken is returned when the ID token can't be verified
+	ErrInvalidIDToken = errors.New("invalid ID token")
+	// ErrTooManyLogins is returned when too many logins are pending to start another
+	ErrTooManyLogins = errors.New("too many pending logins")
+)
+
+// loginExpiry is how long a user has to complete the login at the provider
+const loginExpiry = 10 * time.Minute
+
+// maxPendingLogins caps the logins kept in memory, since anyone can start one
+const maxPendingLogins = 10000
+
+// Config holds the configuration of an OpenID Connect provider
+type Config struct {
+	// Name identifies the provider in URLs, e.g. "company"
+	Name string
+	// DisplayName is shown on the login button
+	DisplayName string
+	// Issuer is the issuer URL, used for discovery
+	Issuer string
+	// ClientID and ClientSecret are the credentials of this application at
+	// the provider. Public clients have no secret and rely on PKCE alone.
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback URL registered at the provider
+	RedirectURL string
+	// Scopes are requested in addition to "openid"
+	Scopes []string
+}
+
+// Identity is the verified identity of a user at a provider
+type Identity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// metadata is the subset of the discovery document used by the login flow
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// pendingLogin is a login that was started but not completed yet
+type pendingLogin struct {
+	nonce     string
+	verifier  string
+	expiresAt time.Time
+}
+
+// idTokenClaims are the claims of an ID token
+type idTokenClaims struct {
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	jwt.RegisteredClaims
+}
+
+// Provider runs the authorization code flow with PKCE against one OpenID
+// Connect provider. The discovery document and signing keys are fetched on
+// first use, so that the server starts even if the provider is unreachable.
+type Provider struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+
+	metadata *metadata
+	keys     map[string]*rsa.PublicKey
+	pending  map[string]pendingLogin
+	mutex    sync.Mutex
+}
+
+// NewProvider creates a provider. If client is nil, http.DefaultClient is used.
+func NewProvider(config Config, client *http.Client) *Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if config.DisplayName == "" {
+		config.DisplayName = config.Name
+	}
+
+	return &Provider{
+		config:  config,
+		client:  client,
+		now:     time.Now,
+		pending: make(map[string]pendingLogin),
+	}
+}
+
+// Name returns the name identifying the provider
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// DisplayName returns the human readable name of the provider
+func (p *Provider) DisplayName() string {
+	return p.config.DisplayName
+}
+
+// Begin starts a login. It returns the URL to send the user to and the state
+// that the provider passes back to the callback. It returns ErrTooManyLogins
+// when maxPendingLogins logins are pending.
+func (p *Provider) Begin(ctx context.Context) (string, string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := GenerateVerifier()
+	if err != nil {
+		return "", "", err
+	}
+
+	p.mutex.Lock()
+	now := p.now()
+	// Expired logins are only swept when they take up room
+	if len(p.pending) >= maxPendingLogins {
+		for key, login := range p.pending {
+			if !now.Before(login.expiresAt) {
+				delete(p.pending, key)
+			}
+		}
+		if len(p.pending) >= maxPendingLogins {
+			p.mutex.Unlock()
+			return "", "", ErrTooManyLogins
+		}
+	}
+	p.pending[state] = pendingLogin{
+		nonce:     nonce,
+		verifier:  verifier,
+		expiresAt: now.Add(loginExpiry),
+	}
+	p.mutex.Unlock()
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", strings.Join(append([]string{"openid"}, p.config.Scopes...), " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", ChallengeS256(verifier))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return meta.AuthorizationEndpoint + separator + params.Encode(), state, nil
+}
+
+// Finish completes a login started with Begin by exchanging the
+// authorization code and verifying the returned ID token
+func (p *Provider) Finish(ctx context.Context, state, code string) (*Identity, error) {
+	// A state can only be used once
+	p.mutex.Lock()
+	login, exists := p.pending[state]
+	delete(p.pending, state)
+	p.mutex.Unlock()
+
+	if !exists || !p.now().Before(login.expiresAt) {
+		return nil, ErrInvalidState
+	}
+
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := p.exchange(ctx, meta, code, login.verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.verifyIDToken(ctx, meta, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Nonce != login.nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return &Identity{
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+// discover fetches and caches the discovery document
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mutex.Lock()
+	meta := p.metadata
+	p.mutex.Unlock()
+	if meta != nil {
+		return meta, nil
+	}
+
+	meta = &metadata{}
+	uri := strings.TrimRight(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(ctx, p.client, uri, meta); err != nil {
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+	if meta.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("discovery failed: issuer %q does not match %q", meta.Issuer, p.config.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("discovery failed: incomplete provider metadata")
+	}
+
+	p.mutex.Lock()
+	p.metadata = meta
+	p.mutex.Unlock()
+
+	return meta, nil
+}
+
+// exchange redeems the authorization code at the token endpoint and returns the raw ID token
+func (p *Provider) exchange(ctx context.Context, meta *metadata, code, verifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed: %s %s", body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", fmt.Errorf("%w: missing from token response", ErrInvalidIDToken)
+	}
+
+	return body.IDToken, nil
+}
+
+// verifyIDToken checks the signature, issuer, audience and expiry of an ID token
+func (p *Provider) verifyIDToken(ctx context.Context, meta *metadata, rawIDToken string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, meta, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(p.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	// Tokens for several audiences must have been issued to us
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, fmt.Errorf("%w: authorized party mismatch", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+// key returns the signing key with the given ID. The key set is downloaded
+// again when an unknown key is seen, so that key rotation is picked up.
+func (p *Provider) key(ctx context.Context, meta *metadata, kid string) (*rsa.PublicKey, error) {
+	p.mutex.Lock()
+	key, exists := p.keys[kid]
+	p.mutex.Unlock()
+	if exists {
+		return key, nil
+	}
+
+	keys, err := fetchKeys(ctx, p.client, meta.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	p.keys = keys
+	p.mutex.Unlock()
+
+	key, exists = keys[kid]
+	if !exists {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// getJSON fetches a JSON document
+func getJSON(ctx context.Context, client *http.Client, uri string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, uri)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,213 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/internal/oidc/oidctest"
+)
+
+// newTestProvider starts a stand-in provider and creates a Provider for it
+func newTestProvider(t *testing.T, clientSecret string) (*Provider, *oidctest.Server) {
+	t.Helper()
+
+	server := oidctest.NewServer("conduit", "client-secret")
+	t.Cleanup(server.Close)
+
+	provider := NewProvider(Config{
+		Name:         "test",
+		Issuer:       server.Issuer(),
+		ClientID:     "conduit",
+		ClientSecret: clientSecret,
+		RedirectURL:  "http://localhost:8080/api/auth/test/callback",
+		Scopes:       []string{"email", "profile"},
+	}, server.Client())
+
+	return provider, server
+}
+
+// login runs Begin, the provider's authorization step and returns the callback state and code
+func login(t *testing.T, provider *Provider, server *oidctest.Server) (string, string) {
+	t.Helper()
+
+	authURL, state, err := provider.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to begin login: %v", err)
+	}
+
+	callback, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Failed to authorize: %v", err)
+	}
+	if callback.Query().Get("state") != state {
+		t.Fatalf("Expected state %s, got %s", state, callback.Query().Get("state"))
+	}
+
+	return state, callback.Query().Get("code")
+}
+
+func TestBeginBuildsAuthorizationURL(t *testing.T) {
+	provider, server := newTestProvider(t, "client-secret")
+
+	authURL, state, err := provider.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to begin login: %v", err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	if !strings.HasPrefix(authURL, server.Issuer()+"/authorize?") {
+		t.Errorf("Expected authorization endpoint, got %s", authURL)
+	}
+
+	query := parsed.Query()
+	expected := map[string]string{
+		"response_type":         "code",
+		"client_id":             "conduit",
+		"redirect_uri":          "http://localhost:8080/api/auth/test/callback",
+		"scope":                 "openid email profile",
+		"state":                 state,
+		"code_challenge_method": "S256",
+	}
+	for key, value := range expected {
+		if query.Get(key) != value {
+			t.Errorf("Expected %s=%s, got %s", key, value, query.Get(key))
+		}
+	}
+	if query.Get("nonce") == "" || query.Get("code_challenge") == "" {
+		t.Error("Expected nonce and code challenge")
+	}
+}
+
+func TestLoginFlow(t *testing.T) {
+	provider, server := newTestProvider(t, "client-secret")
+	server.SetUser(oidctest.User{
+		Subject:           "abc123",
+		Email:             "jane@example.com",
+		EmailVerified:     true,
+		Name:              "Jane Doe",
+		PreferredUsername: "jane",
+	})
+
+	state, code := login(t, provider, server)
+
+	identity, err := provider.Finish(context.Background(), state, code)
+	if err != nil {
+		t.Fatalf("Failed to finish login: %v", err)
+	}
+
+	if identity.Subject != "abc123" {
+		t.Errorf("Expected subject abc123, got %s", identity.Subject)
+	}
+	if identity.Email != "jane@example.com" || !identity.EmailVerified {
+		t.Errorf("Expected verified email jane@example.com, got %s (%v)", identity.Email, identity.EmailVerified)
+	}
+	if identity.PreferredUsername != "jane" || identity.Name != "Jane Doe" {
+		t.Errorf("Unexpected profile claims: %+v", identity)
+	}
+}
+
+func TestFinishRejectsUnknownOrReusedState(t *testing.T) {
+	provider, server := newTestProvider(t, "client-secret")
+
+	// Unknown state
+	if _, err := provider.Finish(context.Background(), "unknown", "code"); err != ErrInvalidState {
+		t.Errorf("Expected ErrInvalidState, got %v", err)
+	}
+
+	// A state can only be used once
+	state, code := login(t, provider, server)
+	if _, err := provider.Finish(context.Background(), state, code); err != nil {
+		t.Fatalf("Failed to finish login: %v", err)
+	}
+	if _, err := provider.Finish(context.Background(), state, code); err != ErrInvalidState {
+		t.Errorf("Expected ErrInvalidState for reused state, got %v", err)
+	}
+}
+
+func TestFinishRejectsExpiredState(t *testing.T) {
+	provider, server := newTestProvider(t, "client-secret")
+
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+	state, code := login(t, provider, server)
+
+	// The user took too long at the provider
+	now = now.Add(loginExpiry)
+	if _, err := provider.Finish(context.Background(), state, code); err != ErrInvalidState {
+		t.Errorf("Expected ErrInvalidState, got %v", err)
+	}
+}
+
+func TestBeginCapsPendingLogins(t *testing.T) {
+	provider, _ := newTestProvider(t, "client-secret")
+
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+	for i := 0; i < maxPendingLogins; i++ {
+		provider.pending[strconv.Itoa(i)] = pendingLogin{expiresAt: now.Add(loginExpiry)}
+	}
+
+	if _, _, err := provider.Begin(context.Background()); !errors.Is(err, ErrTooManyLogins) {
+		t.Errorf("Expected ErrTooManyLogins, got %v", err)
+	}
+
+	// Expired logins make room for new ones
+	now = now.Add(loginExpiry)
+	if _, _, err := provider.Begin(context.Background()); err != nil {
+		t.Fatalf("Failed to begin login: %v", err)
+	}
+	if len(provider.pending) != 1 {
+		t.Errorf("Expected expired logins to be swept, got %d pending", len(provider.pending))
+	}
+}
+
+func TestFinishWithWrongClientSecret(t *testing.T) {
+	provider, server := newTestProvider(t, "wrong-secret")
+
+	state, code := login(t, provider, server)
+	if _, err := provider.Finish(context.Background(), state, code); err == nil {
+		t.Error("Expected the token request to fail, got nil")
+	}
+}
+
+func TestFinishRejectsExpiredIDToken(t *testing.T) {
+	provider, server := newTestProvider(t, "client-secret")
+
+	state, code := login(t, provider, server)
+
+	// The ID token is valid for a few minutes only
+	provider.now = func() time.Time { return time.Now().Add(time.Hour) }
+	provider.mutex.Lock()
+	login := provider.pending[state]
+	login.expiresAt = time.Now().Add(2 * time.Hour)
+	provider.pending[state] = login
+	provider.mutex.Unlock()
+
+	if _, err := provider.Finish(context.Background(), state, code); !errors.Is(err, ErrInvalidIDToken) {
+		t.Errorf("Expected ErrInvalidIDToken, got %v", err)
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	server := oidctest.NewServer("conduit", "")
+	defer server.Close()
+
+	// The issuer in the discovery document has to match exactly
+	provider := NewProvider(Config{
+		Name:     "test",
+		Issuer:   server.Issuer() + "/",
+		ClientID: "conduit",
+	}, server.Client())
+
+	if _, _, err := provider.Begin(context.Background()); err == nil {
+		t.Error("Expected discovery to fail, got nil")
+	}
+}
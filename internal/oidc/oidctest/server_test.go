@@ -0,0 +1,44 @@
+package oidctest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestDiscoveryDocument(t *testing.T) {
+	server := NewServer("client", "secret")
+	defer server.Close()
+
+	resp, err := http.Get(server.Issuer() + "/.well-known/openid-configuration")
+	if err != nil {
+		t.Fatalf("Failed to fetch discovery document: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var doc map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatalf("Failed to parse discovery document: %v", err)
+	}
+	if doc["issuer"] != server.Issuer() {
+		t.Errorf("Expected issuer %s, got %v", server.Issuer(), doc["issuer"])
+	}
+	if doc["token_endpoint"] != server.Issuer()+"/token" {
+		t.Errorf("Unexpected token endpoint: %v", doc["token_endpoint"])
+	}
+}
+
+func TestAuthorizeRequiresPKCE(t *testing.T) {
+	server := NewServer("client", "secret")
+	defer server.Close()
+
+	resp, err := http.Get(server.Issuer() + "/authorize?response_type=code&client_id=client&redirect_uri=http://localhost/cb")
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
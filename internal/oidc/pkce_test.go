@@ -0,0 +1,30 @@
+package oidc
+
+import "testing"
+
+func TestChallengeS256(t *testing.T) {
+	// Example from RFC 7636, appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	expected := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	if challenge := ChallengeS256(verifier); challenge != expected {
+		t.Errorf("Expected challenge %s, got %s", expected, challenge)
+	}
+}
+
+func TestGenerateVerifier(t *testing.T) {
+	verifier, err := GenerateVerifier()
+	if err != nil {
+		t.Fatalf("Failed to generate verifier: %v", err)
+	}
+
+	// RFC 7636 requires 43 to 128 characters
+	if len(verifier) < 43 || len(verifier) > 128 {
+		t.Errorf("Expected verifier of 43 to 128 characters, got %d", len(verifier))
+	}
+
+	other, _ := GenerateVerifier()
+	if verifier == other {
+		t.Error("Expected verifiers to be random")
+	}
+}
@@ -0,0 +1,81 @@
+// Package policy decides which users may perform which actions, based on
+// their role and on whether they own the resource.
+package policy
+
+// Role is the role of a user
+type Role string
+
+// Roles of users, from least to most privileged
+const (
+	// RoleUser is the role of regular users
+	RoleUser Role = "user"
+	// RoleModerator can additionally moderate comments
+	RoleModerator Role = "moderator"
+	// RoleAdmin can additionally remove any content and manage users
+	RoleAdmin Role = "admin"
+)
+
+// Roles lists all roles, from least to most privileged
+var Roles = []Role{RoleUser, RoleModerator, RoleAdmin}
+
+// ParseRole converts a string to a role. The empty string is the user role.
+func ParseRole(s string) (Role, bool) {
+	if s == "" {
+		return RoleUser, true
+	}
+	for _, role := range Roles {
+		if string(role) == s {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+// Action is something a user may or may not be allowed to do
+type Action string
+
+// Actions checked by the handlers
+const (
+	// UpdateArticle is editing an article
+	UpdateArticle Action = "article:update"
+	// DeleteArticle is deleting an article
+	DeleteArticle Action = "article:delete"
+	// DeleteComment is deleting a comment
+	DeleteComment Action = "comment:delete"
+	// SuspendUser is suspending and reinstating accounts
+	SuspendUser Action = "user:suspend"
+	// ManageUsers is listing, deleting and forcing password resets of accounts
+	ManageUsers Action = "user:manage"
+	// ManageRoles is changing the role of users
+	ManageRoles Action = "user:manage-roles"
+	// ViewLoginAudit is reading failed login attempts and lockouts
+	ViewLoginAudit Action = "login:audit"
+	// UnlockAccount is lifting the lockout of an account
+	UnlockAccount Action = "login:unlock"
+)
+
+// Actor is the user performing an action
+type Actor struct {
+	Username string
+	Role     Role
+}
+
+// Can reports whether actor may perform action on a resource owned by the
+// user with the given username. owner is empty for actions that don't
+// concern a resource of a user.
+func Can(actor Actor, action Action, owner string) bool {
+	isOwner := owner != "" && actor.Username == owner
+
+	switch action {
+	case UpdateArticle:
+		return isOwner
+	case DeleteArticle:
+		return isOwner || actor.Role == RoleAdmin
+	case DeleteComment:
+		return isOwner || actor.Role == RoleModerator || actor.Role == RoleAdmin
+	case SuspendUser, ManageUsers, ManageRoles, ViewLoginAudit, UnlockAccount:
+		return actor.Role == RoleAdmin
+	}
+
+	return false
+}
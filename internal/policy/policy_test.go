@@ -0,0 +1,111 @@
+package policy
+
+import "testing"
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Role
+		ok       bool
+	}{
+		{"", RoleUser, true},
+		{"user", RoleUser, true},
+		{"moderator", RoleModerator, true},
+		{"admin", RoleAdmin, true},
+		{"Admin", "", false},
+		{"root", "", false},
+	}
+
+	for _, tt := range tests {
+		role, ok := ParseRole(tt.input)
+		if role != tt.expected || ok != tt.ok {
+			t.Errorf("ParseRole(%q): expected %q, %v, got %q, %v", tt.input, tt.expected, tt.ok, role, ok)
+		}
+	}
+}
+
+func TestCan(t *testing.T) {
+	// Every action is checked for every role, once on the actor's own
+	// resource and once on someone else's
+	type decision struct {
+		own, other bool
+	}
+	matrix := map[Action]map[Role]decision{
+		UpdateArticle: {
+			RoleUser:      {own: true, other: false},
+			RoleModerator: {own: true, other: false},
+			RoleAdmin:     {own: true, other: false},
+		},
+		DeleteArticle: {
+			RoleUser:      {own: true, other: false},
+			RoleModerator: {own: true, other: false},
+			RoleAdmin:     {own: true, other: true},
+		},
+		DeleteComment: {
+			RoleUser:      {own: true, other: false},
+			RoleModerator: {own: true, other: true},
+			RoleAdmin:     {own: true, other: true},
+		},
+		SuspendUser: {
+			RoleUser:      {own: false, other: false},
+			RoleModerator: {own: false, other: false},
+			RoleAdmin:     {own: true, other: true},
+		},
+		ManageUsers: {
+			RoleUser:      {own: false, other: false},
+			RoleModerator: {own: false, other: false},
+			RoleAdmin:     {own: true, other: true},
+		},
+		ManageRoles: {
+			RoleUser:      {own: false, other: false},
+			RoleModerator: {own: false, other: false},
+			RoleAdmin:     {own: true, other: true},
+		},
+		ViewLoginAudit: {
+			RoleUser:      {own: false, other: false},
+			RoleModerator: {own: false, other: false},
+			RoleAdmin:     {own: true, other: true},
+		},
+		UnlockAccount: {
+			RoleUser:      {own: false, other: false},
+			RoleModerator: {own: false, other: false},
+			RoleAdmin:     {own: true, other: true},
+		},
+	}
+
+	for action, roles := range matrix {
+		for _, role := range Roles {
+			expected, ok := roles[role]
+			if !ok {
+				t.Fatalf("Missing matrix entry for %s and %s", action, role)
+			}
+
+			actor := Actor{Username: "jane", Role: role}
+			t.Run(string(action)+" as "+string(role), func(t *testing.T) {
+				if got := Can(actor, action, "jane"); got != expected.own {
+					t.Errorf("Expected %v on own resource, got %v", expected.own, got)
+				}
+				if got := Can(actor, action, "john"); got != expected.other {
+					t.Errorf("Expected %v on other resource, got %v", expected.other, got)
+				}
+			})
+		}
+	}
+}
+
+func TestCanWithoutOwner(t *testing.T) {
+	// An empty owner never makes the actor the owner
+	actor := Actor{Username: "", Role: RoleUser}
+	if Can(actor, DeleteArticle, "") {
+		t.Error("Expected empty owner not to match empty username")
+	}
+	if !Can(Actor{Role: RoleAdmin}, ManageRoles, "") {
+		t.Error("Expected admin to manage roles")
+	}
+}
+
+func TestCanUnknownAction(t *testing.T) {
+	if Can(Actor{Username: "jane", Role: RoleAdmin}, Action("unknown"), "jane") {
+		t.Error("Expected unknown actions to be denied")
+	}
+}
@@ -0,0 +1,166 @@
+// Package ratelimit limits how often clients may call the API, using token
+// buckets that are kept in a pluggable store.
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests requests per Period. Unused requests build up to a
+// burst of Requests, and the bucket refills evenly over the period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// String formats the limit like "10/1m0s"
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	// Allowed reports whether the request may proceed
+	Allowed bool
+	// Limit is the size of the bucket
+	Limit int
+	// Remaining is the number of requests left in the bucket
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, zero if allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets. The in-process MemoryStore works for a single
+// server; deployments with several servers can share buckets by implementing
+// Store on top of a shared backend.
+type Store interface {
+	// Take takes a token from the bucket with the given key
+	Take(key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Principal selects whose requests share a bucket
+type Principal string
+
+const (
+	// ByUser counts requests per authenticated user, and per client IP for
+	// anonymous requests
+	ByUser Principal = "user"
+	// ByIP counts requests per client IP
+	ByIP Principal = "ip"
+)
+
+// Rule applies a limit to the requests matching a method and path pattern.
+// Patterns consist of path segments where "*" matches any single segment and
+// a final "**" matches any remainder, like "/api/articles/*/comments".
+type Rule struct {
+	// Method is the HTTP method to match, empty for all methods
+	Method string
+	// Pattern is the path pattern to match
+	Pattern string
+	// By selects whose requests share a bucket
+	By Principal
+	// Limit is the number of requests allowed
+	Limit Limit
+}
+
+// String formats the rule like it is configured, e.g. "POST /api/articles 30/1h0m0s user"
+func (r Rule) String() string {
+	method := r.Method
+	if method == "" {
+		method = "*"
+	}
+	return fmt.Sprintf("%s %s %s %s", method, r.Pattern, r.Limit, r.By)
+}
+
+// Matches reports whether the rule applies to a request
+func (r Rule) Matches(method, path string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+
+	patternSegments := strings.Split(strings.Trim(r.Pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range patternSegments {
+		if segment == "**" && i == len(patternSegments)-1 {
+			return true
+		}
+		if i >= len(pathSegments) || (segment != "*" && segment != pathSegments[i]) {
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
+
+// DefaultRules returns the rules used unless configured otherwise. They are
+// generous for people but stop scripts hammering the expensive and
+// security-sensitive endpoints.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Method: "POST", Pattern: "/api/users/login", By: ByIP, Limit: Limit{Requests: 10, Period: time.Minute}},
+		{Method: "POST", Pattern: "/api/users/login/two-factor", By: ByIP, Limit: Limit{Requests: 10, Period: time.Minute}},
+		{Method: "GET", Pattern: "/api/auth/*/login", By: ByIP, Limit: Limit{Requests: 10, Period: time.Minute}},
+		{Method: "POST", Pattern: "/api/users", By: ByIP, Limit: Limit{Requests: 10, Period: time.Hour}},
+		{Method: "POST", Pattern: "/api/users/password-reset", By: ByIP, Limit: Limit{Requests: 5, Period: time.Hour}},
+		{Method: "POST", Pattern: "/api/user/verify-email", By: ByUser, Limit: Limit{Requests: 5, Period: time.Hour}},
+		{Method: "POST", Pattern: "/api/articles", By: ByUser, Limit: Limit{Requests: 30, Period: time.Hour}},
+		{Method: "POST", Pattern: "/api/articles/*/comments", By: ByUser, Limit: Limit{Requests: 60, Period: time.Hour}},
+		{Pattern: "/api/**", By: ByUser, Limit: Limit{Requests: 300, Period: time.Minute}},
+	}
+}
+
+// ParseRules parses rules separated by semicolons. Each rule has the form
+// "METHOD PATTERN REQUESTS/PERIOD PRINCIPAL", e.g. "POST /api/articles 30/1h user".
+// The method "*" matches all methods.
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rule, err := parseRule(entry)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseRule parses a single rule
+func parseRule(s string) (Rule, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 4 {
+		return Rule{}, fmt.Errorf("invalid rate limit rule %q: want METHOD PATTERN REQUESTS/PERIOD PRINCIPAL", s)
+	}
+
+	rule := Rule{Method: strings.ToUpper(fields[0]), Pattern: fields[1], By: Principal(fields[3])}
+	if rule.Method == "*" {
+		rule.Method = ""
+	}
+	if !strings.HasPrefix(rule.Pattern, "/") {
+		return Rule{}, fmt.Errorf("invalid rate limit rule %q: pattern must start with /", s)
+	}
+	if rule.By != ByUser && rule.By != ByIP {
+		return Rule{}, fmt.Errorf("invalid rate limit rule %q: principal must be %q or %q", s, ByUser, ByIP)
+	}
+
+	requests, period, found := strings.Cut(fields[2], "/")
+	if !found {
+		return Rule{}, fmt.Errorf("invalid rate limit rule %q: limit must look like 10/1m", s)
+	}
+	var err error
+	if rule.Limit.Requests, err = strconv.Atoi(requests); err != nil || rule.Limit.Requests <= 0 {
+		return Rule{}, fmt.Errorf("invalid rate limit rule %q: requests must be a positive number", s)
+	}
+	if rule.Limit.Period, err = time.ParseDuration(period); err != nil || rule.Limit.Period <= 0 {
+		return Rule{}, fmt.Errorf("invalid rate limit rule %q: period must be a positive duration", s)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		rule     Rule
+		method   string
+		path     string
+		expected bool
+	}{
+		{Rule{Method: "POST", Pattern: "/api/articles"}, "POST", "/api/articles", true},
+		{Rule{Method: "POST", Pattern: "/api/articles"}, "GET", "/api/articles", false},
+		{Rule{Method: "POST", Pattern: "/api/articles"}, "POST", "/api/articles/my-article", false},
+		{Rule{Method: "POST", Pattern: "/api/articles"}, "POST", "/api/articles/", true},
+		{Rule{Method: "POST", Pattern: "/api/articles/*/comments"}, "POST", "/api/articles/my-article/comments", true},
+		{Rule{Method: "POST", Pattern: "/api/articles/*/comments"}, "POST", "/api/articles/comments", false},
+		{Rule{Method: "GET", Pattern: "/api/auth/*/login"}, "GET", "/api/auth/company/login", true},
+		{Rule{Method: "GET", Pattern: "/api/auth/*/login"}, "GET", "/api/auth/company/callback", false},
+		{Rule{Pattern: "/api/**"}, "GET", "/api/tags", true},
+		{Rule{Pattern: "/api/**"}, "DELETE", "/api/articles/my-article/comments/1", true},
+		{Rule{Pattern: "/api/**"}, "GET", "/api", true},
+		{Rule{Pattern: "/api/**"}, "GET", "/openapi.yml", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rule.Matches(tt.method, tt.path); got != tt.expected {
+			t.Errorf("%s matching %s %s: expected %v, got %v", tt.rule, tt.method, tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("POST /api/articles 30/1h user; * /api/** 100/1m ip;")
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+
+	expected := []Rule{
+		{Method: "POST", Pattern: "/api/articles", By: ByUser, Limit: Limit{Requests: 30, Period: time.Hour}},
+		{Pattern: "/api/**", By: ByIP, Limit: Limit{Requests: 100, Period: time.Minute}},
+	}
+	if len(rules) != len(expected) {
+		t.Fatalf("Expected %d rules, got %d", len(expected), len(rules))
+	}
+	for i := range expected {
+		if rules[i] != expected[i] {
+			t.Errorf("Expected rule %v, got %v", expected[i], rules[i])
+		}
+	}
+}
+
+func TestParseRulesInvalid(t *testing.T) {
+	for _, s := range []string{
+		"POST /api/articles 30/1h",
+		"POST api/articles 30/1h user",
+		"POST /api/articles 30 user",
+		"POST /api/articles 0/1h user",
+		"POST /api/articles 30/hour user",
+		"POST /api/articles 30/1h session",
+	} {
+		if _, err := ParseRules(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+	now := time.Now()
+
+	// The bucket starts full
+	for i := 2; i >= 0; i-- {
+		result, _ := store.Take("key", limit, now)
+		if !result.Allowed || result.Remaining != i || result.Limit != 3 {
+			t.Fatalf("Expected allowed with %d remaining, got %+v", i, result)
+		}
+	}
+
+	// Then requests are rejected until a token refilled
+	result, _ := store.Take("key", limit, now)
+	if result.Allowed {
+		t.Fatal("Expected request to be rejected")
+	}
+	if result.RetryAfter != time.Second {
+		t.Errorf("Expected retry after 1s, got %s", result.RetryAfter)
+	}
+	if result.Reset != 3*time.Second {
+		t.Errorf("Expected reset after 3s, got %s", result.Reset)
+	}
+
+	result, _ = store.Take("key", limit, now.Add(time.Second))
+	if !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Expected allowed after refill, got %+v", result)
+	}
+
+	// Other keys have their own bucket
+	result, _ = store.Take("other", limit, now)
+	if !result.Allowed || result.Remaining != 2 {
+		t.Errorf("Expected separate bucket, got %+v", result)
+	}
+
+	// Buckets never grow beyond their limit
+	result, _ = store.Take("key", limit, now.Add(time.Hour))
+	if !result.Allowed || result.Remaining != 2 {
+		t.Errorf("Expected full bucket, got %+v", result)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Period: time.Second}
+	now := time.Now()
+
+	store.Take("a", limit, now)
+	store.Take("b", limit, now.Add(2*sweepInterval))
+
+	// Refilled buckets are dropped on the next sweep
+	if _, exists := store.buckets["a"]; exists {
+		t.Error("Expected refilled bucket to be dropped")
+	}
+	if _, exists := store.buckets["b"]; !exists {
+		t.Error("Expected recently used bucket to be kept")
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/frontend"
+)
+
+// siteName is the name of the frontend, shown in titles and link previews
+const siteName = "Real World Example"
+
+// maxDescriptionLength is the length descriptions are cut to, since link
+// previews only show a line or two
+const maxDescriptionLength = 200
+
+// pageMeta returns the meta tags of article and profile pages. Content of
+// suspended users gets no previews.
+func pageMeta(store *db.InMemoryDB, appURL string) frontend.MetaFunc {
+	baseURL := strings.TrimRight(appURL, "/")
+
+	return func(urlPath string) (frontend.Meta, bool) {
+		segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+		if len(segments) != 2 || segments[1] == "" {
+			return frontend.Meta{}, false
+		}
+
+		switch segments[0] {
+		case "article":
+			return articleMeta(store, baseURL, segments[1])
+		case "profile":
+			return profileMeta(store, baseURL, segments[1])
+		}
+		return frontend.Meta{}, false
+	}
+}
+
+// pageRedirect redirects the pages of renamed public articles from their
+// previous slugs to the current one. The slugs of hidden articles would give
+// their titles away.
+func pageRedirect(store *db.InMemoryDB) frontend.RedirectFunc {
+	return func(urlPath string) (string, bool) {
+		slug, ok := strings.CutPrefix(urlPath, "/article/")
+		if !ok || slug == "" || strings.Contains(slug, "/") {
+			return "", false
+		}
+
+		current, ok := store.ResolveSlug(slug)
+		if !ok {
+			return "", false
+		}
+		article, err := store.GetArticle(current)
+		if err != nil || store.IsSuspended(article.Author.Username) || !db.IsPublic(*article) {
+			return "", false
+		}
+		return "/article/" + url.PathEscape(current), true
+	}
+}
+
+// articleMeta returns the meta tags of an article page
+func articleMeta(store *db.InMemoryDB, baseURL, slug string) (frontend.Meta, bool) {
+	article, err := store.GetArticle(slug)
+	if err != nil || store.IsSuspended(article.Author.Username) || !db.IsPublic(*article) {
+		return frontend.Meta{}, false
+	}
+
+	// Prefer the current image of the author
+	image := article.Author.Image
+	if author, err := store.GetUserByUsername(article.Author.Username); err == nil {
+		image = author.Image
+	}
+
+	return frontend.Meta{
+		Title:       article.Title,
+		Description: truncate(article.Description, maxDescriptionLength),
+		Type:        "article",
+		URL:         baseURL + "/article/" + url.PathEscape(article.Slug),
+		Image:       absoluteURL(baseURL, image),
+		SiteName:    siteName,
+	}, true
+}
+
+// profileMeta returns the meta tags of a profile page
+func profileMeta(store *db.InMemoryDB, baseURL, username string) (frontend.Meta, bool) {
+	user, err := store.GetUserByUsername(username)
+	if err != nil || store.IsSuspended(username) {
+		return frontend.Meta{}, false
+	}
+
+	description := user.Bio
+	if description == "" {
+		description = "Articles by " + user.Username
+	}
+
+	return frontend.Meta{
+		Title:       user.Username,
+		Description: truncate(description, maxDescriptionLength),
+		Type:        "profile",
+		URL:         baseURL + "/profile/" + url.PathEscape(user.Username),
+		Image:       absoluteURL(baseURL, user.Image),
+		SiteName:    siteName,
+	}, true
+}
+
+// absoluteURL resolves an image URL against the base URL. Previews can only
+// show HTTP(S) images, so other URLs are dropped.
+func absoluteURL(baseURL, image string) string {
+	switch {
+	case strings.HasPrefix(image, "https://"), strings.HasPrefix(image, "http://"):
+		return image
+	case strings.HasPrefix(image, "/") && !strings.HasPrefix(image, "//"):
+		return baseURL + image
+	}
+	return ""
+}
+
+// truncate cuts s to at most n characters, marking cuts with an ellipsis
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
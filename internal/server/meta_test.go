@@ -0,0 +1,196 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/frontend"
+)
+
+// setupMetaStore creates alice with an article and bob without a bio
+func setupMetaStore(t *testing.T) *db.InMemoryDB {
+	t.Helper()
+
+	store := db.NewInMemoryDB()
+	if err := store.CreateUser(api.User{Username: "alice", Email: "alice@example.com", Bio: "Writes about Go", Image: "/images/alice.png"}, "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := store.CreateUser(api.User{Username: "bob", Email: "bob@example.com"}, "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	err := store.CreateArticle(api.Article{
+		Slug:        "hello-world",
+		Title:       "Hello World",
+		Description: "A first article",
+		Author:      api.Profile{Username: "alice"},
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	return store
+}
+
+func TestPageMeta(t *testing.T) {
+	meta := pageMeta(setupMetaStore(t), "https://conduit.example.com/")
+
+	tests := []struct {
+		path     string
+		expected *frontend.Meta
+	}{
+		{"/article/hello-world", &frontend.Meta{
+			Title:       "Hello World",
+			Description: "A first article",
+			Type:        "article",
+			URL:         "https://conduit.example.com/article/hello-world",
+			Image:       "https://conduit.example.com/images/alice.png",
+			SiteName:    siteName,
+		}},
+		{"/profile/alice", &frontend.Meta{
+			Title:       "alice",
+			Description: "Writes about Go",
+			Type:        "profile",
+			URL:         "https://conduit.example.com/profile/alice",
+			Image:       "https://conduit.example.com/images/alice.png",
+			SiteName:    siteName,
+		}},
+		{"/profile/bob", &frontend.Meta{
+			Title:       "bob",
+			Description: "Articles by bob",
+			Type:        "profile",
+			URL:         "https://conduit.example.com/profile/bob",
+			SiteName:    siteName,
+		}},
+		{"/article/unknown", nil},
+		{"/profile/unknown", nil},
+		{"/profile/alice/favorites", nil},
+		{"/article", nil},
+		{"/login", nil},
+	}
+
+	for _, tt := range tests {
+		got, ok := meta(tt.path)
+		if tt.expected == nil {
+			if ok {
+				t.Errorf("%s: expected no meta, got %+v", tt.path, got)
+			}
+			continue
+		}
+		if !ok || got != *tt.expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.path, *tt.expected, got)
+		}
+	}
+}
+
+func TestPageMetaSuspendedUser(t *testing.T) {
+	store := setupMetaStore(t)
+	if err := store.SuspendUser("alice@example.com", "spam", time.Now()); err != nil {
+		t.Fatalf("Failed to suspend user: %v", err)
+	}
+	meta := pageMeta(store, "https://conduit.example.com")
+
+	for _, path := range []string{"/article/hello-world", "/profile/alice"} {
+		if got, ok := meta(path); ok {
+			t.Errorf("%s: expected no meta for suspended user, got %+v", path, got)
+		}
+	}
+}
+
+// scheduledArticle returns an article of alice with the given status and publish time
+func scheduledArticle(slug string, publishAt time.Time, status string) api.Article {
+	return api.Article{
+		Slug:      slug,
+		Title:     slug,
+		Author:    api.Profile{Username: "alice"},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Status:    status,
+		PublishAt: &publishAt,
+	}
+}
+
+func TestPageMetaDraft(t *testing.T) {
+	store := setupMetaStore(t)
+	if err := store.CreateArticle(scheduledArticle("later", time.Now().Add(time.Hour), db.StatusScheduled)); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	meta := pageMeta(store, "https://conduit.example.com")
+
+	// Articles that aren't public yet have no meta tags
+	if got, ok := meta("/article/later"); ok {
+		t.Errorf("Expected no meta for scheduled article, got %+v", got)
+	}
+}
+
+func TestPageRedirect(t *testing.T) {
+	store := setupMetaStore(t)
+	title := "Hello Again"
+	if _, err := store.UpdateArticle("hello-world", api.UpdateArticle{Title: &title}, "alice"); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	// Drafts don't give their new slugs away
+	if err := store.CreateArticle(scheduledArticle("working-title", time.Now(), db.StatusDraft)); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	title = "Secret Launch"
+	if _, err := store.UpdateArticle("working-title", api.UpdateArticle{Title: &title}, "alice"); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	redirect := pageRedirect(store)
+
+	tests := []struct {
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"/article/hello-world", "/article/hello-again", true},
+		{"/article/hello-again", "", false},
+		{"/article/unknown", "", false},
+		{"/article/hello-world/edit", "", false},
+		{"/profile/hello-world", "", false},
+		{"/article/working-title", "", false},
+	}
+
+	for _, tt := range tests {
+		target, ok := redirect(tt.path)
+		if ok != tt.ok || target != tt.expected {
+			t.Errorf("%s: expected %q, %v, got %q, %v", tt.path, tt.expected, tt.ok, target, ok)
+		}
+	}
+}
+
+func TestAbsoluteURL(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"https://images.example.com/a.png", "https://images.example.com/a.png"},
+		{"http://images.example.com/a.png", "http://images.example.com/a.png"},
+		{"/images/a.png", "https://conduit.example.com/images/a.png"},
+		{"//images.example.com/a.png", ""},
+		{"data:image/png;base64,AAAA", ""},
+		{"javascript:alert(1)", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := absoluteURL("https://conduit.example.com", tt.image); got != tt.expected {
+			t.Errorf("absoluteURL(%q): expected %q, got %q", tt.image, tt.expected, got)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("  short\n text ", 20); got != "short text" {
+		t.Errorf("Expected whitespace to be collapsed, got %q", got)
+	}
+
+	got := truncate(strings.Repeat("ä", 300), maxDescriptionLength)
+	if len([]rune(got)) != maxDescriptionLength || !strings.HasSuffix(got, "…") {
+		t.Errorf("Expected %d characters ending in an ellipsis, got %d: %q", maxDescriptionLength, len([]rune(got)), got)
+	}
+}
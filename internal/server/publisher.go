@@ -0,0 +1,41 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/denga/go-real-world-example/internal/db"
+)
+
+// PublishInterval is how often scheduled articles are checked for publication
+const PublishInterval = 15 * time.Second
+
+// StartPublisher publishes scheduled articles once their publish time has
+// come, checking right away and then every interval. The returned function
+// stops the publisher.
+func StartPublisher(store *db.InMemoryDB, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		publishDue(store, time.Now())
+		for {
+			select {
+			case now := <-ticker.C:
+				publishDue(store, now)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// publishDue publishes the scheduled articles that are due and logs them
+func publishDue(store *db.InMemoryDB, now time.Time) {
+	for _, slug := range store.PublishDue(now) {
+		log.Printf("Published scheduled article %s", slug)
+	}
+}
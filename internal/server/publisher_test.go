@@ -0,0 +1,34 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/internal/db"
+)
+
+func TestStartPublisher(t *testing.T) {
+	store := setupMetaStore(t)
+	if err := store.CreateArticle(scheduledArticle("due", time.Now().Add(-time.Minute), db.StatusScheduled)); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	stop := StartPublisher(store, time.Hour)
+	defer stop()
+
+	// Due articles are published on the first check
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		article, err := store.GetArticle("due")
+		if err != nil {
+			t.Fatalf("Failed to get article: %v", err)
+		}
+		if article.Status == db.StatusPublished {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the article to be published, got %s", article.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,130 @@
+// Package server assembles the HTTP routes of the application.
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/denga/go-real-world-example/api"
+	"github.com/denga/go-real-world-example/internal/config"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/frontend"
+	"github.com/denga/go-real-world-example/internal/handlers"
+	"github.com/denga/go-real-world-example/internal/middleware"
+	"github.com/denga/go-real-world-example/internal/policy"
+	"github.com/denga/go-real-world-example/internal/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// NewRouter returns the router serving the API under /api, the OpenAPI spec
+// and the frontend files in frontendRoot
+func NewRouter(cfg config.Config, store *db.InMemoryDB, handler *handlers.Handler, spec []byte, frontendRoot fs.FS) (http.Handler, error) {
+	// Allow the inline scripts of the frontend in the Content-Security-Policy
+	scriptHashes, err := frontend.InlineScriptHashes(frontendRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new router
+	r := chi.NewRouter()
+
+	// Add middleware
+	r.Use(middleware.ClientIP(cfg.TrustedProxies))
+	r.Use(chimiddleware.Logger)
+	r.Use(chimiddleware.Recoverer)
+	r.Use(middleware.SecurityHeaders(cfg.Security, scriptHashes))
+	r.Use(middleware.CORS(cfg.CORS))
+
+	// Serve the OpenAPI spec
+	r.Get("/openapi.yml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(spec)
+	})
+
+	// Create a separate router for API routes
+	apiRouter := chi.NewRouter()
+
+	// Add auth middleware only to API routes
+	apiRouter.Use(middleware.Auth(cfg.Auth, store))
+	if len(cfg.RateLimits) > 0 {
+		apiRouter.Use(middleware.RateLimit(cfg.RateLimits, ratelimit.NewMemoryStore()))
+	}
+	apiRouter.Use(middleware.RequireRole(store, "/api/admin/", policy.RoleAdmin))
+
+	// Register API handlers and mount them to the main router
+	r.Mount("/api", api.HandlerFromMux(handler, apiRouter))
+
+	// Serve the frontend files
+	r.Handle("/*", frontend.Handler(frontendRoot, frontend.Options{
+		Meta:     pageMeta(store, cfg.AppURL),
+		Redirect: pageRedirect(store),
+	}))
+
+	return r, nil
+}
+
+// readHeaderTimeout bounds how long clients may take to send request headers
+const readHeaderTimeout = 10 * time.Second
+
+// ListenAndServe serves handler on the configured port, over HTTPS with
+// HTTP/2 when a certificate is configured. With a redirect port it also
+// listens for plain HTTP and redirects to HTTPS.
+func ListenAndServe(cfg config.Config, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	if !cfg.TLS.Enabled() {
+		fmt.Printf("Server listening on port %s\n", cfg.Port)
+		return srv.ListenAndServe()
+	}
+
+	tlsConfig, err := NewTLSConfig(cfg.TLS)
+	if err != nil {
+		return err
+	}
+	srv.TLSConfig = tlsConfig
+
+	errs := make(chan error, 2)
+	if cfg.TLS.RedirectPort != "" {
+		redirect := &http.Server{
+			Addr:              ":" + cfg.TLS.RedirectPort,
+			Handler:           RedirectHandler(cfg.Port),
+			ReadHeaderTimeout: readHeaderTimeout,
+		}
+		go func() {
+			log.Printf("Redirecting HTTP on port %s to HTTPS", cfg.TLS.RedirectPort)
+			errs <- redirect.ListenAndServe()
+		}()
+	}
+	go func() {
+		fmt.Printf("Server listening on port %s (HTTPS)\n", cfg.Port)
+		errs <- srv.ListenAndServeTLS("", "")
+	}()
+
+	// Stop when either listener fails
+	return <-errs
+}
+
+// NewTLSConfig returns the TLS configuration of the server, which serves
+// the certificate files and reloads them when they change
+func NewTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	reloader, err := NewCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:     cfg.MinVersion,
+		GetCertificate: reloader.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}, nil
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/denga/go-real-world-example/internal/config"
+	"github.com/denga/go-real-world-example/internal/db"
+	"github.com/denga/go-real-world-example/internal/handlers"
+)
+
+// inlineScript is the inline script of the test frontend
+const inlineScript = `self.__next_f=self.__next_f||[]`
+
+// setupTestRouter returns a router with the given configuration and a small frontend
+func setupTestRouter(t *testing.T, cfg config.Config) http.Handler {
+	t.Helper()
+
+	frontendRoot := fstest.MapFS{
+		"index.html":   {Data: []byte(`<html><script src="/_next/app.js"></script><script>` + inlineScript + `</script></html>`)},
+		"_next/app.js": {Data: []byte(`console.log("hello")`)},
+		"404.html":     {Data: []byte(`<html>Not found</html>`)},
+
+		"article/getting-started.html": {Data: []byte(`<html>Article</html>`)},
+	}
+	store := db.NewInMemoryDB()
+	router, err := NewRouter(cfg, store, handlers.NewHandler(store, cfg.Auth), []byte("openapi: 3.0.0\n"), frontendRoot)
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+	return router
+}
+
+// get sends a GET request for path to the router
+func get(router http.Handler, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSecurityHeadersOnStaticRoutes(t *testing.T) {
+	router := setupTestRouter(t, config.Default())
+
+	sum := sha256.Sum256([]byte(inlineScript))
+	hash := "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+
+	for _, path := range []string{"/", "/_next/app.js"} {
+		rr := get(router, path)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d", path, http.StatusOK, rr.Code)
+		}
+
+		// The inline script of the page is allowed by hash, other inline scripts are not
+		policy := rr.Header().Get("Content-Security-Policy")
+		if !strings.Contains(policy, "script-src 'self' "+hash+";") {
+			t.Errorf("%s: expected only script hash %s in script-src, got %q", path, hash, policy)
+		}
+		if !strings.Contains(policy, "frame-ancestors 'none'") {
+			t.Errorf("%s: expected frame-ancestors in policy, got %q", path, policy)
+		}
+
+		if options := rr.Header().Get("X-Content-Type-Options"); options != "nosniff" {
+			t.Errorf("%s: expected nosniff, got %q", path, options)
+		}
+		if frame := rr.Header().Get("X-Frame-Options"); frame != "DENY" {
+			t.Errorf("%s: expected X-Frame-Options DENY, got %q", path, frame)
+		}
+		if referrer := rr.Header().Get("Referrer-Policy"); referrer != "strict-origin-when-cross-origin" {
+			t.Errorf("%s: expected Referrer-Policy, got %q", path, referrer)
+		}
+	}
+}
+
+func TestSecurityHeadersOnAPIRoutes(t *testing.T) {
+	router := setupTestRouter(t, config.Default())
+
+	// Successful and failed API responses get the headers
+	for _, path := range []string{"/api/tags", "/api/user", "/openapi.yml"} {
+		rr := get(router, path)
+
+		if policy := rr.Header().Get("Content-Security-Policy"); policy != "default-src 'none'; frame-ancestors 'none'" {
+			t.Errorf("%s: expected API policy, got %q", path, policy)
+		}
+		if options := rr.Header().Get("X-Content-Type-Options"); options != "nosniff" {
+			t.Errorf("%s: expected nosniff, got %q", path, options)
+		}
+		if frame := rr.Header().Get("X-Frame-Options"); frame != "DENY" {
+			t.Errorf("%s: expected X-Frame-Options DENY, got %q", path, frame)
+		}
+	}
+}
+
+func TestSecurityHeadersHSTS(t *testing.T) {
+	cfg := config.Default()
+	cfg.Security.HSTSMaxAge = 31536000
+	router := setupTestRouter(t, cfg)
+
+	for _, path := range []string{"/", "/api/tags"} {
+		rr := get(router, path)
+		if hsts := rr.Header().Get("Strict-Transport-Security"); hsts != "max-age=31536000" {
+			t.Errorf("%s: expected HSTS header, got %q", path, hsts)
+		}
+	}
+}
+
+func TestFrontendDeepLinks(t *testing.T) {
+	router := setupTestRouter(t, config.Default())
+
+	// Deep links into dynamic routes get the page of the route
+	rr := get(router, "/article/some-slug")
+	if rr.Code != http.StatusOK || rr.Body.String() != "<html>Article</html>" {
+		t.Errorf("Expected article page, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	// Unknown pages get the 404 page
+	rr = get(router, "/nothing/here")
+	if rr.Code != http.StatusNotFound || rr.Body.String() != "<html>Not found</html>" {
+		t.Errorf("Expected 404 page, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	// Unknown API routes keep their API errors
+	for _, path := range []string{"/api/unknown", "/api/articles/some-slug/unknown"} {
+		rr = get(router, path)
+		if rr.Code == http.StatusOK {
+			t.Errorf("%s: expected an error, got status %d", path, rr.Code)
+		}
+		if strings.Contains(rr.Body.String(), "<html>") {
+			t.Errorf("%s: expected no HTML, got %q", path, rr.Body.String())
+		}
+	}
+}
+
+func TestArticlePreviewTags(t *testing.T) {
+	cfg := config.Default()
+	cfg.AppURL = "https://conduit.example.com"
+	frontendRoot := fstest.MapFS{
+		"article/getting-started.html": {Data: []byte(`<html><head><title>Generic</title></head><body></body></html>`)},
+	}
+	store := setupMetaStore(t)
+	router, err := NewRouter(cfg, store, handlers.NewHandler(store, cfg.Auth), nil, frontendRoot)
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	rr := get(router, "/article/hello-world")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	for _, expected := range []string{"<title>Hello World | Real World Example</title>", `<meta property="og:url" content="https://conduit.example.com/article/hello-world"/>`} {
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("Expected %s in page, got:\n%s", expected, rr.Body.String())
+		}
+	}
+}
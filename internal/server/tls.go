@@ -0,0 +1,136 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// certCheckInterval is how often the certificate files are checked for changes
+const certCheckInterval = 10 * time.Second
+
+// CertReloader serves a certificate from a certificate and key file and
+// reloads it when the files change, so that renewed certificates are picked
+// up without a restart
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	now      func() time.Time
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	certStamp fileStamp
+	keyStamp  fileStamp
+	checkedAt time.Time
+}
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewCertReloader loads the certificate and key pair
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	c := &CertReloader{certFile: certFile, keyFile: keyFile, now: time.Now}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload loads the certificate and key files again
+func (c *CertReloader) Reload() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.load()
+}
+
+// load reads the files, the caller holds the lock
+func (c *CertReloader) load() error {
+	certStamp, err := stat(c.certFile)
+	if err != nil {
+		return err
+	}
+	keyStamp, err := stat(c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate: %w", err)
+	}
+
+	c.cert = &cert
+	c.certStamp = certStamp
+	c.keyStamp = keyStamp
+	c.checkedAt = c.now()
+	return nil
+}
+
+// GetCertificate returns the current certificate, reloading it first if the
+// files changed. It is meant for tls.Config.GetCertificate.
+func (c *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := c.now(); now.Sub(c.checkedAt) >= certCheckInterval {
+		c.checkedAt = now
+		if c.changed() {
+			// Keep serving the old certificate while the new files are
+			// incomplete, e.g. the certificate is written before the key
+			if err := c.load(); err != nil {
+				log.Printf("Error reloading TLS certificate: %v", err)
+			} else {
+				log.Printf("Reloaded TLS certificate from %s", c.certFile)
+			}
+		}
+	}
+
+	return c.cert, nil
+}
+
+// changed reports whether the files differ from the loaded ones
+func (c *CertReloader) changed() bool {
+	certStamp, certErr := stat(c.certFile)
+	keyStamp, keyErr := stat(c.keyFile)
+	if certErr != nil || keyErr != nil {
+		return false
+	}
+	return certStamp != c.certStamp || keyStamp != c.keyStamp
+}
+
+// stat returns the stamp of a file
+func stat(name string) (fileStamp, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// RedirectHandler redirects requests to the same URL over HTTPS on httpsPort
+func RedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			// No port in the Host header
+			host = strings.Trim(r.Host, "[]")
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+
+		// 308 keeps the method and body of the request
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/denga/go-real-world-example/internal/config"
+)
+
+// writeCertificate writes a self-signed certificate for 127.0.0.1 with the
+// given common name and its key to dir, and returns the certificate
+func writeCertificate(t *testing.T, dir, commonName string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(filepath.Join(dir, "cert.pem"), certPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "key.pem"), keyPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+// touch moves the modification time of the certificate files forward, since
+// rewrites within the file system's timestamp resolution look unchanged
+func touch(t *testing.T, dir string, at time.Time) {
+	t.Helper()
+	for _, name := range []string{"cert.pem", "key.pem"} {
+		if err := os.Chtimes(filepath.Join(dir, name), at, at); err != nil {
+			t.Fatalf("Failed to touch %s: %v", name, err)
+		}
+	}
+}
+
+// commonName returns the common name of the certificate the reloader serves
+func commonName(t *testing.T, reloader *CertReloader) string {
+	t.Helper()
+	cert, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("Failed to get certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	writeCertificate(t, dir, "first")
+
+	reloader, err := NewCertReloader(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"))
+	if err != nil {
+		t.Fatalf("Failed to load certificate: %v", err)
+	}
+	now := time.Now()
+	reloader.now = func() time.Time { return now }
+
+	if name := commonName(t, reloader); name != "first" {
+		t.Fatalf("Expected certificate first, got %s", name)
+	}
+
+	// A renewed certificate is picked up at the next check
+	writeCertificate(t, dir, "second")
+	touch(t, dir, now.Add(time.Minute))
+	if name := commonName(t, reloader); name != "first" {
+		t.Errorf("Expected certificate first before the check interval, got %s", name)
+	}
+	now = now.Add(certCheckInterval)
+	if name := commonName(t, reloader); name != "second" {
+		t.Errorf("Expected certificate second after the check interval, got %s", name)
+	}
+
+	// A broken key keeps the current certificate
+	if err := os.WriteFile(filepath.Join(dir, "key.pem"), []byte("incomplete"), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	touch(t, dir, now.Add(2*time.Minute))
+	now = now.Add(certCheckInterval)
+	if name := commonName(t, reloader); name != "second" {
+		t.Errorf("Expected certificate second to stay, got %s", name)
+	}
+}
+
+func TestNewCertReloaderMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewCertReloader(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")); err == nil {
+		t.Error("Expected error for missing files, got nil")
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		host      string
+		httpsPort string
+		expected  string
+	}{
+		{"example.com", "443", "https://example.com/articles?tag=go"},
+		{"example.com:80", "443", "https://example.com/articles?tag=go"},
+		{"example.com:8080", "8443", "https://example.com:8443/articles?tag=go"},
+		{"[::1]:8080", "8443", "https://[::1]:8443/articles?tag=go"},
+		{"[::1]", "8443", "https://[::1]:8443/articles?tag=go"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/articles?tag=go", nil)
+		req.Host = tt.host
+		rr := httptest.NewRecorder()
+		RedirectHandler(tt.httpsPort).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusPermanentRedirect {
+			t.Errorf("%s: expected status %d, got %d", tt.host, http.StatusPermanentRedirect, rr.Code)
+		}
+		if location := rr.Header().Get("Location"); location != tt.expected {
+			t.Errorf("%s: expected redirect to %s, got %s", tt.host, tt.expected, location)
+		}
+	}
+}
+
+// startTLSServer serves an empty page over HTTPS with the TLS configuration
+// and returns its URL
+func startTLSServer(t *testing.T, cfg config.TLSConfig) string {
+	t.Helper()
+
+	tlsConfig, err := NewTLSConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create TLS config: %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	srv := &http.Server{
+		Handler:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		TLSConfig: tlsConfig,
+	}
+	go srv.ServeTLS(listener, "", "")
+	t.Cleanup(func() { srv.Close() })
+
+	return "https://" + listener.Addr().String()
+}
+
+// tlsClient returns a client trusting cert that speaks at most maxVersion
+func tlsClient(cert *x509.Certificate, maxVersion uint16) *http.Client {
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots, MaxVersion: maxVersion},
+		ForceAttemptHTTP2: true,
+	}}
+}
+
+func TestTLSServerHTTP2(t *testing.T) {
+	dir := t.TempDir()
+	cert := writeCertificate(t, dir, "conduit")
+	url := startTLSServer(t, config.TLSConfig{
+		CertFile:   filepath.Join(dir, "cert.pem"),
+		KeyFile:    filepath.Join(dir, "key.pem"),
+		MinVersion: tls.VersionTLS12,
+	})
+
+	resp, err := tlsClient(cert, 0).Get(url)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.ProtoMajor != 2 {
+		t.Errorf("Expected HTTP/2, got %s", resp.Proto)
+	}
+	if resp.TLS == nil || resp.TLS.PeerCertificates[0].Subject.CommonName != "conduit" {
+		t.Error("Expected the configured certificate")
+	}
+}
+
+func TestTLSServerMinVersion(t *testing.T) {
+	dir := t.TempDir()
+	cert := writeCertificate(t, dir, "conduit")
+	url := startTLSServer(t, config.TLSConfig{
+		CertFile:   filepath.Join(dir, "cert.pem"),
+		KeyFile:    filepath.Join(dir, "key.pem"),
+		MinVersion: tls.VersionTLS13,
+	})
+
+	// Clients limited to TLS 1.2 are rejected
+	if resp, err := tlsClient(cert, tls.VersionTLS12).Get(url); err == nil {
+		resp.Body.Close()
+		t.Error("Expected TLS 1.2 handshake to fail")
+	}
+
+	resp, err := tlsClient(cert, 0).Get(url)
+	if err != nil {
+		t.Fatalf("Failed to connect with TLS 1.3: %v", err)
+	}
+	resp.Body.Close()
+	if resp.TLS.Version != tls.VersionTLS13 {
+		t.Errorf("Expected TLS 1.3, got %x", resp.TLS.Version)
+	}
+}
@@ -1,44 +1,163 @@
 package util
 
 import (
-	"regexp"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
 	"strings"
-)
+	"unicode"
 
-var (
-	// nonAlphanumericRegex matches any character that is not a letter, number, or space
-	nonAlphanumericRegex = regexp.MustCompile(`[^a-zA-Z0-9\s]`)
-	// multipleSpacesRegex matches multiple spaces
-	multipleSpacesRegex = regexp.MustCompile(`\s+`)
+	"golang.org/x/text/unicode/norm"
 )
 
-// GenerateSlug generates a URL-friendly slug from a string
-func GenerateSlug(s string) string {
-	// Convert to lowercase
-	s = strings.ToLower(s)
+// MaxSlugLength is the maximum length of a slug in bytes
+const MaxSlugLength = 80
+
+// fallbackSlugPrefix starts the slugs of titles without usable characters
+const fallbackSlugPrefix = "post-"
+
+// reservedSlugs can't be used as article slugs because they collide with
+// routes, like GET /api/articles/feed
+var reservedSlugs = map[string]bool{
+	"feed":  true,
+	"new":   true,
+	"edit":  true,
+	"index": true,
+}
+
+// transliterations maps lowercase letters of common scripts to ASCII. Letters
+// that decompose into a base letter and marks, like "é" or "ế", only need an
+// entry if they are written differently, like "ä" as "ae". Letters of other
+// scripts, like Chinese or Japanese, have no entry and are dropped.
+var transliterations = buildTransliterations(map[string]string{
+	// Latin
+	"ä": "ae", "æ": "ae", "đð": "d", "ħ": "h", "ı": "i", "ĳ": "ij",
+	"ŀł": "l", "ŉ": "n", "öøœ": "oe", "ß": "ss", "ŧ": "t", "þ": "th",
+	"ü": "ue",
+	// Greek
+	"α": "a", "β": "v", "γ": "g", "δ": "d", "ε": "e", "ζ": "z", "η": "i",
+	"θ": "th", "ι": "i", "κ": "k", "λ": "l", "μ": "m", "ν": "n", "ξ": "x",
+	"ο": "o", "π": "p", "ρ": "r", "σς": "s", "τ": "t", "υ": "y", "φ": "f",
+	"χ": "ch", "ψ": "ps", "ω": "o",
+	// Cyrillic
+	"а": "a", "б": "b", "в": "v", "гґ": "g", "д": "d", "еёэ": "e",
+	"є": "ye", "ж": "zh", "з": "z", "иі": "i", "ї": "yi", "й": "y",
+	"к": "k", "л": "l", "м": "m", "н": "n", "о": "o", "п": "p",
+	"р": "r", "с": "s", "т": "t", "у": "u", "ф": "f", "х": "kh",
+	"ц": "ts", "ч": "ch", "ш": "sh", "щ": "shch", "ъь": "", "ы": "y",
+	"ю": "yu", "я": "ya",
+})
+
+// buildTransliterations expands a table keyed by groups of letters
+func buildTransliterations(groups map[string]string) map[rune]string {
+	table := make(map[rune]string)
+	for letters, replacement := range groups {
+		for _, r := range letters {
+			table[r] = replacement
+		}
+	}
+	return table
+}
+
+// Slugify turns a string into lowercase ASCII words joined by hyphens.
+// Letters of common scripts are transliterated, other letters decomposed into
+// their base letter and combining marks, and the marks and other characters
+// dropped. Long results are cut at a word boundary to at most MaxSlugLength
+// bytes. The result is empty if nothing usable remains.
+func Slugify(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	write := func(r rune) {
+		var word string
+		switch {
+		case r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word = string(r)
+		case unicode.Is(unicode.Mn, r) || r == '\'' || r == '’':
+			// Accents of decomposed letters, like "é", and apostrophes
+			// don't split words
+			return
+		default:
+			word = transliterations[r]
+		}
+
+		if word == "" {
+			// Separators and dropped characters end a word
+			if _, ok := transliterations[r]; !ok {
+				pendingHyphen = true
+			}
+			return
+		}
+		if pendingHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingHyphen = false
+		b.WriteString(word)
+	}
+
+	for _, r := range strings.ToLower(s) {
+		// Full-width forms, like "Ａ" in Japanese text, are ASCII in disguise
+		if r >= '！' && r <= '～' {
+			r = unicode.ToLower(r - '！' + '!')
+		}
 
-	// Remove non-alphanumeric characters
-	s = nonAlphanumericRegex.ReplaceAllString(s, "")
+		if _, ok := transliterations[r]; ok || r <= unicode.MaxASCII {
+			write(r)
+			continue
+		}
+		for _, decomposed := range norm.NFD.String(string(r)) {
+			write(decomposed)
+		}
+	}
 
-	// Replace spaces with hyphens
-	s = multipleSpacesRegex.ReplaceAllString(s, " ")
-	s = strings.TrimSpace(s)
-	s = strings.ReplaceAll(s, " ", "-")
+	return truncateSlug(b.String(), MaxSlugLength)
+}
 
-	return s
+// truncateSlug cuts a slug to at most max bytes, at a hyphen if possible
+func truncateSlug(slug string, max int) string {
+	if len(slug) <= max {
+		return slug
+	}
+	if i := strings.LastIndexByte(slug[:max+1], '-'); i > 0 {
+		return slug[:i]
+	}
+	return slug[:max]
 }
 
-// GenerateUniqueSlug generates a unique slug by appending a number if necessary
+// GenerateSlug generates a URL-friendly slug from a string. Strings without
+// usable characters, like titles in scripts without transliteration, get a
+// short slug derived from a hash of the string.
+func GenerateSlug(s string) string {
+	if slug := Slugify(s); slug != "" {
+		return slug
+	}
+	sum := sha256.Sum256([]byte(s))
+	return fallbackSlugPrefix + hex.EncodeToString(sum[:4])
+}
+
+// IsReservedSlug reports whether a slug collides with a route
+func IsReservedSlug(slug string) bool {
+	return reservedSlugs[slug]
+}
+
+// GenerateUniqueSlug generates a unique slug by appending a number if
+// necessary. Reserved slugs are never returned.
 func GenerateUniqueSlug(title string, exists func(string) bool) string {
-	baseSlug := GenerateSlug(title)
+	return UniqueSlug(GenerateSlug(title), exists)
+}
+
+// UniqueSlug returns baseSlug, or baseSlug with the lowest numeric suffix,
+// starting at "-1", for which exists is false. Reserved slugs are skipped,
+// and the base is shortened so that the result stays within MaxSlugLength.
+func UniqueSlug(baseSlug string, exists func(string) bool) string {
 	slug := baseSlug
 	counter := 1
 
 	// Keep incrementing counter until we find a unique slug
-	for exists(slug) {
-		slug = baseSlug + "-" + string(rune('0'+counter))
+	for IsReservedSlug(slug) || exists(slug) {
+		suffix := "-" + strconv.Itoa(counter)
+		slug = truncateSlug(baseSlug, MaxSlugLength-len(suffix)) + suffix
 		counter++
 	}
 
 	return slug
-}
\ No newline at end of file
+}
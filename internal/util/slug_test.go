@@ -1,6 +1,8 @@
 package util
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -31,14 +33,64 @@ func TestGenerateSlug(t *testing.T) {
 			expected: "hello-world",
 		},
 		{
-			name:     "Empty string",
-			input:    "",
-			expected: "",
+			name:     "Title with apostrophes",
+			input:    "Don't panic, it’s Go",
+			expected: "dont-panic-its-go",
 		},
 		{
-			name:     "Only special characters",
-			input:    "!@#$%^&*()",
-			expected: "",
+			name:     "German title",
+			input:    "Größere Äpfel für Übermorgen",
+			expected: "groessere-aepfel-fuer-uebermorgen",
+		},
+		{
+			name:     "French title",
+			input:    "Où est la bibliothèque? Ça va, Noël!",
+			expected: "ou-est-la-bibliotheque-ca-va-noel",
+		},
+		{
+			name:     "Decomposed accents",
+			input:    "Cafe\u0301 Prie\u0300re",
+			expected: "cafe-priere",
+		},
+		{
+			name:     "Polish and Scandinavian letters",
+			input:    "Łódź og Ærø",
+			expected: "lodz-og-aeroe",
+		},
+		{
+			name:     "Vietnamese title",
+			input:    "Tiếng Việt ở Hồ Chí Minh và Đà Nẵng",
+			expected: "tieng-viet-o-ho-chi-minh-va-da-nang",
+		},
+		{
+			name:     "Czech title",
+			input:    "Příliš žluťoučký kůň úpěl ďábelské ódy",
+			expected: "prilis-zlutoucky-kun-upel-dabelske-ody",
+		},
+		{
+			name:     "Pinyin with carons",
+			input:    "Nǐ hǎo, Běijīng",
+			expected: "ni-hao-beijing",
+		},
+		{
+			name:     "Russian title",
+			input:    "Привет, мир! Щука и ёж",
+			expected: "privet-mir-shchuka-i-ezh",
+		},
+		{
+			name:     "Greek title",
+			input:    "Καλημέρα κόσμε",
+			expected: "kalimera-kosme",
+		},
+		{
+			name:     "Full-width characters",
+			input:    "Ｇｏ　１２３",
+			expected: "go-123",
+		},
+		{
+			name:     "Mixed scripts",
+			input:    "Go 入門ガイド 2024",
+			expected: "go-2024",
 		},
 	}
 
@@ -52,12 +104,81 @@ func TestGenerateSlug(t *testing.T) {
 	}
 }
 
+func TestGenerateSlugFallback(t *testing.T) {
+	// Titles without usable characters get a hashed slug
+	for _, input := range []string{"", "!@#$%^&*()", "日本語の記事", "🎉🎉"} {
+		slug := GenerateSlug(input)
+		if !strings.HasPrefix(slug, "post-") || len(slug) != len("post-")+8 {
+			t.Errorf("GenerateSlug(%q) = %q, expected post- with 8 hex digits", input, slug)
+		}
+		if again := GenerateSlug(input); again != slug {
+			t.Errorf("GenerateSlug(%q) is not stable: %q and %q", input, slug, again)
+		}
+	}
+
+	if GenerateSlug("日本語の記事") == GenerateSlug("中文文章") {
+		t.Error("Expected different titles to get different fallback slugs")
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	// Unlike GenerateSlug, Slugify has no fallback
+	if slug := Slugify("!@#$%^&*()"); slug != "" {
+		t.Errorf("Slugify of only special characters = %q, expected empty", slug)
+	}
+	if slug := Slugify("日本語"); slug != "" {
+		t.Errorf("Slugify of Japanese = %q, expected empty", slug)
+	}
+}
+
+func TestGenerateSlugMaxLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Cut at word boundary",
+			input:    strings.Repeat("word ", 30),
+			expected: strings.TrimSuffix(strings.Repeat("word-", 16), "-"),
+		},
+		{
+			name:     "Word ending at the limit",
+			input:    strings.Repeat("a", 75) + " bcde fgh",
+			expected: strings.Repeat("a", 75) + "-bcde",
+		},
+		{
+			name:     "Single long word",
+			input:    strings.Repeat("x", 100),
+			expected: strings.Repeat("x", MaxSlugLength),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GenerateSlug(tt.input)
+			if result != tt.expected {
+				t.Errorf("GenerateSlug(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+			if len(result) > MaxSlugLength {
+				t.Errorf("Expected at most %d bytes, got %d", MaxSlugLength, len(result))
+			}
+		})
+	}
+}
+
 func TestGenerateUniqueSlug(t *testing.T) {
 	existingSlugs := map[string]bool{
 		"hello-world":   true,
 		"hello-world-1": true,
 	}
 
+	// "popular" is taken, and so are its suffixes up to -10
+	popularSlugs := map[string]bool{"popular": true}
+	for i := 1; i <= 10; i++ {
+		popularSlugs["popular-"+strconv.Itoa(i)] = true
+	}
+
 	tests := []struct {
 		name     string
 		input    string
@@ -88,6 +209,22 @@ func TestGenerateUniqueSlug(t *testing.T) {
 			},
 			expected: "hello-world-1-1",
 		},
+		{
+			name:  "More than nine duplicates",
+			input: "Popular",
+			exists: func(s string) bool {
+				return popularSlugs[s]
+			},
+			expected: "popular-11",
+		},
+		{
+			name:  "Reserved slug",
+			input: "Feed",
+			exists: func(s string) bool {
+				return existingSlugs[s]
+			},
+			expected: "feed-1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -98,4 +235,14 @@ func TestGenerateUniqueSlug(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestUniqueSlugLongBase(t *testing.T) {
+	base := strings.Repeat("x", MaxSlugLength)
+	slug := UniqueSlug(base, func(s string) bool { return s == base })
+
+	expected := strings.Repeat("x", MaxSlugLength-2) + "-1"
+	if slug != expected {
+		t.Errorf("UniqueSlug of a base at the limit = %q, expected %q", slug, expected)
+	}
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTags is the maximum number of tags of an article
+const MaxTags = 10
+
+// MaxFollowedTags is the maximum number of tags a user can follow
+const MaxFollowedTags = 100
+
+// MaxTagLength is the maximum length of a tag in characters
+const MaxTagLength = 32
+
+var (
+	// ErrTooManyTags is returned for tag lists with more than MaxTags tags
+	ErrTooManyTags = errors.New("too many tags")
+	// ErrTagTooLong is returned for tags longer than MaxTagLength
+	ErrTagTooLong = errors.New("tag too long")
+)
+
+// NormalizeTag lowercases a tag, trims it and collapses inner whitespace to
+// single spaces, so that "  Go  Lang" and "go lang" are the same tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// NormalizeTags normalizes a tag list, dropping empty and duplicate tags
+// while keeping the order of the rest
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, ErrTagTooLong
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > MaxTags {
+		return nil, ErrTooManyTags
+	}
+	return normalized, nil
+}
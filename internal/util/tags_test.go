@@ -0,0 +1,69 @@
+package util
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+		err      error
+	}{
+		{
+			name:     "Case and whitespace",
+			input:    []string{"  Go", "Web\tDev ", "REST  API"},
+			expected: []string{"go", "web dev", "rest api"},
+		},
+		{
+			name:     "Empty and duplicate tags",
+			input:    []string{"go", "", "  ", "GO", "web"},
+			expected: []string{"go", "web"},
+		},
+		{
+			name:     "Nil",
+			input:    nil,
+			expected: []string{},
+		},
+		{
+			name:     "Longest tag",
+			input:    []string{strings.Repeat("ä", MaxTagLength)},
+			expected: []string{strings.Repeat("ä", MaxTagLength)},
+		},
+		{
+			name:  "Tag too long",
+			input: []string{strings.Repeat("a", MaxTagLength+1)},
+			err:   ErrTagTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		tags, err := NormalizeTags(tt.input)
+		if err != tt.err {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.err, err)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(tags, tt.expected) {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, tags)
+		}
+	}
+}
+
+func TestNormalizeTagsCount(t *testing.T) {
+	var tags []string
+	for i := 0; i < MaxTags; i++ {
+		tags = append(tags, "tag"+strconv.Itoa(i))
+	}
+
+	// Duplicates don't count towards the limit
+	if _, err := NormalizeTags(append(tags, "TAG0")); err != nil {
+		t.Errorf("Expected %d tags to be allowed, got %v", MaxTags, err)
+	}
+	if _, err := NormalizeTags(append(tags, "one more")); err != ErrTooManyTags {
+		t.Errorf("Expected ErrTooManyTags, got %v", err)
+	}
+}